@@ -0,0 +1,270 @@
+package baseline
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/Siddhant-K-code/sentinel-ai/internal/deadcode"
+	"github.com/Siddhant-K-code/sentinel-ai/internal/security"
+)
+
+// Version is the baseline file format version
+const Version = 1
+
+// Baseline is a committed snapshot of accepted findings. Entries are
+// matched by fingerprint, so they survive unrelated line shifts.
+type Baseline struct {
+	Version  int     `yaml:"version"`
+	Security []Entry `yaml:"security,omitempty"`
+	DeadCode []Entry `yaml:"deadcode,omitempty"`
+}
+
+// Entry is one accepted finding or dead-code symbol
+type Entry struct {
+	Fingerprint   string `yaml:"fingerprint"`
+	Tool          string `yaml:"tool,omitempty"`
+	Rule          string `yaml:"rule,omitempty"`
+	Symbol        string `yaml:"symbol,omitempty"`
+	File          string `yaml:"file"`
+	Message       string `yaml:"message,omitempty"`
+	Justification string `yaml:"justification,omitempty"`
+}
+
+// New snapshots the findings of a scan. Either input may be nil.
+func New(results []security.ScanResult, dead *deadcode.DeadCodeResult) *Baseline {
+	b := &Baseline{Version: Version}
+
+	for _, result := range results {
+		for _, f := range result.Findings {
+			if !f.Actionable() {
+				continue
+			}
+			tool := f.Tool
+			if tool == "" {
+				tool = result.Tool
+			}
+			b.Security = append(b.Security, Entry{
+				Fingerprint: f.Fingerprint,
+				Tool:        tool,
+				Rule:        f.RuleID,
+				File:        filepath.ToSlash(f.File),
+				Message:     f.Message,
+			})
+		}
+	}
+
+	if dead != nil {
+		for _, sym := range dead.Symbols {
+			b.DeadCode = append(b.DeadCode, Entry{
+				Fingerprint: sym.Fingerprint(),
+				Symbol:      fmt.Sprintf("%s %s.%s", sym.Kind, sym.Package, sym.Name),
+				File:        filepath.ToSlash(sym.File),
+			})
+		}
+	}
+
+	b.sort()
+	return b
+}
+
+// Load reads a baseline file
+func Load(path string) (*Baseline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var b Baseline
+	if err := yaml.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("parse baseline %s: %w", path, err)
+	}
+	if b.Version != Version {
+		return nil, fmt.Errorf("baseline %s: unsupported version %d", path, b.Version)
+	}
+	for _, e := range append(append([]Entry{}, b.Security...), b.DeadCode...) {
+		if e.Fingerprint == "" {
+			return nil, errors.New("baseline entry without fingerprint in " + path)
+		}
+	}
+	return &b, nil
+}
+
+// Save writes the baseline with entries in a stable order so that
+// regenerating it produces small diffs
+func (b *Baseline) Save(path string) error {
+	b.sort()
+
+	data, err := yaml.Marshal(b)
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// RequireSecretKey fails when the baseline has secret entries and
+// security.SecretKeyEnv is unset. Secret fingerprints are keyed hashes,
+// which only match across runs and clones under the same key.
+func (b *Baseline) RequireSecretKey() error {
+	if b == nil || security.SecretKeySet() {
+		return nil
+	}
+	for _, e := range b.Security {
+		if e.Tool == "secrets" {
+			return fmt.Errorf("baseline has secret findings (%s in %s); set %s so their fingerprints match across runs", e.Rule, e.File, security.SecretKeyEnv)
+		}
+	}
+	return nil
+}
+
+// KeepJustifications copies justifications from a previous baseline to
+// entries that are still present
+func (b *Baseline) KeepJustifications(prev *Baseline) {
+	if prev == nil {
+		return
+	}
+	reasons := make(map[string]string)
+	for _, e := range append(append([]Entry{}, prev.Security...), prev.DeadCode...) {
+		if e.Justification != "" {
+			reasons[e.Fingerprint] = e.Justification
+		}
+	}
+	for _, entries := range [][]Entry{b.Security, b.DeadCode} {
+		for i := range entries {
+			if entries[i].Justification == "" {
+				entries[i].Justification = reasons[entries[i].Fingerprint]
+			}
+		}
+	}
+}
+
+// FilterSecurity removes baselined findings from results and marks the
+// rest as new. Baseline entries that their tool no longer reports are
+// returned as fixed and added to the results as absent findings, so
+// SARIF consumers see them closed.
+func (b *Baseline) FilterSecurity(results []security.ScanResult) []Entry {
+	known := index(b.Security)
+	seen := make(map[string]bool)
+
+	for ri := range results {
+		kept := results[ri].Findings[:0]
+		for _, f := range results[ri].Findings {
+			if known[f.Fingerprint] {
+				seen[f.Fingerprint] = true
+				continue
+			}
+			f.BaselineState = "new"
+			kept = append(kept, f)
+		}
+		results[ri].Findings = kept
+	}
+
+	var fixed []Entry
+	for _, e := range b.Security {
+		if seen[e.Fingerprint] {
+			continue
+		}
+
+		// Only a tool that ran cleanly can tell that a finding is gone
+		i := resultFor(results, e.Tool)
+		if i < 0 || results[i].Error != "" {
+			continue
+		}
+		fixed = append(fixed, e)
+
+		absent := security.Finding{
+			RuleID:        e.Rule,
+			Message:       e.Message,
+			File:          filepath.FromSlash(e.File),
+			Tool:          e.Tool,
+			Fingerprint:   e.Fingerprint,
+			BaselineState: "absent",
+		}
+		results[i].Findings = append(results[i].Findings, absent)
+	}
+	return fixed
+}
+
+// FilterDeadCode removes baselined symbols and returns the entries whose
+// symbols are gone
+func (b *Baseline) FilterDeadCode(result *deadcode.DeadCodeResult) []Entry {
+	if result == nil {
+		return nil
+	}
+
+	known := index(b.DeadCode)
+	seen := make(map[string]bool)
+
+	kept := result.Symbols[:0]
+	for _, sym := range result.Symbols {
+		fp := sym.Fingerprint()
+		if known[fp] {
+			seen[fp] = true
+			continue
+		}
+		kept = append(kept, sym)
+	}
+	result.Symbols = kept
+
+	// A failed detection cannot tell that a symbol is gone
+	if result.Error != "" {
+		return nil
+	}
+
+	var fixed []Entry
+	for _, e := range b.DeadCode {
+		if !seen[e.Fingerprint] {
+			fixed = append(fixed, e)
+		}
+	}
+	return fixed
+}
+
+// index returns the set of fingerprints in entries
+func index(entries []Entry) map[string]bool {
+	set := make(map[string]bool, len(entries))
+	for _, e := range entries {
+		set[e.Fingerprint] = true
+	}
+	return set
+}
+
+// resultFor finds the scan result that reports a tool's findings, or -1
+func resultFor(results []security.ScanResult, tool string) int {
+	for i, r := range results {
+		if r.Tool == tool {
+			return i
+		}
+		for _, f := range r.Findings {
+			if f.Tool == tool {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// sort orders entries by file, then rule or symbol, then fingerprint
+func (b *Baseline) sort() {
+	for _, entries := range [][]Entry{b.Security, b.DeadCode} {
+		sort.Slice(entries, func(i, j int) bool {
+			a, c := entries[i], entries[j]
+			if a.File != c.File {
+				return a.File < c.File
+			}
+			if a.Rule+a.Symbol != c.Rule+c.Symbol {
+				return a.Rule+a.Symbol < c.Rule+c.Symbol
+			}
+			return a.Fingerprint < c.Fingerprint
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package baseline
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Siddhant-K-code/sentinel-ai/internal/deadcode"
+	"github.com/Siddhant-K-code/sentinel-ai/internal/security"
+)
+
+func scan() ([]security.ScanResult, *deadcode.DeadCodeResult) {
+	results := []security.ScanResult{{
+		Tool: "semgrep",
+		Findings: []security.Finding{
+			{RuleID: "xss", File: "web/handler.go", Line: 10, Tool: "semgrep", Fingerprint: "aaa"},
+			{RuleID: "sqli", File: "db/query.go", Line: 4, Tool: "semgrep", Fingerprint: "bbb"},
+		},
+	}}
+	dead := &deadcode.DeadCodeResult{Symbols: []deadcode.Symbol{
+		{Name: "unused", Kind: "func", Package: "util", File: "util.go", Line: 3},
+	}}
+	return results, dead
+}
+
+func TestSaveLoad(t *testing.T) {
+	results, dead := scan()
+	b := New(results, dead)
+	b.Security[0].Justification = "input is escaped by the template"
+
+	path := filepath.Join(t.TempDir(), ".sentinel", "baseline.yaml")
+	if err := b.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Entries are sorted by file, so db/ comes before web/
+	if strings.Index(string(data), "db/query.go") > strings.Index(string(data), "web/handler.go") {
+		t.Errorf("Expected entries sorted by file:\n%s", data)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded.Security) != 2 || len(loaded.DeadCode) != 1 {
+		t.Fatalf("Expected 2 security and 1 dead-code entries, got %+v", loaded)
+	}
+
+	// Regenerating keeps justifications of entries that are still present
+	fresh := New(results, dead)
+	fresh.KeepJustifications(loaded)
+	justified := 0
+	for _, e := range fresh.Security {
+		if e.Justification != "" {
+			justified++
+		}
+	}
+	if justified != 1 {
+		t.Errorf("Expected 1 justification to be kept, got %d", justified)
+	}
+}
+
+func TestLoadRejectsUnknownVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "baseline.yaml")
+	if err := os.WriteFile(path, []byte("version: 2\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Error("Expected error for unsupported version")
+	}
+}
+
+func TestFilter(t *testing.T) {
+	results, dead := scan()
+	b := New(results, dead)
+
+	// Next run: sqli is fixed, a new finding appears, dead code unchanged
+	results = []security.ScanResult{{
+		Tool: "semgrep",
+		Findings: []security.Finding{
+			{RuleID: "xss", File: "web/handler.go", Line: 12, Tool: "semgrep", Fingerprint: "aaa"},
+			{RuleID: "ssrf", File: "web/fetch.go", Line: 8, Tool: "semgrep", Fingerprint: "ccc"},
+		},
+	}}
+	_, dead = scan()
+
+	fixed := b.FilterSecurity(results)
+	if len(fixed) != 1 || fixed[0].Rule != "sqli" {
+		t.Fatalf("Expected sqli to be fixed, got %+v", fixed)
+	}
+
+	findings := results[0].Findings
+	if len(findings) != 2 {
+		t.Fatalf("Expected the new finding and the absent one, got %+v", findings)
+	}
+	if findings[0].RuleID != "ssrf" || findings[0].BaselineState != "new" {
+		t.Errorf("Expected new ssrf finding, got %+v", findings[0])
+	}
+	if findings[1].RuleID != "sqli" || findings[1].BaselineState != "absent" {
+		t.Errorf("Expected absent sqli finding, got %+v", findings[1])
+	}
+
+	if fixed := b.FilterDeadCode(dead); len(fixed) != 0 || len(dead.Symbols) != 0 {
+		t.Errorf("Expected baselined symbol to be dropped, got %+v fixed %+v", dead.Symbols, fixed)
+	}
+}
+
+func TestFilterFailedToolFixesNothing(t *testing.T) {
+	results, _ := scan()
+	b := New(results, nil)
+
+	failed := []security.ScanResult{{Tool: "semgrep", Error: "semgrep not found in PATH"}}
+	if fixed := b.FilterSecurity(failed); len(fixed) != 0 {
+		t.Errorf("Expected no fixed entries from a failed tool, got %+v", fixed)
+	}
+	if len(failed[0].Findings) != 0 {
+		t.Errorf("Expected no absent findings, got %+v", failed[0].Findings)
+	}
+}
+
+func TestRequireSecretKey(t *testing.T) {
+	t.Setenv(security.SecretKeyEnv, "")
+	results, _ := scan()
+	if err := New(results, nil).RequireSecretKey(); err != nil {
+		t.Errorf("Expected no key needed without secrets, got %v", err)
+	}
+
+	results[0].Findings = append(results[0].Findings, security.Finding{RuleID: "secrets/aws-access-key", File: "config.go", Line: 1, Tool: "secrets", Fingerprint: "ddd"})
+	b := New(results, nil)
+	if err := b.RequireSecretKey(); err == nil || !strings.Contains(err.Error(), security.SecretKeyEnv) {
+		t.Errorf("Expected an error naming %s, got %v", security.SecretKeyEnv, err)
+	}
+
+	t.Setenv(security.SecretKeyEnv, "ci-key")
+	if err := b.RequireSecretKey(); err != nil {
+		t.Errorf("Expected the key to satisfy the check, got %v", err)
+	}
+}
@@ -0,0 +1,106 @@
+package cache
+
+import (
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPutGet(t *testing.T) {
+	c, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key := Key("go", "test", "./...")
+	if _, ok := c.Get(key); ok {
+		t.Error("Expected miss on empty cache")
+	}
+
+	if err := c.Put(key, []byte("result")); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	data, ok := c.Get(key)
+	if !ok || string(data) != "result" {
+		t.Errorf("Expected hit with stored data, got %q %v", data, ok)
+	}
+
+	// Keys must not escape the cache directory
+	if err := c.Put("../../etc/passwd", nil); err == nil {
+		t.Error("Expected invalid key to be rejected")
+	}
+}
+
+func TestKeyIsUnambiguous(t *testing.T) {
+	if Key("ab", "c") == Key("a", "bc") {
+		t.Error("Expected different keys for different part boundaries")
+	}
+}
+
+func TestConcurrentPut(t *testing.T) {
+	c, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key := Key("shared")
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := c.Put(key, []byte("same content")); err != nil {
+				t.Errorf("Put failed: %v", err)
+			}
+			if data, ok := c.Get(key); ok && string(data) != "same content" {
+				t.Errorf("Observed partial entry %q", data)
+			}
+		}()
+	}
+	wg.Wait()
+
+	stats, err := c.Stats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Entries != 1 {
+		t.Errorf("Expected 1 entry and no leftover temp files, got %d", stats.Entries)
+	}
+}
+
+func TestPruneAndClear(t *testing.T) {
+	c, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	oldKey, newKey := Key("old"), Key("new")
+	_ = c.Put(oldKey, []byte("old"))
+	_ = c.Put(newKey, []byte("new"))
+
+	path, _ := c.path(oldKey)
+	past := time.Now().Add(-48 * time.Hour)
+	if err := os.Chtimes(path, past, past); err != nil {
+		t.Fatal(err)
+	}
+
+	removed, err := c.Prune(24 * time.Hour)
+	if err != nil || removed != 1 {
+		t.Fatalf("Expected 1 pruned entry, got %d (%v)", removed, err)
+	}
+	if _, ok := c.Get(oldKey); ok {
+		t.Error("Expected old entry to be pruned")
+	}
+
+	removed, err = c.Clear()
+	if err != nil || removed != 1 {
+		t.Fatalf("Expected 1 cleared entry, got %d (%v)", removed, err)
+	}
+
+	stats, _ := c.Stats()
+	if stats.Entries != 0 {
+		t.Errorf("Expected empty cache, got %d entries", stats.Entries)
+	}
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/Siddhant-K-code/sentinel-ai/internal/baseline"
+	"github.com/Siddhant-K-code/sentinel-ai/internal/engine"
+	"github.com/Siddhant-K-code/sentinel-ai/internal/policy"
+)
+
+func baselineCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "baseline",
+		Short: "Manage the baseline of accepted findings",
+		Long: `Manage a committed baseline of accepted security findings and dead code.
+With scan --baseline, only findings missing from the baseline are reported.`,
+	}
+
+	var (
+		repo       string
+		policyPath string
+		output     string
+		logOut     string
+	)
+
+	create := &cobra.Command{
+		Use:   "create",
+		Short: "Snapshot current findings into a baseline file",
+		Long: `Run security scanning and dead-code detection and record the fingerprint
+of every finding. Justifications in an existing baseline file are kept for
+findings that are still present.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+
+			pol, err := policy.Load(policyPath)
+			if err != nil {
+				return err
+			}
+
+			e, err := engine.New(ctx, engine.Options{
+				Repo:    repo,
+				Policy:  pol,
+				LogPath: logOut,
+			})
+			if err != nil {
+				return err
+			}
+
+			res, err := e.Scan(ctx, engine.ScanOpts{Security: true, DeadCode: true})
+			if err != nil {
+				return err
+			}
+
+			b := baseline.New(res.Security, res.DeadCode)
+			if err := b.RequireSecretKey(); err != nil {
+				return err
+			}
+			prev, err := baseline.Load(output)
+			if err != nil && !errors.Is(err, os.ErrNotExist) {
+				return err
+			}
+			b.KeepJustifications(prev)
+
+			if err := b.Save(output); err != nil {
+				return err
+			}
+			cmd.Printf("Wrote %d security and %d dead-code entries to %s\n", len(b.Security), len(b.DeadCode), output)
+			return nil
+		},
+	}
+	create.Flags().StringVar(&repo, "repo", ".", "Repository path to scan")
+	create.Flags().StringVar(&policyPath, "policy", "./.sentinel/policy.yaml", "Path to policy file")
+	create.Flags().StringVar(&output, "output", "./.sentinel/baseline.yaml", "Baseline file to write")
+	create.Flags().StringVar(&logOut, "log", "", "Log output file path")
+
+	cmd.AddCommand(create)
+
+	return cmd
+}
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/Siddhant-K-code/sentinel-ai/internal/cache"
+	"github.com/Siddhant-K-code/sentinel-ai/internal/policy"
+)
+
+func cacheCmd() *cobra.Command {
+	var (
+		dir        string
+		policyPath string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Inspect and manage the tool result cache",
+		Long: `Manage the on-disk cache of tool run results.
+Results are keyed by command, tool binary and a hash of the input files.`,
+	}
+
+	// openCache resolves the directory from the flag, then the policy
+	openCache := func() (*cache.Cache, policy.Policy, error) {
+		pol, err := policy.Load(policyPath)
+		if err != nil {
+			return nil, policy.Policy{}, err
+		}
+		if dir == "" {
+			dir = pol.Cache.Dir
+		}
+		c, err := cache.Open(dir)
+		return c, pol, err
+	}
+
+	stats := &cobra.Command{
+		Use:   "stats",
+		Short: "Show cache size and age",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, _, err := openCache()
+			if err != nil {
+				return err
+			}
+
+			s, err := c.Stats()
+			if err != nil {
+				return err
+			}
+
+			b, err := json.MarshalIndent(s, "", "  ")
+			if err != nil {
+				return err
+			}
+			cmd.Println(string(b))
+			return nil
+		},
+	}
+
+	var maxAge time.Duration
+	prune := &cobra.Command{
+		Use:   "prune",
+		Short: "Remove entries not used recently",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, pol, err := openCache()
+			if err != nil {
+				return err
+			}
+
+			age := maxAge
+			if age == 0 && pol.Cache.MaxAgeDays > 0 {
+				age = time.Duration(pol.Cache.MaxAgeDays) * 24 * time.Hour
+			}
+			if age == 0 {
+				age = 30 * 24 * time.Hour
+			}
+
+			removed, err := c.Prune(age)
+			if err != nil {
+				return err
+			}
+			cmd.Printf("Removed %d cache entries older than %s\n", removed, age)
+			return nil
+		},
+	}
+	prune.Flags().DurationVar(&maxAge, "max-age", 0, "Remove entries unused for longer than this (default from policy, else 720h)")
+
+	clear := &cobra.Command{
+		Use:   "clear",
+		Short: "Remove all cache entries",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, _, err := openCache()
+			if err != nil {
+				return err
+			}
+
+			removed, err := c.Clear()
+			if err != nil {
+				return err
+			}
+			cmd.Printf("Removed %d cache entries\n", removed)
+			return nil
+		},
+	}
+
+	cmd.PersistentFlags().StringVar(&dir, "dir", "", "Cache directory (default from policy, else the user cache directory)")
+	cmd.PersistentFlags().StringVar(&policyPath, "policy", "./.sentinel/policy.yaml", "Path to policy file")
+
+	cmd.AddCommand(stats, prune, clear)
+
+	return cmd
+}
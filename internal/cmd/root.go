@@ -18,6 +18,9 @@ Not intended for production environments.`,
 	root.AddCommand(scanCmd())
 	root.AddCommand(applyCmd())
 	root.AddCommand(prCmd())
+	root.AddCommand(cacheCmd())
+	root.AddCommand(baselineCmd())
+	root.AddCommand(vulndbCmd())
 
 	return root
 }
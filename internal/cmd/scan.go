@@ -6,6 +6,7 @@ import (
 	"os"
 
 	"github.com/spf13/cobra"
+	"github.com/Siddhant-K-code/sentinel-ai/internal/baseline"
 	"github.com/Siddhant-K-code/sentinel-ai/internal/engine"
 	"github.com/Siddhant-K-code/sentinel-ai/internal/policy"
 )
@@ -20,6 +21,10 @@ func scanCmd() *cobra.Command {
 		logOut     string
 		doSec      bool
 		doDead     bool
+		concurrency int
+		baselinePath string
+		failOn     string
+		coverProfile string
 	)
 
 	cmd := &cobra.Command{
@@ -42,20 +47,39 @@ Produces SARIF output and patch plans without modifying the codebase.`,
 				AgentPath: agentPath,
 				Policy:    pol,
 				LogPath:   logOut,
+				CoverProfile: coverProfile,
 			})
 			if err != nil {
 				return err
 			}
 
+			// Load the accepted findings
+			var base *baseline.Baseline
+			if baselinePath != "" {
+				if base, err = baseline.Load(baselinePath); err != nil {
+					return err
+				}
+				if err := base.RequireSecretKey(); err != nil {
+					return err
+				}
+			}
+
 			// Run scan
 			res, err := e.Scan(ctx, engine.ScanOpts{
 				Security: doSec,
 				DeadCode: doDead,
+				Concurrency: concurrency,
+				Baseline: base,
+				FailOn: failOn,
 			})
 			if err != nil {
 				return err
 			}
 
+			for _, fixed := range res.Fixed {
+				cmd.PrintErrf("fixed: %s %s%s\n", fixed.File, fixed.Rule, fixed.Symbol)
+			}
+
 			// Write outputs
 			if sarifOut != "" {
 				if err := os.WriteFile(sarifOut, res.SARIF, 0644); err != nil {
@@ -73,6 +97,7 @@ Produces SARIF output and patch plans without modifying the codebase.`,
 				}
 			}
 
+			cmd.PrintErrln(res.Summary)
 			os.Exit(res.ExitCode)
 			return nil
 		},
@@ -86,6 +111,10 @@ Produces SARIF output and patch plans without modifying the codebase.`,
 	cmd.Flags().StringVar(&logOut, "log", "", "Log output file path")
 	cmd.Flags().BoolVar(&doSec, "security", false, "Enable security scanning")
 	cmd.Flags().BoolVar(&doDead, "dead-code", false, "Enable dead-code detection")
+	cmd.Flags().StringVar(&baselinePath, "baseline", "", "Baseline file; only findings not in it are reported and affect the exit code")
+	cmd.Flags().StringVar(&failOn, "fail-on", "", "Fail on security findings at or above this severity (critical, high, medium, low, info), replacing the policy's security gates")
+	cmd.Flags().StringVar(&coverProfile, "coverprofile", "", "Existing Go coverage profile for dead-code risk, instead of running the tests")
+	cmd.Flags().IntVar(&concurrency, "concurrency", 0, "Maximum analyzers run in parallel (default from policy)")
 
 	return cmd
 }
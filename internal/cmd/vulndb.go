@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/Siddhant-K-code/sentinel-ai/internal/vulndb"
+)
+
+func vulndbCmd() *cobra.Command {
+	var dir string
+
+	cmd := &cobra.Command{
+		Use:   "vulndb",
+		Short: "Manage the local OSV vulnerability database",
+		Long: `Manage the local copy of OSV advisories used by the osv analyzer.
+Scans run without network access, so the database is imported ahead of time.`,
+	}
+
+	importCmd := &cobra.Command{
+		Use:   "import <dir|zip>",
+		Short: "Replace the database with OSV advisories from a directory or zip",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			stats, err := vulndb.Import(args[0], dir)
+			if err != nil {
+				return err
+			}
+			cmd.Printf("Imported %d advisories (%d files skipped)\n", stats.Entries, stats.Skipped)
+			return nil
+		},
+	}
+
+	cmd.PersistentFlags().StringVar(&dir, "dir", "", "Database directory (default: vulndb under the user cache directory)")
+
+	cmd.AddCommand(importCmd)
+
+	return cmd
+}
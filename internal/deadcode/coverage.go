@@ -0,0 +1,132 @@
+package deadcode
+
+import (
+	"fmt"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/tools/cover"
+)
+
+// Coverage is how much of a function the tests executed
+type Coverage struct {
+	Statements int     `json:"statements"`
+	Covered    int     `json:"covered"`
+	Percent    float64 `json:"percent"`
+	// Blocks are the function's blocks in the profile, in source order
+	Blocks []CoverageBlock `json:"blocks,omitempty"`
+}
+
+// CoverageBlock is a block of statements and how often the tests ran it
+// ("set" profiles only record 0 or 1)
+type CoverageBlock struct {
+	StartLine  int `json:"start_line"`
+	StartCol   int `json:"start_col"`
+	EndLine    int `json:"end_line"`
+	EndCol     int `json:"end_col"`
+	Statements int `json:"statements"`
+	Count      int `json:"count"`
+}
+
+// Executed reports whether the tests ran any statement of the function
+func (c *Coverage) Executed() bool {
+	return c.Covered > 0
+}
+
+// coverProfile holds the blocks of a Go coverage profile by
+// workspace-relative, slash-separated file
+type coverProfile struct {
+	mode  string
+	files map[string][]cover.ProfileBlock
+}
+
+// loadCoverProfile parses a profile written by go test -coverprofile. Its
+// files are named by import path, which is mapped onto the workspace
+// through the module path in go.mod; files of other modules are dropped.
+func loadCoverProfile(path, workspace string) (*coverProfile, error) {
+	profiles, err := cover.ParseProfiles(path)
+	if err != nil {
+		return nil, fmt.Errorf("parse coverage profile: %w", err)
+	}
+
+	module := ""
+	if data, err := os.ReadFile(filepath.Join(workspace, "go.mod")); err == nil {
+		module = modfile.ModulePath(data)
+	}
+
+	p := &coverProfile{files: make(map[string][]cover.ProfileBlock)}
+	for _, profile := range profiles {
+		p.mode = profile.Mode
+		var rel string
+		switch {
+		case filepath.IsAbs(profile.FileName):
+			r, err := filepath.Rel(workspace, profile.FileName)
+			if err != nil || strings.HasPrefix(r, "..") {
+				continue
+			}
+			rel = filepath.ToSlash(r)
+		case module != "" && strings.HasPrefix(profile.FileName, module+"/"):
+			rel = strings.TrimPrefix(profile.FileName, module+"/")
+		default:
+			continue
+		}
+		p.files[rel] = append(p.files[rel], profile.Blocks...)
+	}
+	return p, nil
+}
+
+// function returns the coverage of the blocks between start and end in a
+// workspace file, or nil when the profile has no statements there
+func (p *coverProfile) function(file string, start, end token.Position) *Coverage {
+	if p == nil {
+		return nil
+	}
+	c := &Coverage{}
+	for _, b := range p.files[filepath.ToSlash(file)] {
+		if before(b.StartLine, b.StartCol, start.Line, start.Column) || before(end.Line, end.Column, b.EndLine, b.EndCol) {
+			continue
+		}
+		c.Blocks = append(c.Blocks, CoverageBlock{
+			StartLine:  b.StartLine,
+			StartCol:   b.StartCol,
+			EndLine:    b.EndLine,
+			EndCol:     b.EndCol,
+			Statements: b.NumStmt,
+			Count:      b.Count,
+		})
+		c.Statements += b.NumStmt
+		if b.Count > 0 {
+			c.Covered += b.NumStmt
+		}
+	}
+	if c.Statements == 0 {
+		return nil
+	}
+	c.Percent = float64(c.Covered) * 100 / float64(c.Statements)
+	return c
+}
+
+// before reports whether line:col comes before otherLine:otherCol
+func before(line, col, otherLine, otherCol int) bool {
+	return line < otherLine || line == otherLine && col < otherCol
+}
+
+// coverageRisk weighs test coverage into the risk of removing a symbol.
+// Dead code the tests never executed is safer to remove, unless other
+// modules may use it; dead code the tests do execute is likely called in
+// ways the analysis cannot see.
+func coverageRisk(risk string, c *Coverage) string {
+	if c == nil {
+		return risk
+	}
+	if c.Executed() {
+		return "high"
+	}
+	if risk == "medium" {
+		return "low"
+	}
+	return risk
+}
@@ -0,0 +1,124 @@
+package deadcode
+
+import (
+	"context"
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Siddhant-K-code/sentinel-ai/internal/goanalysis"
+	"github.com/Siddhant-K-code/sentinel-ai/internal/testutil"
+	"github.com/Siddhant-K-code/sentinel-ai/internal/tools"
+)
+
+func TestLoadCoverProfile(t *testing.T) {
+	root := testutil.WriteFiles(t, map[string]string{"go.mod": "module example.com/app\n"})
+	profile := filepath.Join(t.TempDir(), "cover.out")
+	data := "mode: count\n" +
+		"example.com/app/a.go:3.13,5.2 2 4\n" +
+		"example.com/app/a.go:7.20,8.10 1 0\n" +
+		"example.com/app/a.go:8.10,10.3 3 1\n" +
+		"example.com/app/a.go:12.1,13.2 1 1\n" +
+		"example.com/dep/b.go:1.1,2.2 1 1\n" +
+		filepath.Join(root, "pkg", "c.go") + ":1.1,2.2 1 0\n"
+	if err := os.WriteFile(profile, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := loadCoverProfile(profile, root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.mode != "count" || len(p.files) != 2 || p.files["pkg/c.go"] == nil {
+		t.Fatalf("Expected a.go and pkg/c.go from the profile, got %+v", p.files)
+	}
+
+	// Blocks of the function at lines 7-11, not its neighbours
+	c := p.function("a.go", token.Position{Line: 7, Column: 1}, token.Position{Line: 11, Column: 2})
+	if c == nil || len(c.Blocks) != 2 || c.Statements != 4 || c.Covered != 3 || c.Percent != 75 || !c.Executed() {
+		t.Errorf("Expected 3 of 4 statements covered, got %+v", c)
+	}
+	if c := p.function("a.go", token.Position{Line: 15, Column: 1}, token.Position{Line: 16, Column: 2}); c != nil {
+		t.Errorf("Expected no coverage outside the profile's blocks, got %+v", c)
+	}
+
+	if _, err := loadCoverProfile(filepath.Join(root, "missing.out"), root); err == nil {
+		t.Error("Expected an error for a missing profile")
+	}
+}
+
+func TestDetectCoverage(t *testing.T) {
+	root := testutil.WriteFiles(t, referencesModule)
+	profile := filepath.Join(t.TempDir(), "cover.out")
+	data := "mode: set\n" +
+		"example.com/app/main.go:21.22,26.2 2 0\n" +
+		"example.com/app/internal/store/store.go:9.26,9.42 1 1\n"
+	if err := os.WriteFile(profile, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runner := tools.NewRunner(root, [][]string{goanalysis.ListCommand}, time.Minute)
+
+	result, err := NewDetector(runner, root, Options{CoverProfile: profile}).Detect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	symbols := make(map[string]Symbol)
+	for _, sym := range result.Symbols {
+		symbols[sym.Name] = sym
+	}
+
+	// Never executed in tests: still low risk, with the evidence attached
+	if sym := symbols["loop"]; sym.Coverage == nil || sym.Coverage.Executed() || sym.Coverage.Statements != 2 || sym.Risk != "low" {
+		t.Errorf("Expected loop to be unexecuted and low risk, got %+v", sym)
+	}
+	// Executed by tests although unreferenced, so something calls it
+	if sym := symbols["Memory.Reset"]; sym.Coverage == nil || !sym.Coverage.Executed() || sym.Risk != "high" {
+		t.Errorf("Expected Memory.Reset to be executed and high risk, got %+v", sym)
+	}
+	// No statements, no evidence
+	if sym := symbols["Unused"]; sym.Coverage != nil || sym.Risk != "medium" {
+		t.Errorf("Expected Unused without coverage at medium risk, got %+v", sym)
+	}
+
+	result, err = NewDetector(runner, root, Options{CoverProfile: filepath.Join(root, "missing.out")}).Detect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Error == "" {
+		t.Error("Expected an error for a missing coverage profile")
+	}
+}
+
+func TestDetectRunsCoverage(t *testing.T) {
+	root := testutil.WriteFiles(t, referencesModule)
+	coverage := tools.DetectToolchains(root)[0].Coverage
+	runner := tools.NewRunner(root, [][]string{goanalysis.ListCommand, coverage}, time.Minute)
+
+	result, err := NewDetector(runner, root, Options{}).Detect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.CoverageError != "" {
+		t.Fatalf("Expected the coverage run to succeed: %s", result.CoverageError)
+	}
+	if _, err := os.Stat(filepath.Join(root, filepath.FromSlash(tools.GoCoverProfile))); err != nil {
+		t.Errorf("Expected the profile under .sentinel: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "coverage.out")); err == nil {
+		t.Error("Expected no profile written into the workspace tree")
+	}
+
+	// A failing test run is reported rather than dropped
+	if err := os.WriteFile(filepath.Join(root, "fail_test.go"), []byte("package main\n\nimport \"testing\"\n\nfunc TestFail(t *testing.T) { t.Fatal(\"boom\") }\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	result, err = NewDetector(runner, root, Options{}).Detect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.CoverageError == "" {
+		t.Error("Expected the failed coverage run to be reported")
+	}
+}
@@ -2,6 +2,8 @@ package deadcode
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"go/ast"
 	"go/parser"
@@ -11,6 +13,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/Siddhant-K-code/sentinel-ai/internal/goanalysis"
+	"github.com/Siddhant-K-code/sentinel-ai/internal/suppress"
 	"github.com/Siddhant-K-code/sentinel-ai/internal/tools"
 )
 
@@ -18,13 +22,53 @@ import (
 type Detector struct {
 	runner    *tools.Runner
 	workspace string
+	opts      Options
 }
 
+// Options controls dead-code detection
+type Options struct {
+	// Tags are extra build tags used when loading packages
+	Tags []string
+	// Network lets package loading download missing modules
+	Network bool
+	// Reachability also reports functions and methods that are referenced
+	// but that no entry point reaches
+	Reachability bool
+	// CoverProfile is an existing Go coverage profile; without one, the
+	// Go coverage command is run when the allowlist permits it
+	CoverProfile string
+	// Programs shares loaded packages with other analyzers; without it
+	// the detector loads its own
+	Programs *goanalysis.Loader
+}
+
+// Analysis kinds reported in DeadCodeResult
+const (
+	// AnalysisTypes counts references with full type information across
+	// the module, tests included
+	AnalysisTypes = "types"
+	// AnalysisReachability adds whole-program reachability from main,
+	// init, tests and the exported API to AnalysisTypes
+	AnalysisReachability = "reachability"
+	// AnalysisSyntax looks at calls within each file, when the packages
+	// cannot be loaded
+	AnalysisSyntax = "syntax"
+)
+
 // DeadCodeResult represents the result of dead code detection
 type DeadCodeResult struct {
 	Symbols   []Symbol `json:"symbols"`
+	Suppressed []Symbol `json:"suppressed,omitempty"` // accepted by inline suppressions
 	Duration  time.Duration `json:"duration"`
 	Error     string   `json:"error,omitempty"`
+	// Analysis is how references were found: AnalysisTypes,
+	// AnalysisReachability or AnalysisSyntax
+	Analysis string `json:"analysis,omitempty"`
+	// Warning explains results that may be incomplete
+	Warning string `json:"warning,omitempty"`
+	// CoverageError is why running the tests for coverage failed; risk
+	// is then assessed without coverage
+	CoverageError string `json:"coverage_error,omitempty"`
 }
 
 // Symbol represents a potentially dead code symbol
@@ -39,13 +83,33 @@ type Symbol struct {
 	LastTouch   string `json:"last_touch,omitempty"`
 	Risk        string `json:"risk"` // low, medium, high
 	Description string `json:"description"`
+	// Unreachable is set for referenced functions and methods that no
+	// entry point reaches; DeadCallers is the chain of unreachable
+	// declarations that references it, nearest first
+	Unreachable bool     `json:"unreachable,omitempty"`
+	DeadCallers []string `json:"dead_callers,omitempty"`
+	// Coverage is what the tests executed of a function or method, when
+	// a coverage profile is available
+	Coverage *Coverage `json:"coverage,omitempty"`
+	Suppression *suppress.Suppression `json:"suppression,omitempty"`
+}
+
+// Fingerprint identifies a symbol independently of its line number
+func (s Symbol) Fingerprint() string {
+	h := sha256.New()
+	for _, part := range []string{s.Kind, s.Package, filepath.ToSlash(s.File), s.Name} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))[:32]
 }
 
 // NewDetector creates a new dead code detector
-func NewDetector(runner *tools.Runner, workspace string) *Detector {
+func NewDetector(runner *tools.Runner, workspace string, opts Options) *Detector {
 	return &Detector{
 		runner:    runner,
 		workspace: workspace,
+		opts:      opts,
 	}
 }
 
@@ -53,10 +117,17 @@ func NewDetector(runner *tools.Runner, workspace string) *Detector {
 func (d *Detector) Detect(ctx context.Context) (*DeadCodeResult, error) {
 	start := time.Now()
 
-	// Get coverage information (optional)
+	// Get coverage information, optional unless a profile was given
 	coverage, err := d.getCoverage(ctx)
+	coverageErr := ""
 	if err != nil {
-		coverage = make(map[string]float64) // Use empty coverage map
+		if d.opts.CoverProfile != "" {
+			return &DeadCodeResult{
+				Duration: time.Since(start),
+				Error:    err.Error(),
+			}, nil
+		}
+		coverage, coverageErr = nil, err.Error()
 	}
 
 	// Find all Go files
@@ -68,40 +139,119 @@ func (d *Detector) Detect(ctx context.Context) (*DeadCodeResult, error) {
 		}, nil
 	}
 
-	// Analyze each file for dead code
-	var symbols []Symbol
-	for _, file := range goFiles {
-		fileSymbols, err := d.analyzeFile(file, coverage)
-		if err != nil {
-			continue // Skip files with errors
+	// Inline suppressions accept individual symbols; problems with them
+	// are reported by the security scanner
+	suppressions, err := suppress.Load(d.workspace, time.Now())
+	if err != nil {
+		return &DeadCodeResult{
+			Duration: time.Since(start),
+			Error:    fmt.Sprintf("failed to read suppressions: %v", err),
+		}, nil
+	}
+
+	// Count references with type information, or fall back to looking
+	// at each file on its own
+	analysis, warning := AnalysisTypes, ""
+	if d.opts.Reachability {
+		analysis = AnalysisReachability
+	}
+	symbols, illTyped, err := d.analyzeTypes(ctx, coverage)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		analysis = AnalysisSyntax
+		warning = fmt.Sprintf("type-checked analysis unavailable, only calls within each file are counted: %v", err)
+		symbols = nil
+		for _, file := range goFiles {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			fileSymbols, err := d.analyzeFile(file, coverage)
+			if err != nil {
+				continue // Skip files with errors
+			}
+			symbols = append(symbols, fileSymbols...)
 		}
-		symbols = append(symbols, fileSymbols...)
+	} else if len(illTyped) > 0 {
+		warning = fmt.Sprintf("packages with errors are not checked: %s", strings.Join(illTyped, ", "))
+	}
+	for i := range symbols {
+		sym := &symbols[i]
+		sym.Suppression = suppressions.Lookup(sym.File, sym.Line, "deadcode", "deadcode/"+sym.Kind)
 	}
 
 	// Filter out symbols that are actually used
-	deadSymbols := d.filterDeadSymbols(symbols)
+	var deadSymbols, suppressed []Symbol
+	for _, sym := range d.filterDeadSymbols(symbols) {
+		if sym.Suppression != nil {
+			suppressed = append(suppressed, sym)
+			continue
+		}
+		deadSymbols = append(deadSymbols, sym)
+	}
 
 	return &DeadCodeResult{
-		Symbols:  deadSymbols,
-		Duration: time.Since(start),
+		Symbols:    deadSymbols,
+		Suppressed: suppressed,
+		Duration:   time.Since(start),
+		Analysis:   analysis,
+		Warning:    warning,
+		CoverageError: coverageErr,
 	}, nil
 }
 
-// getCoverage gets test coverage information
-func (d *Detector) getCoverage(ctx context.Context) (map[string]float64, error) {
-	// Run coverage analysis
-	result := d.runner.Coverage(ctx)
-	if result.Error != nil {
-		return nil, result.Error
+// analyzeTypes returns the package-level declarations of the workspace
+// that nothing references, and the packages skipped for errors
+func (d *Detector) analyzeTypes(ctx context.Context, coverage *coverProfile) ([]Symbol, []string, error) {
+	refs, err := d.loadReferences(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if d.opts.Reachability {
+		refs.markUnreachable()
+	}
+	var symbols []Symbol
+	for _, c := range refs.dead() {
+		sym := c.sym
+		sym.Risk = d.calculateRisk(sym.Name, sym.Kind, sym.Exported)
+		if sym.Exported && !importable(c.pkg.Types) {
+			// Nothing outside the module can use it
+			sym.Risk = "medium"
+		}
+		if sym.Kind == "func" || sym.Kind == "method" {
+			sym.Coverage = coverage.function(sym.File, c.start, c.end)
+			sym.Risk = coverageRisk(sym.Risk, sym.Coverage)
+		}
+		symbols = append(symbols, sym)
 	}
+	return symbols, refs.illTyped, nil
+}
 
-	// Parse coverage output (simplified)
-	// In a real implementation, this would parse the coverage.out file
-	coverage := make(map[string]float64)
+// getCoverage reads the coverage profile given in the options or, when
+// the allowlist permits it, runs the Go coverage command and reads the
+// profile it writes. It returns nil without an error when coverage is
+// not available.
+func (d *Detector) getCoverage(ctx context.Context) (*coverProfile, error) {
+	if d.opts.CoverProfile != "" {
+		return loadCoverProfile(d.opts.CoverProfile, d.workspace)
+	}
 
-	// For now, return empty coverage map
-	// Real implementation would parse go test -coverprofile output
-	return coverage, nil
+	for _, tc := range d.runner.Toolchains() {
+		if tc.Name != "go" || tc.Coverage == nil || !d.runner.Allowed(tc.Coverage[0], tc.Coverage[1:]...) {
+			continue
+		}
+		result := d.runner.Run(ctx, tc.Coverage[0], tc.Coverage[1:]...)
+		if result.Error != nil {
+			return nil, fmt.Errorf("%s: %v", strings.Join(tc.Coverage, " "), result.Error)
+		}
+		root, err := d.runner.WorkDir("")
+		if err != nil {
+			return nil, err
+		}
+		return loadCoverProfile(filepath.Join(root, filepath.FromSlash(tools.GoCoverProfile)), d.workspace)
+	}
+	return nil, nil
 }
 
 // findGoFiles finds all Go files in the workspace
@@ -130,7 +280,7 @@ func (d *Detector) findGoFiles() ([]string, error) {
 }
 
 // analyzeFile analyzes a Go file for potentially dead symbols
-func (d *Detector) analyzeFile(filePath string, coverage map[string]float64) ([]Symbol, error) {
+func (d *Detector) analyzeFile(filePath string, coverage *coverProfile) ([]Symbol, error) {
 	fset := token.NewFileSet()
 	node, err := parser.ParseFile(fset, filePath, nil, parser.ParseComments)
 	if err != nil {
@@ -139,6 +289,10 @@ func (d *Detector) analyzeFile(filePath string, coverage map[string]float64) ([]
 
 	var symbols []Symbol
 	packageName := node.Name.Name
+	rel, err := filepath.Rel(d.workspace, filePath)
+	if err != nil {
+		return nil, err
+	}
 
 	// Collect all function calls in the file to check for references
 	callMap := make(map[string]bool)
@@ -162,17 +316,19 @@ func (d *Detector) analyzeFile(filePath string, coverage map[string]float64) ([]
 				if isCalled {
 					references = 1
 				}
+				cov := coverage.function(rel, fset.Position(x.Pos()), fset.Position(x.End()))
 
 				symbols = append(symbols, Symbol{
 					Name:       x.Name.Name,
 					Kind:       "func",
 					Package:    packageName,
-					File:       filepath.Base(filePath),
+					File:       rel,
 					Line:       fset.Position(x.Pos()).Line,
 					Exported:   x.Name.IsExported(),
 					References: references,
-					Risk:       d.calculateRisk(x.Name.Name, "func", x.Name.IsExported()),
+					Risk:       coverageRisk(d.calculateRisk(x.Name.Name, "func", x.Name.IsExported()), cov),
 					Description: fmt.Sprintf("Function %s in package %s", x.Name.Name, packageName),
+					Coverage:   cov,
 				})
 			}
 		case *ast.GenDecl:
@@ -185,7 +341,7 @@ func (d *Detector) analyzeFile(filePath string, coverage map[string]float64) ([]
 								Name:       name.Name,
 								Kind:       "var",
 								Package:    packageName,
-								File:       filepath.Base(filePath),
+								File:       rel,
 								Line:       fset.Position(name.Pos()).Line,
 								Exported:   name.IsExported(),
 								References: 0,
@@ -200,7 +356,7 @@ func (d *Detector) analyzeFile(filePath string, coverage map[string]float64) ([]
 							Name:       s.Name.Name,
 							Kind:       "type",
 							Package:    packageName,
-							File:       filepath.Base(filePath),
+							File:       rel,
 							Line:       fset.Position(s.Pos()).Line,
 							Exported:   s.Name.IsExported(),
 							References: 0,
@@ -241,9 +397,7 @@ func (d *Detector) filterDeadSymbols(symbols []Symbol) []Symbol {
 	var deadSymbols []Symbol
 
 	for _, symbol := range symbols {
-		// For now, consider all unexported symbols with 0 references as dead
-		// In a real implementation, this would do proper reference analysis
-		if !symbol.Exported && symbol.References == 0 {
+		if symbol.References == 0 || symbol.Unreachable {
 			deadSymbols = append(deadSymbols, symbol)
 		}
 	}
@@ -0,0 +1,314 @@
+package deadcode
+
+import (
+	"context"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Siddhant-K-code/sentinel-ai/internal/goanalysis"
+	"github.com/Siddhant-K-code/sentinel-ai/internal/testutil"
+	"github.com/Siddhant-K-code/sentinel-ai/internal/tools"
+)
+
+var referencesModule = map[string]string{
+	"go.mod": "module example.com/app\n\ngo 1.22\n",
+	"main.go": `package main
+
+import (
+	"fmt"
+
+	"example.com/app/internal/store"
+)
+
+func main() {
+	apply(double)
+	var s store.Store = &store.Memory{}
+	fmt.Println(s.Get("k"), helperFromOtherFile(), mode(modeFast))
+	fmt.Println(find(store.DB{}), closeAll(store.DB{}))
+}
+
+func apply(f func(int) int) int { return f(2) }
+
+func double(n int) int { return n * 2 }
+
+// loop only calls itself
+func loop(n int) int {
+	if n == 0 {
+		return 0
+	}
+	return loop(n - 1)
+}
+
+type mode int
+
+const (
+	modeFast mode = iota
+	modeSlow
+)
+
+const unusedLimit = 10
+
+// finder is declared by its consumer, not by package store
+type finder interface{ Lookup(key string) string }
+
+func find(f finder) string { return f.Lookup("k") }
+
+func closeAll(c interface{ Close() error }) error { return c.Close() }
+`,
+	"other.go": `package main
+
+func helperFromOtherFile() string { return "other" }
+
+var taggedOnly = "tagged"
+
+func testedOnly() bool { return true }
+`,
+	"tagged.go": `//go:build integration
+
+package main
+
+func tagged() string { return taggedOnly }
+`,
+	"main_test.go": `package main
+
+import "testing"
+
+func TestTestedOnly(t *testing.T) {
+	if !testedOnly() {
+		t.Fatal()
+	}
+}
+`,
+	"internal/store/store.go": `package store
+
+type Store interface{ Get(key string) string }
+
+type Memory struct{ data map[string]string }
+
+func (m *Memory) Get(key string) string { return m.data[key] }
+
+func (m *Memory) Reset() { m.data = nil }
+
+func Unused() {}
+
+type DB struct{}
+
+func (DB) Lookup(key string) string { return key }
+
+func (DB) Close() error { return nil }
+`,
+}
+
+func TestDetectTypes(t *testing.T) {
+	root := testutil.WriteFiles(t, referencesModule)
+	runner := tools.NewRunner(root, [][]string{goanalysis.ListCommand}, time.Minute)
+	d := NewDetector(runner, root, Options{})
+
+	result, err := d.Detect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Analysis != AnalysisTypes || result.Warning != "" {
+		t.Fatalf("Expected type-checked analysis, got %q (%s)", result.Analysis, result.Warning)
+	}
+
+	var got []string
+	for _, sym := range result.Symbols {
+		got = append(got, sym.Kind+" "+sym.Name+" "+filepath.ToSlash(sym.File)+" "+sym.Risk)
+	}
+	sort.Strings(got)
+	// DB.Lookup and DB.Close are called through interfaces main declares
+	want := []string{
+		"const unusedLimit main.go low",
+		"func Unused internal/store/store.go medium",
+		"func loop main.go low",
+		"method Memory.Reset internal/store/store.go medium",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Expected dead symbols:\n%s\ngot:\n%s", strings.Join(want, "\n"), strings.Join(got, "\n"))
+	}
+}
+
+func TestReferenceCounts(t *testing.T) {
+	root := testutil.WriteFiles(t, referencesModule)
+	runner := tools.NewRunner(root, [][]string{goanalysis.ListCommand}, time.Minute)
+	d := NewDetector(runner, root, Options{})
+
+	refs, err := d.loadReferences(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	counts := make(map[string]int)
+	for _, c := range refs.candidates {
+		counts[c.sym.Name] = c.sym.References
+	}
+	tests := map[string]int{
+		"double":              1, // passed as a value
+		"helperFromOtherFile": 1, // called from another file
+		"testedOnly":          1, // called from a test
+		"Memory":              3, // a literal and two receivers
+		"mode":                2,
+		"loop":                0, // recursion does not count
+		"taggedOnly":          1, // used in a file excluded by build tags
+	}
+	for name, want := range tests {
+		if got := counts[name]; got != want {
+			t.Errorf("Expected %d references to %s, got %d", want, name, got)
+		}
+	}
+}
+
+func TestDetectFallsBackToSyntax(t *testing.T) {
+	root := testutil.WriteFiles(t, map[string]string{
+		"main.go": "package main\n\nfunc main() { used() }\n\nfunc used() {}\n\nfunc unused() {}\n",
+	})
+	// No go.mod, so packages cannot be loaded
+	runner := tools.NewRunner(root, [][]string{goanalysis.ListCommand}, time.Minute)
+	result, err := NewDetector(runner, root, Options{}).Detect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Analysis != AnalysisSyntax || !strings.Contains(result.Warning, "no go.mod") {
+		t.Errorf("Expected syntax fallback with a warning, got %q (%s)", result.Analysis, result.Warning)
+	}
+	if len(result.Symbols) != 1 || result.Symbols[0].Name != "unused" || result.Symbols[0].File != "main.go" {
+		t.Errorf("Expected only unused to be dead, got %+v", result.Symbols)
+	}
+}
+
+func TestDetectBuildTags(t *testing.T) {
+	root := testutil.WriteFiles(t, referencesModule)
+	runner := tools.NewRunner(root, [][]string{goanalysis.ListCommand}, time.Minute)
+	d := NewDetector(runner, root, Options{Tags: []string{"integration"}})
+
+	result, err := d.Detect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// With the tag, tagged.go is type-checked and its function is dead
+	for _, sym := range result.Symbols {
+		if sym.Name == "tagged" {
+			return
+		}
+	}
+	t.Errorf("Expected tagged to be dead with the integration tag, got %+v", result.Symbols)
+}
+
+var reachabilityModule = map[string]string{
+	"go.mod": "module example.com/app\n\ngo 1.22\n",
+	"main.go": `package main
+
+import (
+	"fmt"
+	"reflect"
+
+	"example.com/app/internal/shapes"
+)
+
+type shape interface{ area() int }
+
+type square struct{ n int }
+
+func (s square) area() int { return s.n * s.n }
+
+type circle struct{ r int }
+
+func (c circle) area() int { return 3 * c.r * c.r }
+
+func total(list ...shape) int {
+	sum := 0
+	for _, s := range list {
+		sum += s.area()
+	}
+	return sum
+}
+
+func main() {
+	fmt.Println(total(square{2}), temp(3))
+	reflect.ValueOf(plugin{}).MethodByName("Run").Call(nil)
+}
+
+func oldEntry() int { return legacy() }
+
+func legacy() int { return total(circle{1}) + shapes.Old() }
+
+type temp int
+
+func (t temp) String() string { return format(int(t)) }
+
+func format(n int) string { return fmt.Sprint(n, " degrees") }
+
+type plugin struct{}
+
+func (plugin) Run() { runPlugin() }
+
+func runPlugin() {}
+`,
+	"internal/shapes/shapes.go": `package shapes
+
+func Old() int { return older() }
+
+func older() int { return 1 }
+`,
+	"lib/lib.go": `package lib
+
+func Public() int { return helper() }
+
+func helper() int { return 2 }
+`,
+}
+
+func TestDetectReachability(t *testing.T) {
+	root := testutil.WriteFiles(t, reachabilityModule)
+	runner := tools.NewRunner(root, [][]string{goanalysis.ListCommand}, time.Minute)
+	d := NewDetector(runner, root, Options{Reachability: true})
+
+	result, err := d.Detect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Analysis != AnalysisReachability || result.Warning != "" {
+		t.Fatalf("Expected reachability analysis, got %q (%s)", result.Analysis, result.Warning)
+	}
+
+	var unreachable, unreferenced []string
+	for _, sym := range result.Symbols {
+		if sym.Unreachable {
+			unreachable = append(unreachable, sym.Name+" <- "+strings.Join(sym.DeadCallers, ", "))
+		} else {
+			unreferenced = append(unreferenced, sym.Name)
+		}
+	}
+	sort.Strings(unreachable)
+	sort.Strings(unreferenced)
+
+	// circle only reaches the interface from dead code; format is reached
+	// through fmt.Stringer, runPlugin through reflection and helper
+	// through the exported API of lib
+	want := []string{
+		"Old <- example.com/app.legacy, example.com/app.oldEntry",
+		"circle.area <- ",
+		"legacy <- oldEntry",
+		"older <- Old, example.com/app.legacy, example.com/app.oldEntry",
+	}
+	if strings.Join(unreachable, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Expected unreachable symbols:\n%s\ngot:\n%s", strings.Join(want, "\n"), strings.Join(unreachable, "\n"))
+	}
+	// Unreferenced declarations are still reported unless reached: the
+	// exported API is an entry point and reflection calls plugin.Run
+	if strings.Join(unreferenced, " ") != "oldEntry" {
+		t.Errorf("Expected unreferenced oldEntry, got %v", unreferenced)
+	}
+
+	// Without reachability only unreferenced declarations are dead
+	result, err = NewDetector(runner, root, Options{}).Detect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Symbols) != 3 || result.Analysis != AnalysisTypes {
+		t.Errorf("Expected 3 unreferenced symbols, got %+v", result.Symbols)
+	}
+}
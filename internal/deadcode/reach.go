@@ -0,0 +1,115 @@
+package deadcode
+
+import (
+	"fmt"
+	"go/token"
+	"strings"
+
+	"golang.org/x/tools/go/callgraph/rta"
+	"golang.org/x/tools/go/ssa"
+)
+
+// markUnreachable finds the functions and methods that are referenced, or
+// kept for an interface, but that no entry point reaches, and records the
+// unreachable declarations that keep each of them referenced.
+//
+// Reachability comes from rapid type analysis: a dynamic call reaches
+// the methods of the types converted to interfaces in reachable code.
+// Its reflection rules also apply: exported methods of those types
+// count as reached, as does every function whose address is taken once
+// reflect.Value.Call is linked in. Functions and methods an entry point
+// reaches are live even without references, e.g. through reflection.
+func (r *references) markUnreachable() {
+	roots := r.roots()
+	if len(roots) == 0 {
+		return
+	}
+	reached := make(map[string]bool)
+	for fn := range rta.Analyze(roots, false).Reachable {
+		for fn.Parent() != nil {
+			fn = fn.Parent()
+		}
+		if origin := fn.Origin(); origin != nil {
+			fn = origin
+		}
+		if obj := fn.Object(); obj != nil {
+			reached[r.key(obj.Pos())] = true
+		}
+	}
+
+	for _, key := range r.order {
+		c := r.candidates[key]
+		if c.sym.Kind == "func" || c.sym.Kind == "method" {
+			c.reached = reached[key]
+			c.alive = c.alive || c.reached
+		}
+	}
+	for _, key := range r.order {
+		c := r.candidates[key]
+		if c.sym.Kind != "func" && c.sym.Kind != "method" || c.reached {
+			continue
+		}
+		if c.sym.References == 0 && !c.alive {
+			continue // already dead
+		}
+		c.sym.Unreachable = true
+		c.sym.DeadCallers = r.deadCallers(c)
+		c.sym.Description += " is referenced but unreachable from main, init, tests and the exported API"
+		if len(c.sym.DeadCallers) > 0 {
+			c.sym.Description += " (via " + strings.Join(c.sym.DeadCallers, " <- ") + ")"
+		}
+	}
+}
+
+// roots are the entry points of the workspace: main, init, test
+// functions, and the exported API of packages other modules can import.
+// Exported functions of internal packages are only roots when reached.
+func (r *references) roots() []*ssa.Function {
+	var roots []*ssa.Function
+	for _, fn := range r.prog.EntryPoints() {
+		file := r.prog.Fset.Position(fn.Pos()).Filename
+		if token.IsExported(fn.Name()) && !strings.HasSuffix(file, "_test.go") && !importable(fn.Pkg.Pkg) {
+			continue
+		}
+		roots = append(roots, fn)
+	}
+	return roots
+}
+
+// deadCallers follows the first unreachable or unreferenced referrer of
+// each declaration, starting from c, until one has none or the chain
+// loops
+func (r *references) deadCallers(c *candidate) []string {
+	pkg := c.sym.Package
+	var chain []string
+	seen := map[*candidate]bool{c: true}
+	for {
+		var next *candidate
+		for _, ref := range c.referrers {
+			if !seen[ref] && ref.deadCode() {
+				next = ref
+				break
+			}
+		}
+		if next == nil {
+			return chain
+		}
+		name := next.sym.Name
+		if next.sym.Package != pkg {
+			name = fmt.Sprintf("%s.%s", next.sym.Package, name)
+		}
+		chain = append(chain, name)
+		seen[next] = true
+		c = next
+	}
+}
+
+// deadCode reports whether nothing live keeps a declaration: functions
+// and methods no entry point reaches, and other declarations without
+// references
+func (c *candidate) deadCode() bool {
+	if c.sym.Kind == "func" || c.sym.Kind == "method" {
+		return !c.reached
+	}
+	return c.sym.References == 0 && !c.alive
+}
@@ -0,0 +1,484 @@
+package deadcode
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+
+	"github.com/Siddhant-K-code/sentinel-ai/internal/goanalysis"
+)
+
+// candidate is a package-level declaration that may be dead
+type candidate struct {
+	sym Symbol
+	obj types.Object
+	pkg *packages.Package
+	// file and the extent of the declaration; uses inside it, such as
+	// recursive calls, do not count as references
+	file       string
+	start, end token.Position
+	// group is the const block the declaration belongs to; a block is
+	// used as a whole, like an enum
+	group string
+	// alive is set when something other than a reference keeps the
+	// declaration, such as implementing an interface
+	alive bool
+	// referrers are the declarations whose bodies or initializers use
+	// this one
+	referrers []*candidate
+	// reached is set for functions and methods that an entry point
+	// reaches, when reachability is analyzed
+	reached bool
+}
+
+// references holds the declarations of a program and their reference
+// counts, keyed by the position of the declaring identifier
+type references struct {
+	prog       *goanalysis.Program
+	candidates map[string]*candidate
+	order      []string
+	// illTyped are workspace packages with errors, whose declarations
+	// are not reported because their uses may be missing
+	illTyped []string
+}
+
+// loadReferences loads the workspace with tests and counts the uses of
+// every package-level declaration across all packages and their test
+// variants
+func (d *Detector) loadReferences(ctx context.Context) (*references, error) {
+	opts := goanalysis.Options{Tests: true, Tags: d.opts.Tags, Network: d.opts.Network}
+	var prog *goanalysis.Program
+	var err error
+	if d.opts.Programs != nil {
+		prog, err = d.opts.Programs.Load(ctx, opts)
+	} else {
+		prog, err = goanalysis.Load(ctx, d.runner, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	r := &references{prog: prog, candidates: make(map[string]*candidate)}
+	broken := make(map[string]bool)
+	for _, pkg := range prog.Packages {
+		if len(pkg.Errors) > 0 && !broken[pkg.PkgPath] {
+			broken[pkg.PkgPath] = true
+			r.illTyped = append(r.illTyped, pkg.PkgPath)
+		}
+	}
+	sort.Strings(r.illTyped)
+
+	for _, pkg := range prog.Packages {
+		if pkg.TypesInfo == nil || broken[pkg.PkgPath] {
+			continue
+		}
+		for _, file := range pkg.Syntax {
+			r.declare(pkg, file)
+		}
+	}
+	r.count()
+	r.countIgnoredFiles()
+	r.markInterfaceMethods()
+	return r, nil
+}
+
+// key identifies a declaration or use across the test variants of a
+// package, which parse the same file more than once
+func (r *references) key(pos token.Pos) string {
+	p := r.prog.Fset.Position(pos)
+	return fmt.Sprintf("%s:%d", p.Filename, p.Offset)
+}
+
+// declare records the package-level declarations of a non-test file in
+// the workspace
+func (r *references) declare(pkg *packages.Package, file *ast.File) {
+	filename := r.prog.Fset.Position(file.Pos()).Filename
+	rel, err := filepath.Rel(r.prog.Root, filename)
+	if err != nil || strings.HasPrefix(rel, "..") || strings.HasSuffix(filename, "_test.go") {
+		return
+	}
+
+	add := func(ident *ast.Ident, kind, name string, node ast.Node, group string) {
+		obj := pkg.TypesInfo.Defs[ident]
+		if obj == nil || ident.Name == "_" {
+			return
+		}
+		key := r.key(ident.Pos())
+		if _, dup := r.candidates[key]; dup {
+			return
+		}
+		r.candidates[key] = &candidate{
+			sym: Symbol{
+				Name:        name,
+				Kind:        kind,
+				Package:     pkg.PkgPath,
+				File:        rel,
+				Line:        r.prog.Fset.Position(ident.Pos()).Line,
+				Exported:    ident.IsExported(),
+				Description: fmt.Sprintf("%s %s in package %s", kindNames[kind], name, pkg.PkgPath),
+			},
+			obj:   obj,
+			pkg:   pkg,
+			file:  filename,
+			start: r.prog.Fset.Position(node.Pos()),
+			end:   r.prog.Fset.Position(node.End()),
+			group: group,
+		}
+		r.order = append(r.order, key)
+	}
+
+	for _, decl := range file.Decls {
+		switch x := decl.(type) {
+		case *ast.FuncDecl:
+			if entryPoint(pkg, x) {
+				continue
+			}
+			if x.Recv == nil {
+				add(x.Name, "func", x.Name.Name, x, "")
+				continue
+			}
+			add(x.Name, "method", recvName(x)+"."+x.Name.Name, x, "")
+		case *ast.GenDecl:
+			group := ""
+			if x.Tok == token.CONST && x.Lparen.IsValid() {
+				group = r.key(x.Pos())
+			}
+			for _, spec := range x.Specs {
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					add(s.Name, "type", s.Name.Name, s, "")
+				case *ast.ValueSpec:
+					kind := "var"
+					if x.Tok == token.CONST {
+						kind = "const"
+					}
+					for _, name := range s.Names {
+						add(name, kind, name.Name, s, group)
+					}
+				}
+			}
+		}
+	}
+}
+
+// kindNames describe symbol kinds
+var kindNames = map[string]string{
+	"func":   "Function",
+	"method": "Method",
+	"type":   "Type",
+	"var":    "Variable",
+	"const":  "Constant",
+}
+
+// entryPoint reports whether a function is called from outside Go code:
+// main, init, functions exported to C or linked by name, and functions
+// implemented in assembly
+func entryPoint(pkg *packages.Package, fn *ast.FuncDecl) bool {
+	if fn.Recv == nil && (fn.Name.Name == "init" || (fn.Name.Name == "main" && pkg.Name == "main")) {
+		return true
+	}
+	if fn.Body == nil {
+		return true
+	}
+	if fn.Doc != nil {
+		for _, c := range fn.Doc.List {
+			if strings.HasPrefix(c.Text, "//export ") || strings.HasPrefix(c.Text, "//go:linkname ") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// recvName returns the name of a method's receiver type
+func recvName(fn *ast.FuncDecl) string {
+	t := fn.Recv.List[0].Type
+	for {
+		switch x := t.(type) {
+		case *ast.StarExpr:
+			t = x.X
+		case *ast.IndexExpr:
+			t = x.X
+		case *ast.IndexListExpr:
+			t = x.X
+		case *ast.ParenExpr:
+			t = x.X
+		case *ast.Ident:
+			return x.Name
+		default:
+			return "?"
+		}
+	}
+}
+
+// count adds up the uses of each declaration in every package, counting
+// a use site once even when several test variants type-check it
+func (r *references) count() {
+	byFile := make(map[string][]*candidate)
+	for _, key := range r.order {
+		c := r.candidates[key]
+		byFile[c.file] = append(byFile[c.file], c)
+	}
+
+	seen := make(map[string]bool)
+	for _, pkg := range r.prog.Packages {
+		if pkg.TypesInfo == nil {
+			continue
+		}
+		for ident, obj := range pkg.TypesInfo.Uses {
+			c := r.candidates[r.key(origin(obj).Pos())]
+			if c == nil {
+				continue
+			}
+			use := r.prog.Fset.Position(ident.Pos())
+			key := fmt.Sprintf("%s:%d", use.Filename, use.Offset)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			if use.Filename == c.file && use.Offset >= c.start.Offset && use.Offset < c.end.Offset {
+				continue
+			}
+			c.sym.References++
+			for _, decl := range byFile[use.Filename] {
+				if use.Offset >= decl.start.Offset && use.Offset < decl.end.Offset {
+					c.addReferrer(decl)
+				}
+			}
+		}
+	}
+
+	// Keep referrers in source order so dead caller chains are stable
+	index := make(map[*candidate]int, len(r.order))
+	for i, key := range r.order {
+		index[r.candidates[key]] = i
+	}
+	for _, c := range r.candidates {
+		sort.Slice(c.referrers, func(i, j int) bool {
+			return index[c.referrers[i]] < index[c.referrers[j]]
+		})
+	}
+}
+
+// addReferrer records that decl uses c
+func (c *candidate) addReferrer(decl *candidate) {
+	for _, ref := range c.referrers {
+		if ref == decl {
+			return
+		}
+	}
+	c.referrers = append(c.referrers, decl)
+}
+
+// origin returns the generic declaration of an instantiated function or
+// field
+func origin(obj types.Object) types.Object {
+	switch x := obj.(type) {
+	case *types.Func:
+		return x.Origin()
+	case *types.Var:
+		return x.Origin()
+	}
+	return obj
+}
+
+// countIgnoredFiles counts identifiers in files excluded by build
+// constraints, which cannot be type-checked, as references to the
+// declarations of their package and, for selectors, to exported
+// declarations of any package
+func (r *references) countIgnoredFiles() {
+	byPackage := make(map[string]map[string][]*candidate)
+	exported := make(map[string][]*candidate)
+	for _, key := range r.order {
+		c := r.candidates[key]
+		name := c.obj.Name()
+		if byPackage[c.sym.Package] == nil {
+			byPackage[c.sym.Package] = make(map[string][]*candidate)
+		}
+		byPackage[c.sym.Package][name] = append(byPackage[c.sym.Package][name], c)
+		if c.obj.Exported() {
+			exported[name] = append(exported[name], c)
+		}
+	}
+
+	parsed := make(map[string]bool)
+	fset := token.NewFileSet()
+	for _, pkg := range r.prog.Packages {
+		for _, filename := range pkg.IgnoredFiles {
+			if parsed[filename] || !strings.HasSuffix(filename, ".go") {
+				continue
+			}
+			parsed[filename] = true
+			file, err := parser.ParseFile(fset, filename, nil, parser.SkipObjectResolution)
+			if err != nil {
+				continue
+			}
+			local := byPackage[pkg.PkgPath]
+			count := func(name string, selector bool) {
+				for _, c := range local[name] {
+					c.sym.References++
+				}
+				if !selector {
+					return
+				}
+				for _, c := range exported[name] {
+					if c.sym.Package != pkg.PkgPath {
+						c.sym.References++
+					}
+				}
+			}
+
+			// Names declared by the file are not uses
+			declared := make(map[*ast.Ident]bool)
+			for _, decl := range file.Decls {
+				if fn, ok := decl.(*ast.FuncDecl); ok {
+					declared[fn.Name] = true
+				}
+			}
+			var inspect func(n ast.Node) bool
+			inspect = func(n ast.Node) bool {
+				switch x := n.(type) {
+				case *ast.SelectorExpr:
+					count(x.Sel.Name, true)
+					ast.Inspect(x.X, inspect)
+					return false
+				case *ast.Ident:
+					if !declared[x] {
+						count(x.Name, false)
+					}
+				}
+				return true
+			}
+			for _, decl := range file.Decls {
+				ast.Inspect(decl, inspect)
+			}
+		}
+	}
+}
+
+// markInterfaceMethods keeps methods that implement an interface anywhere
+// in the program, named or literal, as they may be called through it:
+// consumers usually declare the interfaces they need. Methods of generic
+// types are kept when any interface has a method of the same name.
+func (r *references) markInterfaceMethods() {
+	ifaces := r.interfaces()
+	for _, key := range r.order {
+		c := r.candidates[key]
+		fn, ok := c.obj.(*types.Func)
+		if !ok {
+			continue
+		}
+		recv := fn.Signature().Recv()
+		if recv == nil {
+			continue
+		}
+		t := recv.Type()
+		if ptr, ok := t.(*types.Pointer); ok {
+			t = ptr.Elem()
+		}
+		named, ok := types.Unalias(t).(*types.Named)
+		if !ok {
+			continue
+		}
+
+		for _, iface := range ifaces[fn.Name()] {
+			if named.TypeParams().Len() > 0 || types.Implements(named, iface) || types.Implements(types.NewPointer(named), iface) {
+				c.alive = true
+				break
+			}
+		}
+	}
+}
+
+// interfaces returns the non-empty interfaces of the program by method
+// name: those declared at package level in any loaded package, and every
+// interface type the workspace and its test variants spell out, such as
+// interface{ Close() error } in a signature
+func (r *references) interfaces() map[string][]*types.Interface {
+	byName := make(map[string][]*types.Interface)
+	seen := make(map[*types.Interface]bool)
+	add := func(t types.Type) {
+		iface, ok := t.Underlying().(*types.Interface)
+		if !ok || iface.NumMethods() == 0 || seen[iface] {
+			return
+		}
+		seen[iface] = true
+		for i := 0; i < iface.NumMethods(); i++ {
+			name := iface.Method(i).Name()
+			byName[name] = append(byName[name], iface)
+		}
+	}
+
+	workspace := make(map[*packages.Package]bool)
+	for _, pkg := range r.prog.Packages {
+		workspace[pkg] = true
+	}
+	visited := make(map[*packages.Package]bool)
+	var visit func(p *packages.Package)
+	visit = func(p *packages.Package) {
+		if visited[p] || p.Types == nil {
+			return
+		}
+		visited[p] = true
+		scope := p.Types.Scope()
+		for _, name := range scope.Names() {
+			if tn, ok := scope.Lookup(name).(*types.TypeName); ok {
+				add(tn.Type())
+			}
+		}
+		if workspace[p] && p.TypesInfo != nil {
+			for _, tv := range p.TypesInfo.Types {
+				if tv.IsType() {
+					add(tv.Type)
+				}
+			}
+		}
+		for _, imp := range p.Imports {
+			visit(imp)
+		}
+	}
+	for _, pkg := range r.prog.Packages {
+		visit(pkg)
+	}
+	return byName
+}
+
+// dead returns the declarations without references, except those kept
+// alive otherwise or by a used const in their block, and the unreachable
+// ones, in source order
+func (r *references) dead() []*candidate {
+	usedGroups := make(map[string]bool)
+	for _, c := range r.candidates {
+		if c.group != "" && c.sym.References > 0 {
+			usedGroups[c.group] = true
+		}
+	}
+
+	var dead []*candidate
+	for _, key := range r.order {
+		c := r.candidates[key]
+		if (c.sym.References > 0 || c.alive || usedGroups[c.group]) && !c.sym.Unreachable {
+			continue
+		}
+		dead = append(dead, c)
+	}
+	return dead
+}
+
+// importable reports whether code outside the module can import a
+// package: it is not a main package and not under an internal directory
+func importable(pkg *types.Package) bool {
+	if pkg.Name() == "main" {
+		return false
+	}
+	path := "/" + pkg.Path() + "/"
+	return !strings.Contains(path, "/internal/")
+}
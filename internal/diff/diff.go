@@ -0,0 +1,171 @@
+// Package diff produces unified diffs of edited files
+package diff
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// context is the number of unchanged lines around each change
+const context = 3
+
+// Edit replaces the bytes [Start, End) of a file with New
+type Edit struct {
+	Start, End int
+	New        string
+}
+
+// Apply applies non-overlapping edits to src
+func Apply(src []byte, edits []Edit) ([]byte, error) {
+	sorted := append([]Edit{}, edits...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Start < sorted[j].Start })
+
+	var out strings.Builder
+	last := 0
+	for _, e := range sorted {
+		if e.Start < last || e.End < e.Start || e.End > len(src) {
+			return nil, fmt.Errorf("invalid or overlapping edit at offset %d", e.Start)
+		}
+		out.Write(src[last:e.Start])
+		out.WriteString(e.New)
+		last = e.End
+	}
+	out.Write(src[last:])
+	return []byte(out.String()), nil
+}
+
+// Unified returns the unified diff turning before into after for a file
+// path relative to the workspace root, or "" when they are equal
+func Unified(path string, before, after []byte) string {
+	a, b := splitLines(string(before)), splitLines(string(after))
+	ops := lineOps(a, b)
+
+	var out strings.Builder
+	for start := 0; start < len(ops); {
+		// Find the next change and the extent of its hunk
+		for start < len(ops) && ops[start].kind == ' ' {
+			start++
+		}
+		if start == len(ops) {
+			break
+		}
+		first := max(start-context, 0)
+		end := start
+		for i := start; i < len(ops); i++ {
+			if ops[i].kind != ' ' {
+				end = i + 1
+			} else if i-end >= 2*context {
+				break
+			}
+		}
+		last := min(end+context, len(ops))
+
+		if out.Len() == 0 {
+			fmt.Fprintf(&out, "--- a/%s\n+++ b/%s\n", path, path)
+		}
+		oldStart, newStart := ops[first].a+1, ops[first].b+1
+		var oldCount, newCount int
+		var body strings.Builder
+		for _, op := range ops[first:last] {
+			switch op.kind {
+			case ' ':
+				oldCount++
+				newCount++
+				body.WriteString(" " + a[op.a])
+			case '-':
+				oldCount++
+				body.WriteString("-" + a[op.a])
+			case '+':
+				newCount++
+				body.WriteString("+" + b[op.b])
+			}
+			if !strings.HasSuffix(body.String(), "\n") {
+				body.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+		if oldCount == 0 {
+			oldStart--
+		}
+		if newCount == 0 {
+			newStart--
+		}
+		fmt.Fprintf(&out, "@@ -%d,%d +%d,%d @@\n%s", oldStart, oldCount, newStart, newCount, body.String())
+		start = last
+	}
+	return out.String()
+}
+
+// op is one line of an edit script; a and b are the line indexes in the
+// old and new text where the line is, or would be, found
+type op struct {
+	kind byte
+	a, b int
+}
+
+// lineOps computes a shortest edit script. Common leading and trailing
+// lines are stripped first, so the quadratic search only covers the
+// changed region.
+func lineOps(a, b []string) []op {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	ma, mb := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+
+	// lcs[i][j] is the length of the longest common subsequence of
+	// ma[i:] and mb[j:]
+	lcs := make([][]int, len(ma)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(mb)+1)
+	}
+	for i := len(ma) - 1; i >= 0; i-- {
+		for j := len(mb) - 1; j >= 0; j-- {
+			if ma[i] == mb[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var ops []op
+	for i := 0; i < prefix; i++ {
+		ops = append(ops, op{' ', i, i})
+	}
+	i, j := 0, 0
+	for i < len(ma) || j < len(mb) {
+		switch {
+		case i < len(ma) && j < len(mb) && ma[i] == mb[j]:
+			ops = append(ops, op{' ', prefix + i, prefix + j})
+			i++
+			j++
+		case j == len(mb) || (i < len(ma) && lcs[i+1][j] >= lcs[i][j+1]):
+			ops = append(ops, op{'-', prefix + i, prefix + j})
+			i++
+		default:
+			ops = append(ops, op{'+', prefix + i, prefix + j})
+			j++
+		}
+	}
+	for k := 0; k < suffix; k++ {
+		ops = append(ops, op{' ', len(a) - suffix + k, len(b) - suffix + k})
+	}
+	return ops
+}
+
+// splitLines splits text into lines that keep their newline
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
@@ -0,0 +1,64 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestApply(t *testing.T) {
+	src := []byte("hello world")
+	got, err := Apply(src, []Edit{{Start: 6, End: 11, New: "there"}, {Start: 0, End: 5, New: "hi"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "hi there" {
+		t.Errorf("Expected %q, got %q", "hi there", got)
+	}
+
+	if _, err := Apply(src, []Edit{{Start: 0, End: 5}, {Start: 3, End: 6}}); err == nil {
+		t.Error("Expected error for overlapping edits")
+	}
+}
+
+func TestUnified(t *testing.T) {
+	var lines []string
+	for _, l := range "abcdefghijklmnopqrst" {
+		lines = append(lines, string(l))
+	}
+	before := strings.Join(lines, "\n") + "\n"
+	lines[1] = "B"
+	lines = append(lines[:15], append([]string{"inserted"}, lines[15:]...)...)
+	after := strings.Join(lines, "\n") + "\n"
+
+	want := `--- a/x.txt
++++ b/x.txt
+@@ -1,5 +1,5 @@
+ a
+-b
++B
+ c
+ d
+ e
+@@ -13,6 +13,7 @@
+ m
+ n
+ o
++inserted
+ p
+ q
+ r
+`
+	if got := Unified("x.txt", []byte(before), []byte(after)); got != want {
+		t.Errorf("Unexpected diff:\n%s", got)
+	}
+	if got := Unified("x.txt", []byte(before), []byte(before)); got != "" {
+		t.Errorf("Expected no diff for equal input, got:\n%s", got)
+	}
+}
+
+func TestUnifiedNoNewline(t *testing.T) {
+	want := "--- a/x\n+++ b/x\n@@ -1,1 +1,1 @@\n-a\n\\ No newline at end of file\n+b\n\\ No newline at end of file\n"
+	if got := Unified("x", []byte("a"), []byte("b")); got != want {
+		t.Errorf("Unexpected diff:\n%q", got)
+	}
+}
@@ -2,9 +2,17 @@ package engine
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"path/filepath"
+	"strings"
 	"time"
 
+	"github.com/Siddhant-K-code/sentinel-ai/internal/baseline"
+	"github.com/Siddhant-K-code/sentinel-ai/internal/cache"
+	"github.com/Siddhant-K-code/sentinel-ai/internal/fixers"
+	"github.com/Siddhant-K-code/sentinel-ai/internal/goanalysis"
+	"github.com/Siddhant-K-code/sentinel-ai/internal/parallel"
 	"github.com/Siddhant-K-code/sentinel-ai/internal/policy"
 	"github.com/Siddhant-K-code/sentinel-ai/internal/security"
 	"github.com/Siddhant-K-code/sentinel-ai/internal/deadcode"
@@ -18,12 +26,17 @@ type Options struct {
 	AgentPath string
 	Policy    policy.Policy
 	LogPath   string
+	// CoverProfile is a Go coverage profile for dead-code detection
+	CoverProfile string
 }
 
 // ScanOpts defines scan operation options
 type ScanOpts struct {
-	Security bool
-	DeadCode bool
+	Security    bool
+	DeadCode    bool
+	Concurrency int // overrides policy limits.max_concurrency when positive
+	Baseline    *baseline.Baseline // when set, only new findings are reported
+	FailOn      string // severity threshold replacing the policy's security gates
 }
 
 // ScanResult represents the result of a scan operation
@@ -32,14 +45,40 @@ type ScanResult struct {
 	Plan      Plan
 	ExitCode  int
 	Summary   string
+
+	Security []security.ScanResult
+	DeadCode *deadcode.DeadCodeResult
+	Fixed    []baseline.Entry // baseline entries no longer reported
+	Gates    []GateResult
 }
 
 // Plan represents a planned set of changes
 type Plan struct {
 	Steps    []Step `json:"steps"`
+	Patches  []Patch `json:"patches,omitempty"`
 	Metadata Metadata `json:"metadata"`
 }
 
+// Patch sources, in order of preference
+const (
+	PatchSourceAnalyzer = "analyzer" // the analyzer reported a fix
+	PatchSourceFixer    = "fixer"    // a deterministic fixer wrote it
+	PatchSourceLLM      = "llm"      // left for the model to write
+)
+
+// Patch is the fix planned for one finding; Diff is empty while the LLM
+// has yet to write it
+type Patch struct {
+	RuleID      string `json:"rule_id"`
+	File        string `json:"file"`
+	Line        int    `json:"line,omitempty"`
+	Fingerprint string `json:"fingerprint,omitempty"`
+	Source      string `json:"source"`
+	Fixer       string `json:"fixer,omitempty"`
+	Description string `json:"description,omitempty"`
+	Diff        string `json:"diff,omitempty"`
+}
+
 // Step represents a single step in the plan
 type Step struct {
 	Name         string   `json:"name"`
@@ -83,13 +122,23 @@ type Engine struct {
 	runner      *tools.Runner
 	scanner     *security.Scanner
 	detector    *deadcode.Detector
+	programs    *goanalysis.Loader
 	auditLogger *logging.AuditLogger
 }
 
 // New creates a new engine instance
 func New(ctx context.Context, opts Options) (*Engine, error) {
 	// Create tool runner
-	runner := tools.NewRunner(opts.Policy.Allowlist.Commands, time.Duration(opts.Policy.Modes["default"].MaxRuntimeSec)*time.Second)
+	runner := tools.NewRunner(opts.Repo, opts.Policy.Allowlist.Commands, time.Duration(opts.Policy.Modes["default"].MaxRuntimeSec)*time.Second)
+
+	// Reuse tool results across runs when enabled
+	if opts.Policy.Cache.Enabled {
+		c, err := cache.Open(opts.Policy.Cache.Dir)
+		if err != nil {
+			return nil, err
+		}
+		runner.EnableCache(c)
+	}
 
 	// Create audit logger
 	auditLogger, err := logging.NewAuditLogger(opts.LogPath, opts.Policy.Logging.PIIRedaction)
@@ -97,11 +146,24 @@ func New(ctx context.Context, opts Options) (*Engine, error) {
 		return nil, err
 	}
 
+	// Analyzers of one scan share the Go packages they load
+	programs := goanalysis.NewLoader(runner)
+
 	// Create security scanner
 	scanner := security.NewScanner(runner, opts.Repo)
+	scanner.SharePrograms(programs)
+	if err := scanner.Configure(opts.Policy, opts.Policy.Security.Analyzers); err != nil {
+		return nil, err
+	}
 
 	// Create dead code detector
-	detector := deadcode.NewDetector(runner, opts.Repo)
+	detector := deadcode.NewDetector(runner, opts.Repo, deadcode.Options{
+		Tags:         opts.Policy.DeadCode.Tags,
+		Network:      opts.Policy.Modes["default"].Network,
+		Reachability: opts.Policy.DeadCode.Mode == policy.DeadCodeReachability,
+		CoverProfile: opts.CoverProfile,
+		Programs:     programs,
+	})
 
 	return &Engine{
 		options:     opts,
@@ -109,29 +171,123 @@ func New(ctx context.Context, opts Options) (*Engine, error) {
 		runner:      runner,
 		scanner:     scanner,
 		detector:    detector,
+		programs:    programs,
 		auditLogger: auditLogger,
 	}, nil
 }
 
-// Scan performs security and dead-code scanning
+// analysis holds the output of one analyzer task
+type analysis struct {
+	security *security.ScanResult
+	deadcode *deadcode.DeadCodeResult
+}
+
+// Scan performs security and dead-code scanning. Every security analyzer
+// and the dead-code detector run as tasks of one pool under the default
+// mode's runtime limit; when the deadline hits, results from analyzers
+// that already finished are still reported, and the gates of sections
+// that did not finish fail.
 func (e *Engine) Scan(ctx context.Context, opts ScanOpts) (*ScanResult, error) {
 	start := time.Now()
 	var sarifData []byte
 	var plan Plan
-	exitCode := 0
 	summary := "Scan completed successfully"
 
-	// Run security scanning if requested
+	gates, err := scanGates(e.policy, opts.FailOn)
+	if err != nil {
+		return nil, err
+	}
+
+	// Loaded programs are only valid for this scan
+	defer e.programs.Reset()
+
+	// Apply the global deadline
+	if maxRuntime := e.policy.Modes["default"].MaxRuntimeSec; maxRuntime > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(maxRuntime)*time.Second)
+		defer cancel()
+	}
+
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = e.policy.Limits.MaxConcurrency
+	}
+
+	// Security analyzers come first, then the dead-code detector
+	var tasks []parallel.Task[analysis]
 	if opts.Security {
 		e.auditLogger.LogToolCall("security", "scanner", []string{"security", "scan"}, 0, "started", nil)
+		for _, task := range e.scanner.Tasks() {
+			task := task
+			tasks = append(tasks, parallel.Task[analysis]{Name: task.Name, Run: func(ctx context.Context) (analysis, error) {
+				result, err := task.Run(ctx)
+				return analysis{security: result}, err
+			}})
+		}
+	}
+	analyzers := len(tasks)
+	if opts.DeadCode {
+		tasks = append(tasks, parallel.Task[analysis]{Name: "deadcode", Run: func(ctx context.Context) (analysis, error) {
+			e.auditLogger.LogToolCall("deadcode", "detector", []string{"deadcode", "detect"}, 0, "started", nil)
+			result, err := e.detector.Detect(ctx)
+			return analysis{deadcode: result}, err
+		}})
+	}
 
-		securityResults, err := e.scanner.Scan(ctx)
-		if err != nil {
-			e.auditLogger.LogToolCall("security", "scanner", []string{"security", "scan"}, time.Since(start), "error", err)
-			return nil, err
+	var (
+		securityResults []security.ScanResult
+		deadCodeResult  *deadcode.DeadCodeResult
+		scans           []parallel.Result[*security.ScanResult]
+		timedOut        []string
+		incomplete      = make(map[string]bool)
+	)
+	for i, r := range parallel.Run(ctx, concurrency, tasks) {
+		step, tool, kind := r.Name, "scanner", policy.GateSecurity
+		if i >= analyzers {
+			tool, kind = "detector", policy.GateDeadCode
 		}
 
-		// Generate SARIF output
+		if !r.Done || errors.Is(r.Err, context.DeadlineExceeded) {
+			e.auditLogger.LogToolCall(step, tool, []string{step}, r.Duration, "timeout", r.Err)
+			timedOut = append(timedOut, step)
+			incomplete[kind] = true
+		} else if r.Err != nil {
+			e.auditLogger.LogToolCall(step, tool, []string{step}, r.Duration, "error", r.Err)
+			return nil, r.Err
+		}
+
+		if kind == policy.GateSecurity {
+			scans = append(scans, parallel.Result[*security.ScanResult]{
+				Name:     r.Name,
+				Value:    r.Value.security,
+				Err:      r.Err,
+				Duration: r.Duration,
+				Done:     r.Done,
+			})
+		} else if r.Value.deadcode != nil && !incomplete[kind] {
+			deadCodeResult = r.Value.deadcode
+		}
+	}
+	if opts.Security {
+		securityResults = e.scanner.Collect(scans)
+		if securityResults == nil {
+			securityResults = []security.ScanResult{}
+		}
+	}
+
+	// Drop accepted findings so that only new ones count
+	var fixed []baseline.Entry
+	if opts.Baseline != nil {
+		fixed = append(fixed, opts.Baseline.FilterSecurity(securityResults)...)
+		fixed = append(fixed, opts.Baseline.FilterDeadCode(deadCodeResult)...)
+	}
+
+	// Fixes are attached before SARIF generation so they are reported
+	patches := e.planPatches(securityResults)
+
+	// Report security findings
+	if securityResults != nil {
+		var err error
 		sarifData, err = e.scanner.GenerateSARIF(securityResults)
 		if err != nil {
 			e.auditLogger.LogToolCall("security", "scanner", []string{"sarif", "generate"}, time.Since(start), "error", err)
@@ -141,35 +297,51 @@ func (e *Engine) Scan(ctx context.Context, opts ScanOpts) (*ScanResult, error) {
 		// Count findings
 		totalFindings := 0
 		for _, result := range securityResults {
-			totalFindings += len(result.Findings)
+			for _, finding := range result.Findings {
+				if finding.Actionable() {
+					totalFindings++
+				}
+			}
 		}
 
 		e.auditLogger.LogScanResult("security", totalFindings, time.Since(start))
 
 		if totalFindings > 0 {
-			exitCode = 10 // Security findings present
 			summary = fmt.Sprintf("Found %d security findings", totalFindings)
+			if opts.Baseline != nil {
+				summary = fmt.Sprintf("Found %d new security findings", totalFindings)
+			}
 		}
 	}
 
-	// Run dead code detection if requested
-	if opts.DeadCode {
-		e.auditLogger.LogToolCall("deadcode", "detector", []string{"deadcode", "detect"}, 0, "started", nil)
-
-		deadCodeResult, err := e.detector.Detect(ctx)
-		if err != nil {
-			e.auditLogger.LogToolCall("deadcode", "detector", []string{"deadcode", "detect"}, time.Since(start), "error", err)
-			return nil, err
-		}
-
+	// Report dead code
+	if deadCodeResult != nil {
 		e.auditLogger.LogScanResult("deadcode", len(deadCodeResult.Symbols), time.Since(start))
 
 		if len(deadCodeResult.Symbols) > 0 {
-			if exitCode == 0 {
-				exitCode = 11 // Dead code found
-			}
 			summary = fmt.Sprintf("%s; Found %d dead code symbols", summary, len(deadCodeResult.Symbols))
 		}
+		if deadCodeResult.Warning != "" {
+			summary = fmt.Sprintf("%s; dead code: %s", summary, deadCodeResult.Warning)
+		}
+		if deadCodeResult.CoverageError != "" {
+			summary = fmt.Sprintf("%s; coverage failed: %s", summary, deadCodeResult.CoverageError)
+		}
+	}
+
+	if len(fixed) > 0 {
+		summary = fmt.Sprintf("%s; %d baseline findings fixed", summary, len(fixed))
+	}
+
+	if len(timedOut) > 0 {
+		summary = fmt.Sprintf("%s; timed out: %s", summary, strings.Join(timedOut, ", "))
+	}
+
+	// Gates decide the exit code
+	gateResults := evaluateGates(gates, securityResults, deadCodeResult, incomplete)
+	exitCode := gateExitCode(gateResults)
+	for _, line := range gateSummary(gateResults) {
+		summary = fmt.Sprintf("%s; %s", summary, line)
 	}
 
 	// Create a basic plan
@@ -188,6 +360,7 @@ func (e *Engine) Scan(ctx context.Context, opts ScanOpts) (*ScanResult, error) {
 			TotalTokens:     1000,
 		},
 	}
+	addPatchSteps(&plan, patches)
 
 	// If no SARIF data was generated, create empty SARIF
 	if sarifData == nil {
@@ -199,9 +372,88 @@ func (e *Engine) Scan(ctx context.Context, opts ScanOpts) (*ScanResult, error) {
 		Plan:     plan,
 		ExitCode: exitCode,
 		Summary:  summary,
+		Security: securityResults,
+		DeadCode: deadCodeResult,
+		Fixed:    fixed,
+		Gates:    gateResults,
 	}, nil
 }
 
+// llmFixBudget is the token budget for the model to fix one finding
+const llmFixBudget = 2000
+
+// planPatches picks a patch source for every actionable finding: a fix
+// reported by the analyzer, then a deterministic fixer, then the LLM.
+// Fixer patches are attached to their findings.
+func (e *Engine) planPatches(results []security.ScanResult) []Patch {
+	var patches []Patch
+	for r := range results {
+		for i := range results[r].Findings {
+			finding := &results[r].Findings[i]
+			if !finding.Actionable() {
+				continue
+			}
+			patch := Patch{
+				RuleID:      finding.RuleID,
+				File:        filepath.ToSlash(finding.File),
+				Line:        finding.Line,
+				Fingerprint: finding.Fingerprint,
+				Source:      PatchSourceLLM,
+			}
+
+			if finding.Fix == nil {
+				name, fix, err := fixers.Fix(e.options.Repo, *finding)
+				switch {
+				case err == nil:
+					finding.Fix = fix
+					patch.Source, patch.Fixer = PatchSourceFixer, name
+				case !errors.Is(err, fixers.ErrNotApplicable):
+					e.auditLogger.LogToolCall("fix", "fixers", []string{finding.RuleID, finding.File}, 0, "error", err)
+				}
+			} else {
+				patch.Source = PatchSourceAnalyzer
+			}
+			if finding.Fix != nil {
+				patch.Description, patch.Diff = finding.Fix.Description, finding.Fix.Diff
+			}
+			patches = append(patches, patch)
+		}
+	}
+	return patches
+}
+
+// addPatchSteps adds the patches to the plan, with a step for the
+// deterministic ones and a budgeted step for those the LLM must write
+func addPatchSteps(plan *Plan, patches []Patch) {
+	plan.Patches = patches
+
+	deterministic, llm := 0, 0
+	for _, p := range patches {
+		if p.Source == PatchSourceLLM {
+			llm++
+		} else {
+			deterministic++
+		}
+	}
+	if deterministic > 0 {
+		plan.Steps = append(plan.Steps, Step{
+			Name:  "deterministic-fixes",
+			Why:   fmt.Sprintf("Apply %d patches from analyzers and deterministic fixers", deterministic),
+			Tools: []string{"patcher"},
+		})
+	}
+	if llm > 0 {
+		budget := llm * llmFixBudget
+		plan.Steps = append(plan.Steps, Step{
+			Name:         "llm-fixes",
+			Why:          fmt.Sprintf("Write patches for %d findings without a deterministic fixer", llm),
+			BudgetTokens: budget,
+			Tools:        []string{"llm", "patcher"},
+		})
+		plan.Metadata.TotalTokens += budget
+	}
+}
+
 // Apply applies patches from a plan
 func (e *Engine) Apply(ctx context.Context, plan Plan, approveLevel string) (*ApplyResult, error) {
 	// TODO: Implement patch application logic
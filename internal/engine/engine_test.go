@@ -0,0 +1,109 @@
+package engine
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Siddhant-K-code/sentinel-ai/internal/policy"
+	"github.com/Siddhant-K-code/sentinel-ai/internal/security"
+)
+
+// timingAnalyzer reports one high finding, none when quiet, or blocks
+// until ctx is done
+type timingAnalyzer struct {
+	name  string
+	slow  bool
+	quiet bool
+}
+
+func (a *timingAnalyzer) Name() string        { return a.name }
+func (a *timingAnalyzer) Languages() []string { return nil }
+func (a *timingAnalyzer) Available() error    { return nil }
+
+func (a *timingAnalyzer) Run(ctx context.Context) *security.ScanResult {
+	if a.slow {
+		<-ctx.Done()
+		return &security.ScanResult{Tool: a.name, Findings: []security.Finding{}, Error: ctx.Err().Error()}
+	}
+	if a.quiet {
+		return &security.ScanResult{Tool: a.name, Findings: []security.Finding{}}
+	}
+	return &security.ScanResult{Tool: a.name, Findings: []security.Finding{
+		{RuleID: "fast/rule", File: "main.go", Line: 1, Severity: "high", Message: "finding"},
+	}}
+}
+
+func init() {
+	for _, name := range []string{"enginetest-fast", "enginetest-slow", "enginetest-quiet"} {
+		name := name
+		security.Register(name, func(security.Env, policy.AnalyzerConfig) (security.Analyzer, error) {
+			return &timingAnalyzer{name: name, slow: name == "enginetest-slow", quiet: name == "enginetest-quiet"}, nil
+		}, false)
+	}
+}
+
+func TestScanTimeoutKeepsFinishedAnalyzers(t *testing.T) {
+	pol := policy.DefaultPolicy()
+	mode := pol.Modes["default"]
+	mode.MaxRuntimeSec = 1
+	pol.Modes["default"] = mode
+	pol.Security.Analyzers = map[string]policy.AnalyzerConfig{
+		"enginetest-fast": {Enabled: true},
+		"enginetest-slow": {Enabled: true},
+	}
+	pol.Gates = []policy.Gate{{Name: "critical", Kind: policy.GateSecurity, MinSeverity: "critical"}}
+
+	root := t.TempDir()
+	e, err := New(context.Background(), Options{Repo: root, Policy: pol, LogPath: filepath.Join(root, "audit.log")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := e.Scan(context.Background(), ScanOpts{Security: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	found := false
+	for _, r := range res.Security {
+		if r.Tool == "enginetest-fast" && len(r.Findings) == 1 {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected the finished analyzer's finding to be kept, got %+v", res.Security)
+	}
+	// The gate counts nothing critical, but the scan is incomplete
+	if len(res.Gates) != 1 || !res.Gates[0].Incomplete || res.ExitCode != ExitSecurity {
+		t.Errorf("Expected the incomplete security gate to fail, got exit %d and %+v", res.ExitCode, res.Gates)
+	}
+	if !strings.Contains(res.Summary, "timed out: enginetest-slow") {
+		t.Errorf("Expected the summary to name the timed out analyzer, got %q", res.Summary)
+	}
+}
+
+func TestFailOnInvalidSuppression(t *testing.T) {
+	pol := policy.DefaultPolicy()
+	pol.Security.Analyzers = map[string]policy.AnalyzerConfig{"enginetest-quiet": {Enabled: true}}
+
+	root := t.TempDir()
+	src := "package main\n\nfunc main() {} // sentinel:ignore go/xss until=2999-01-01\n"
+	if err := os.WriteFile(filepath.Join(root, "main.go"), []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+	e, err := New(context.Background(), Options{Repo: root, Policy: pol, LogPath: filepath.Join(root, "audit.log")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := e.Scan(context.Background(), ScanOpts{Security: true, FailOn: "medium"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The reason-less suppression is a medium finding on the canonical scale
+	if len(res.Gates) != 1 || !res.Gates[0].Tripped || res.ExitCode != ExitSecurity {
+		t.Errorf("Expected --fail-on medium to trip on the invalid suppression, got exit %d and %+v", res.ExitCode, res.Gates)
+	}
+}
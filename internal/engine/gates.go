@@ -0,0 +1,291 @@
+package engine
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/Siddhant-K-code/sentinel-ai/internal/deadcode"
+	"github.com/Siddhant-K-code/sentinel-ai/internal/policy"
+	"github.com/Siddhant-K-code/sentinel-ai/internal/security"
+)
+
+// Exit codes for tripped fail gates
+const (
+	ExitSecurity = 10
+	ExitDeadCode = 11
+)
+
+// GateResult is the outcome of one gate
+type GateResult struct {
+	Gate    policy.Gate `json:"gate"`
+	Count   int         `json:"count"`
+	Tripped bool        `json:"tripped"`
+	// Reason explains what was counted, e.g. "3 security findings with
+	// severity critical or high, more than 0 allowed"
+	Reason string `json:"reason"`
+	// Incomplete is set when the analysis the gate checks timed out; the
+	// count may be missing findings, so the gate fails whatever its action
+	Incomplete bool `json:"incomplete,omitempty"`
+}
+
+// Failed reports whether the gate tripped and fails the scan
+func (r GateResult) Failed() bool {
+	return r.Incomplete || r.Tripped && r.Gate.Action != policy.GateWarn
+}
+
+// scanGates returns the policy's gates, or the defaults, with the security
+// gates replaced by a --fail-on threshold when one is given
+func scanGates(pol policy.Policy, failOn string) ([]policy.Gate, error) {
+	gates := pol.Gates
+	if len(gates) == 0 {
+		gates = policy.DefaultGates()
+	}
+	if failOn == "" {
+		return gates, nil
+	}
+
+	gate, err := policy.FailOnGate(failOn)
+	if err != nil {
+		return nil, err
+	}
+	result := []policy.Gate{gate}
+	for _, g := range gates {
+		if g.Kind != policy.GateSecurity {
+			result = append(result, g)
+		}
+	}
+	return result, nil
+}
+
+// evaluateGates checks each gate against the findings it applies to.
+// Security gates are skipped when security was not scanned, and dead-code
+// gates when dead code was not. Gates of a kind in incomplete, whose
+// analysis timed out, trip and fail.
+func evaluateGates(gates []policy.Gate, results []security.ScanResult, dead *deadcode.DeadCodeResult, incomplete map[string]bool) []GateResult {
+	var out []GateResult
+	for _, g := range gates {
+		count, ran := 0, false
+		switch g.Kind {
+		case policy.GateSecurity:
+			ran = results != nil
+			for _, r := range results {
+				for _, f := range r.Findings {
+					if f.Actionable() && matchFinding(g, f) {
+						count++
+					}
+				}
+			}
+		case policy.GateDeadCode:
+			ran = dead != nil
+			if dead != nil {
+				for _, s := range dead.Symbols {
+					if matchSymbol(g, s) {
+						count++
+					}
+				}
+			}
+		default:
+			continue
+		}
+		if !ran && !incomplete[g.Kind] {
+			continue
+		}
+
+		result := GateResult{
+			Gate:    g,
+			Count:   count,
+			Tripped: count > g.Max,
+			Reason:  fmt.Sprintf("%d %s, more than %d allowed", count, describeGate(g), g.Max),
+		}
+		if incomplete[g.Kind] {
+			result.Tripped, result.Incomplete = true, true
+			result.Reason = fmt.Sprintf("%s analysis timed out, results are incomplete (%s)", g.Kind, result.Reason)
+		}
+		out = append(out, result)
+	}
+	return out
+}
+
+// gateExitCode is 10 when a security gate fails, else 11 when a dead-code
+// gate fails, else 0
+func gateExitCode(results []GateResult) int {
+	code := 0
+	for _, r := range results {
+		if !r.Failed() {
+			continue
+		}
+		if r.Gate.Kind == policy.GateSecurity {
+			return ExitSecurity
+		}
+		code = ExitDeadCode
+	}
+	return code
+}
+
+// gateSummary lists the gates that failed or warned, and why
+func gateSummary(results []GateResult) []string {
+	var lines []string
+	for _, r := range results {
+		if !r.Tripped {
+			continue
+		}
+		verdict := "failed"
+		if !r.Failed() {
+			verdict = "warned"
+		}
+		lines = append(lines, fmt.Sprintf("gate %s %s: %s", gateName(r.Gate), verdict, r.Reason))
+	}
+	return lines
+}
+
+// gateName is the gate's name, or its kind when unnamed
+func gateName(g policy.Gate) string {
+	if g.Name != "" {
+		return g.Name
+	}
+	return g.Kind
+}
+
+// matchFinding reports whether a finding passes the gate's filters
+func matchFinding(g policy.Gate, f security.Finding) bool {
+	if severities := gateSeverities(g); severities != nil && !severities[f.Severity] {
+		return false
+	}
+	if g.MinConfidence != "" && confidenceRank(f.Confidence) < confidenceRank(g.MinConfidence) {
+		return false
+	}
+	if len(g.Rules) > 0 && !matchRule(g.Rules, f.RuleID) {
+		return false
+	}
+	return !g.ExcludeTests || !isTestPath(f.File)
+}
+
+// matchSymbol reports whether a dead-code symbol passes the gate's filters
+func matchSymbol(g policy.Gate, s deadcode.Symbol) bool {
+	if g.ExportedOnly && !s.Exported {
+		return false
+	}
+	if len(g.SymbolKinds) > 0 && !contains(g.SymbolKinds, s.Kind) {
+		return false
+	}
+	return !g.ExcludeTests || !isTestPath(s.File)
+}
+
+// gateSeverities is the set of severities a gate counts, or nil for all
+func gateSeverities(g policy.Gate) map[string]bool {
+	if len(g.Severities) == 0 && g.MinSeverity == "" {
+		return nil
+	}
+	set := make(map[string]bool)
+	for _, s := range g.Severities {
+		set[s] = true
+	}
+	if g.MinSeverity != "" {
+		for _, s := range policy.Severities {
+			set[s] = true
+			if s == g.MinSeverity {
+				break
+			}
+		}
+	}
+	return set
+}
+
+// confidenceRank orders confidence levels. Findings whose tool does not
+// report a confidence are not filtered out.
+func confidenceRank(confidence string) int {
+	switch strings.ToLower(confidence) {
+	case "low":
+		return 1
+	case "medium":
+		return 2
+	}
+	return 3
+}
+
+// matchRule reports whether ruleID is one of the rule IDs or patterns
+func matchRule(rules []string, ruleID string) bool {
+	for _, rule := range rules {
+		if ok, _ := path.Match(rule, ruleID); ok || rule == ruleID {
+			return true
+		}
+	}
+	return false
+}
+
+// testDirs are directory names that hold test code
+var testDirs = map[string]bool{"test": true, "tests": true, "testdata": true, "__tests__": true, "spec": true}
+
+// isTestPath reports whether file is test code: Go, Python and JavaScript
+// test files, and files under test directories
+func isTestPath(file string) bool {
+	file = filepath.ToSlash(file)
+	base := path.Base(file)
+	switch {
+	case strings.HasSuffix(base, "_test.go"),
+		strings.HasSuffix(base, "_test.py"),
+		strings.HasPrefix(base, "test_") && strings.HasSuffix(base, ".py"),
+		strings.Contains(base, ".test."),
+		strings.Contains(base, ".spec."):
+		return true
+	}
+	for _, dir := range strings.Split(path.Dir(file), "/") {
+		if testDirs[dir] {
+			return true
+		}
+	}
+	return false
+}
+
+// describeGate says what a gate counts, e.g. "security findings with
+// severity critical or high and confidence at least medium outside tests"
+func describeGate(g policy.Gate) string {
+	var b strings.Builder
+	if g.Kind == policy.GateDeadCode {
+		b.WriteString("dead")
+		if g.ExportedOnly {
+			b.WriteString(" exported")
+		}
+		b.WriteString(" symbols")
+		if len(g.SymbolKinds) > 0 {
+			b.WriteString(" of kind " + strings.Join(g.SymbolKinds, " or "))
+		}
+	} else {
+		b.WriteString("security findings")
+		var filters []string
+		if severities := gateSeverities(g); severities != nil {
+			var names []string
+			for _, s := range policy.Severities {
+				if severities[s] {
+					names = append(names, s)
+				}
+			}
+			filters = append(filters, "severity "+strings.Join(names, " or "))
+		}
+		if g.MinConfidence != "" {
+			filters = append(filters, "confidence at least "+g.MinConfidence)
+		}
+		if len(g.Rules) > 0 {
+			filters = append(filters, "rule "+strings.Join(g.Rules, " or "))
+		}
+		if len(filters) > 0 {
+			b.WriteString(" with " + strings.Join(filters, " and "))
+		}
+	}
+	if g.ExcludeTests {
+		b.WriteString(" outside tests")
+	}
+	return b.String()
+}
+
+// contains reports whether list holds s
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,126 @@
+package engine
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Siddhant-K-code/sentinel-ai/internal/deadcode"
+	"github.com/Siddhant-K-code/sentinel-ai/internal/policy"
+	"github.com/Siddhant-K-code/sentinel-ai/internal/security"
+)
+
+func gateFixtures() ([]security.ScanResult, *deadcode.DeadCodeResult) {
+	results := []security.ScanResult{{Tool: "gotaint", Findings: []security.Finding{
+		{RuleID: "go/sql-injection", File: "db/query.go", Severity: "critical", Confidence: "high"},
+		{RuleID: "go/xss", File: "web/handler.go", Severity: "high", Confidence: "low"},
+		{RuleID: "go/xss", File: "web/handler_test.go", Severity: "high", Confidence: "high"},
+		{RuleID: "go/weak-hash", File: "auth/hash.go", Severity: "low"},
+		{RuleID: "go/command-injection", File: "run.go", Severity: "critical", BaselineState: "absent"},
+	}}}
+	dead := &deadcode.DeadCodeResult{Symbols: []deadcode.Symbol{
+		{Name: "Old", Kind: "func", File: "a.go", Exported: true},
+		{Name: "helper", Kind: "func", File: "a.go"},
+	}}
+	return results, dead
+}
+
+func TestEvaluateGates(t *testing.T) {
+	results, dead := gateFixtures()
+	gates := []policy.Gate{
+		{Name: "blocking", Kind: policy.GateSecurity, MinSeverity: "high", MinConfidence: "medium", ExcludeTests: true},
+		{Name: "dead-exported", Kind: policy.GateDeadCode, ExportedOnly: true, Max: 5},
+		{Name: "low", Kind: policy.GateSecurity, Severities: []string{"low"}, Action: policy.GateWarn},
+	}
+
+	got := evaluateGates(gates, results, dead, nil)
+	if len(got) != 3 {
+		t.Fatalf("Expected 3 gate results, got %+v", got)
+	}
+	if got[0].Count != 1 || !got[0].Failed() {
+		t.Errorf("Expected the blocking gate to fail on the SQL injection only, got %+v", got[0])
+	}
+	want := "1 security findings with severity critical or high and confidence at least medium outside tests, more than 0 allowed"
+	if got[0].Reason != want {
+		t.Errorf("Expected reason %q, got %q", want, got[0].Reason)
+	}
+	if got[1].Count != 1 || got[1].Tripped {
+		t.Errorf("Expected one dead exported symbol within the limit, got %+v", got[1])
+	}
+	if got[2].Count != 1 || !got[2].Tripped || got[2].Failed() {
+		t.Errorf("Expected the low gate to warn, got %+v", got[2])
+	}
+	if code := gateExitCode(got); code != ExitSecurity {
+		t.Errorf("Expected exit code %d, got %d", ExitSecurity, code)
+	}
+
+	summary := gateSummary(got)
+	if len(summary) != 2 || !strings.HasPrefix(summary[0], "gate blocking failed: 1 security findings") || !strings.HasPrefix(summary[1], "gate low warned: 1 security findings with severity low") {
+		t.Errorf("Unexpected summary %q", summary)
+	}
+
+	// Gates for analyses that did not run are skipped
+	if got := evaluateGates(gates, nil, dead, nil); len(got) != 1 || got[0].Gate.Name != "dead-exported" {
+		t.Errorf("Expected only the dead-code gate, got %+v", got)
+	}
+
+	// A timed out analysis fails its gates, even a warn gate
+	got = evaluateGates(gates, nil, nil, map[string]bool{policy.GateSecurity: true})
+	if len(got) != 2 || !got[0].Failed() || !got[1].Failed() || !got[1].Incomplete {
+		t.Errorf("Expected both security gates to fail as incomplete, got %+v", got)
+	}
+}
+
+func TestScanGates(t *testing.T) {
+	results, dead := gateFixtures()
+
+	// Without policy gates any finding fails, security before dead code
+	gates, err := scanGates(policy.DefaultPolicy(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code := gateExitCode(evaluateGates(gates, results, dead, nil)); code != ExitSecurity {
+		t.Errorf("Expected exit code %d, got %d", ExitSecurity, code)
+	}
+	if code := gateExitCode(evaluateGates(gates, []security.ScanResult{}, dead, nil)); code != ExitDeadCode {
+		t.Errorf("Expected exit code %d, got %d", ExitDeadCode, code)
+	}
+
+	// --fail-on replaces the security gates and keeps the others
+	pol := policy.DefaultPolicy()
+	pol.Gates = []policy.Gate{
+		{Name: "any", Kind: policy.GateSecurity},
+		{Name: "dead", Kind: policy.GateDeadCode, Action: policy.GateWarn},
+	}
+	gates, err = scanGates(pol, "critical")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(gates) != 2 || gates[0].Name != "fail-on" || gates[1].Name != "dead" {
+		t.Fatalf("Unexpected gates %+v", gates)
+	}
+	got := evaluateGates(gates, results, dead, nil)
+	if got[0].Count != 1 || gateExitCode(got) != ExitSecurity {
+		t.Errorf("Expected one critical finding to fail, got %+v", got)
+	}
+
+	if _, err := scanGates(pol, "severe"); err == nil {
+		t.Error("Expected error for an unknown --fail-on severity")
+	}
+}
+
+func TestIsTestPath(t *testing.T) {
+	tests := map[string]bool{
+		"pkg/a_test.go":             true,
+		"tests/test_api.py":         true,
+		"web/app.spec.ts":           true,
+		"internal/testdata/main.go": true,
+		"src/__tests__/x.js":        true,
+		"cmd/main.go":               false,
+		"contest/main.go":           false,
+	}
+	for file, want := range tests {
+		if got := isTestPath(file); got != want {
+			t.Errorf("isTestPath(%q) = %v, want %v", file, got, want)
+		}
+	}
+}
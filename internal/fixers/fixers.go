@@ -0,0 +1,305 @@
+// Package fixers holds deterministic codemods that turn a finding into a
+// patch without a model. Fixers are keyed by rule ID.
+package fixers
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/Siddhant-K-code/sentinel-ai/internal/diff"
+	"github.com/Siddhant-K-code/sentinel-ai/internal/security"
+)
+
+// ErrNotApplicable means the code at the finding does not have the shape
+// a fixer rewrites; the finding is left to the LLM
+var ErrNotApplicable = errors.New("fixer does not apply")
+
+// Fixer rewrites the code a finding points at
+type Fixer struct {
+	// Name identifies the fixer in plans
+	Name string
+	// Description is used for the fix when Fix does not return one
+	Description string
+	Fix         func(in *Input) ([]diff.Edit, error)
+}
+
+// Input is a finding and the parsed Go file it is reported in
+type Input struct {
+	Finding security.Finding
+	// Path is the file relative to the workspace root, with slashes
+	Path string
+	Fset *token.FileSet
+	File *ast.File
+	Src  []byte
+
+	// info holds the types of the file's package, loaded by TypeOf
+	info *types.Info
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Fixer)
+)
+
+// Register makes a fixer available for findings with ruleID. It is meant
+// to be called from init functions.
+func Register(ruleID string, f Fixer) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, dup := registry[ruleID]; dup {
+		panic("fixers: duplicate fixer for " + ruleID)
+	}
+	registry[ruleID] = f
+}
+
+// Lookup returns the fixer registered for ruleID
+func Lookup(ruleID string) (Fixer, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	f, ok := registry[ruleID]
+	return f, ok
+}
+
+// RuleIDs returns the rule IDs that have a fixer, sorted
+func RuleIDs() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	ids := make([]string, 0, len(registry))
+	for id := range registry {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Fix runs the fixer registered for a finding's rule against the file in
+// the workspace at root. It returns the fixer's name and the patch, or
+// ErrNotApplicable when no fixer exists or the code does not match.
+func Fix(root string, f security.Finding) (string, *security.Fix, error) {
+	fixer, ok := Lookup(f.RuleID)
+	if !ok || !strings.HasSuffix(f.File, ".go") || f.Line <= 0 {
+		return "", nil, ErrNotApplicable
+	}
+
+	rel := filepath.ToSlash(filepath.Clean(f.File))
+	if filepath.IsAbs(rel) || strings.HasPrefix(rel, "../") {
+		return "", nil, fmt.Errorf("%s is outside the workspace", f.File)
+	}
+	src, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(rel)))
+	if err != nil {
+		return "", nil, err
+	}
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filepath.Join(root, filepath.FromSlash(rel)), src, parser.ParseComments)
+	if err != nil {
+		return "", nil, err
+	}
+
+	in := &Input{Finding: f, Path: rel, Fset: fset, File: file, Src: src}
+	edits, err := fixer.Fix(in)
+	if err != nil {
+		return "", nil, err
+	}
+	fixed, err := diff.Apply(src, edits)
+	if err != nil {
+		return "", nil, err
+	}
+	patch := diff.Unified(rel, src, fixed)
+	if patch == "" {
+		return "", nil, ErrNotApplicable
+	}
+	return fixer.Name, &security.Fix{Description: fixer.Description, Diff: patch}, nil
+}
+
+// TypeOf returns the type of expr, or nil when it is unknown. The first
+// call type-checks the file's package, tolerating errors.
+func (in *Input) TypeOf(expr ast.Expr) types.Type {
+	if in.info == nil {
+		in.info = &types.Info{Types: make(map[ast.Expr]types.TypeAndValue)}
+		files := []*ast.File{in.File}
+		filename := in.Fset.Position(in.File.Package).Filename
+		others, _ := filepath.Glob(filepath.Join(filepath.Dir(filename), "*.go"))
+		for _, name := range others {
+			if name == filename || strings.HasSuffix(name, "_test.go") {
+				continue
+			}
+			f, err := parser.ParseFile(in.Fset, name, nil, 0)
+			if err == nil && f.Name.Name == in.File.Name.Name {
+				files = append(files, f)
+			}
+		}
+		conf := types.Config{Importer: fallbackImporter{importer.ForCompiler(in.Fset, "source", nil)}, Error: func(error) {}}
+		_, _ = conf.Check(in.File.Name.Name, in.Fset, files, in.info)
+	}
+	tv, ok := in.info.Types[expr]
+	if !ok || tv.Type == nil || tv.Type == types.Typ[types.Invalid] {
+		return nil
+	}
+	return tv.Type
+}
+
+// exportImporter imports from compiler export data, which the build cache
+// has for the standard library. It is shared so each package is read
+// once; the positions of imported objects are never used.
+var exportImporter = struct {
+	sync.Mutex
+	types.Importer
+}{Importer: importer.ForCompiler(token.NewFileSet(), "gc", nil)}
+
+// fallbackImporter tries export data first and type-checks other packages
+// from source
+type fallbackImporter struct {
+	source types.Importer
+}
+
+func (imp fallbackImporter) Import(path string) (*types.Package, error) {
+	exportImporter.Lock()
+	pkg, err := exportImporter.Import(path)
+	exportImporter.Unlock()
+	if err == nil {
+		return pkg, nil
+	}
+	return imp.source.Import(path)
+}
+
+// Offset returns the byte offset of pos in the file
+func (in *Input) Offset(pos token.Pos) int {
+	return in.Fset.Position(pos).Offset
+}
+
+// Text returns the source of a node
+func (in *Input) Text(node ast.Node) string {
+	return string(in.Src[in.Offset(node.Pos()):in.Offset(node.End())])
+}
+
+// Replace returns an edit replacing node with text
+func (in *Input) Replace(node ast.Node, text string) diff.Edit {
+	return diff.Edit{Start: in.Offset(node.Pos()), End: in.Offset(node.End()), New: text}
+}
+
+// Line returns the line of pos
+func (in *Input) Line(pos token.Pos) int {
+	return in.Fset.Position(pos).Line
+}
+
+// CallsAt returns the calls starting on the finding's line, the one at
+// its column first when it has one
+func (in *Input) CallsAt() []*ast.CallExpr {
+	var calls []*ast.CallExpr
+	ast.Inspect(in.File, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if ok && in.Line(call.Pos()) <= in.Finding.Line && in.Line(call.End()) >= in.Finding.Line {
+			calls = append(calls, call)
+		}
+		return true
+	})
+
+	// Innermost calls on the line come last from Inspect; prefer calls
+	// that start on the line, then the one covering the column
+	score := func(call *ast.CallExpr) int {
+		s := 0
+		if in.Line(call.Pos()) == in.Finding.Line {
+			s += 2
+		}
+		if col := in.Finding.Column; col > 0 {
+			start, end := in.Fset.Position(call.Pos()), in.Fset.Position(call.End())
+			if (start.Line < in.Finding.Line || start.Column <= col) && (end.Line > in.Finding.Line || end.Column >= col) {
+				s++
+			}
+		}
+		return s
+	}
+	sort.SliceStable(calls, func(i, j int) bool { return score(calls[i]) > score(calls[j]) })
+	return calls
+}
+
+// Imports reports whether the file imports path without renaming it
+func (in *Input) Imports(path string) bool {
+	for _, imp := range in.File.Imports {
+		if p, _ := strconv.Unquote(imp.Path.Value); p == path && imp.Name == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// AddImport returns the edits that import path, kept in sorted order
+// within the first import group. It returns nil when already imported.
+func (in *Input) AddImport(path string) []diff.Edit {
+	if in.Imports(path) {
+		return nil
+	}
+	quoted := strconv.Quote(path)
+
+	var decl *ast.GenDecl
+	for _, d := range in.File.Decls {
+		if g, ok := d.(*ast.GenDecl); ok && g.Tok == token.IMPORT {
+			decl = g
+			break
+		}
+	}
+	if decl == nil {
+		end := in.Offset(in.File.Name.End())
+		return []diff.Edit{{Start: end, End: end, New: "\n\nimport " + quoted}}
+	}
+
+	if !decl.Lparen.IsValid() {
+		spec := decl.Specs[0].(*ast.ImportSpec)
+		specs := []string{in.Text(spec), quoted}
+		if spec.Path.Value > quoted {
+			specs[0], specs[1] = specs[1], specs[0]
+		}
+		return []diff.Edit{in.Replace(decl, "import (\n\t"+specs[0]+"\n\t"+specs[1]+"\n)")}
+	}
+
+	// Insert before the first spec of the first group that sorts after
+	// path, or after the group's last spec
+	var last *ast.ImportSpec
+	for _, s := range decl.Specs {
+		spec := s.(*ast.ImportSpec)
+		if last != nil && in.Line(spec.Pos()) > in.Line(last.End())+1 {
+			break
+		}
+		if spec.Path.Value > quoted {
+			start := in.lineStart(spec.Pos())
+			return []diff.Edit{{Start: start, End: start, New: in.indent(spec.Pos()) + quoted + "\n"}}
+		}
+		last = spec
+	}
+	if last == nil {
+		start := in.Offset(decl.Lparen) + 1
+		return []diff.Edit{{Start: start, End: start, New: "\n\t" + quoted}}
+	}
+	end := in.Offset(last.End())
+	return []diff.Edit{{Start: end, End: end, New: "\n" + in.indent(last.Pos()) + quoted}}
+}
+
+// lineStart returns the offset of the start of pos's line
+func (in *Input) lineStart(pos token.Pos) int {
+	off := in.Offset(pos)
+	for off > 0 && in.Src[off-1] != '\n' {
+		off--
+	}
+	return off
+}
+
+// indent returns the whitespace before pos on its line
+func (in *Input) indent(pos token.Pos) string {
+	start := in.lineStart(pos)
+	end := start
+	for end < len(in.Src) && (in.Src[end] == ' ' || in.Src[end] == '\t') {
+		end++
+	}
+	return string(in.Src[start:end])
+}
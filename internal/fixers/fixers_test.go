@@ -0,0 +1,192 @@
+package fixers
+
+import (
+	"errors"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/Siddhant-K-code/sentinel-ai/internal/diff"
+	"github.com/Siddhant-K-code/sentinel-ai/internal/security"
+	"github.com/Siddhant-K-code/sentinel-ai/internal/testutil"
+)
+
+const sqlSrc = `package app
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+)
+
+func lookup(db *sql.DB, r *http.Request) {
+	db.Query("SELECT * FROM users WHERE name = '" + r.FormValue("user") + "'")
+	db.Exec(fmt.Sprintf("DELETE FROM users WHERE id = %d AND org = '%s'", id(r), r.FormValue("org")))
+	db.Query(fmt.Sprintf("SELECT * FROM users WHERE name LIKE '%%%s%%'", r.FormValue("q")))
+	db.Query(fmt.Sprintf("SELECT * FROM %s ORDER BY %s", r.FormValue("table"), r.FormValue("sort")))
+	db.Query("SELECT * FROM users WHERE id IN (" + r.FormValue("id") + ") LIMIT 10")
+}
+
+func list(db *sql.DB) error {
+	rows, err := db.Query("SELECT name FROM users")
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+	}
+	return nil
+}
+`
+
+const xssSrc = `package app
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+)
+
+func greet(w http.ResponseWriter, r *http.Request) {
+	name := r.FormValue("name")
+	t := template.Must(template.New("x").Parse("{{.}}"))
+	t.Execute(w, template.HTML("<h1>"+name+"</h1>"))
+	fmt.Fprintf(w, "<p>%s</p>", name)
+	w.Write([]byte(name))
+	fmt.Fprintf(w, "<p>%s (%d)</p>", name, len(name))
+	fmt.Fprintf(w, "<p>%d</p>", len(name))
+	fmt.Fprintf(w, "<p>%v</p>", r.Header)
+	fmt.Fprintln(w, *r.URL)
+}
+`
+
+func TestFixers(t *testing.T) {
+	root := testutil.WriteFiles(t, map[string]string{"sql.go": sqlSrc, "xss.go": xssSrc})
+
+	tests := []struct {
+		name    string
+		finding security.Finding
+		fixer   string
+		want    []string
+	}{
+		{
+			name:    "concatenation",
+			finding: security.Finding{RuleID: "go/sql-injection", File: "sql.go", Line: 10},
+			fixer:   "sql-parameterize",
+			want: []string{
+				`-	db.Query("SELECT * FROM users WHERE name = '" + r.FormValue("user") + "'")`,
+				`+	db.Query("SELECT * FROM users WHERE name = ?", r.FormValue("user"))`,
+			},
+		},
+		{
+			name:    "sprintf",
+			finding: security.Finding{RuleID: "go/sql-injection", File: "sql.go", Line: 11},
+			fixer:   "sql-parameterize",
+			want:    []string{`+	db.Exec("DELETE FROM users WHERE id = ? AND org = ?", id(r), r.FormValue("org"))`},
+		},
+		{
+			name:    "rows close",
+			finding: security.Finding{RuleID: "go/unclosed-rows", File: "sql.go", Line: 18},
+			fixer:   "sql-rows-close",
+			want:    []string{"@@ -19,6 +19,7 @@", "+\tdefer rows.Close()\n \tfor rows.Next() {"},
+		},
+		{
+			name:    "template.HTML",
+			finding: security.Finding{RuleID: "go/xss", File: "xss.go", Line: 12, Column: 28},
+			fixer:   "html-escape",
+			want: []string{
+				"+\t\"html\"\n \t\"html/template\"",
+				`+	t.Execute(w, template.HTML("<h1>"+html.EscapeString(name)+"</h1>"))`,
+			},
+		},
+		{
+			name:    "fprintf",
+			finding: security.Finding{RuleID: "go/xss", File: "xss.go", Line: 13},
+			fixer:   "html-escape",
+			want:    []string{`+	fmt.Fprintf(w, "<p>%s</p>", html.EscapeString(name))`},
+		},
+		{
+			name:    "write",
+			finding: security.Finding{RuleID: "go/xss", File: "xss.go", Line: 14},
+			fixer:   "html-escape",
+			want:    []string{`+	w.Write([]byte(html.EscapeString(name)))`},
+		},
+		{
+			name:    "fprintf with a number",
+			finding: security.Finding{RuleID: "go/xss", File: "xss.go", Line: 15},
+			fixer:   "html-escape",
+			want:    []string{`+	fmt.Fprintf(w, "<p>%s (%d)</p>", html.EscapeString(name), len(name))`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, fix, err := Fix(root, tt.finding)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if name != tt.fixer {
+				t.Errorf("Expected fixer %s, got %s", tt.fixer, name)
+			}
+			if !strings.HasPrefix(fix.Diff, "--- a/"+tt.finding.File+"\n") {
+				t.Errorf("Unexpected diff header:\n%s", fix.Diff)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(fix.Diff, want) {
+					t.Errorf("Expected diff to contain %q:\n%s", want, fix.Diff)
+				}
+			}
+		})
+	}
+}
+
+func TestFixersNotApplicable(t *testing.T) {
+	root := testutil.WriteFiles(t, map[string]string{"sql.go": sqlSrc, "xss.go": xssSrc})
+	for _, f := range []security.Finding{
+		// Only strings can be escaped: an int, a map and a struct
+		{RuleID: "go/xss", File: "xss.go", Line: 16},
+		{RuleID: "go/xss", File: "xss.go", Line: 17},
+		{RuleID: "go/xss", File: "xss.go", Line: 18},
+		// LIKE '%...%' cannot become a placeholder without changing the query
+		{RuleID: "go/sql-injection", File: "sql.go", Line: 12},
+		// Table and column names are identifiers, not parameters
+		{RuleID: "go/sql-injection", File: "sql.go", Line: 13},
+		{RuleID: "go/sql-injection", File: "sql.go", Line: 3},
+		// IN (...) may hold a comma-joined list, not a single value
+		{RuleID: "go/sql-injection", File: "sql.go", Line: 14},
+		{RuleID: "go/no-fixer", File: "sql.go", Line: 10},
+		{RuleID: "go/sql-injection", File: "query.sql", Line: 1},
+	} {
+		if _, _, err := Fix(root, f); !errors.Is(err, ErrNotApplicable) {
+			t.Errorf("Expected ErrNotApplicable for %+v, got %v", f, err)
+		}
+	}
+
+	if _, _, err := Fix(root, security.Finding{RuleID: "go/xss", File: "../outside.go", Line: 1}); err == nil {
+		t.Error("Expected error for a file outside the workspace")
+	}
+}
+
+func TestAddImport(t *testing.T) {
+	tests := map[string]string{
+		"package a\n":                                          "package a\n\nimport \"html\"\n",
+		"package a\n\nimport \"fmt\"\n":                        "package a\n\nimport (\n\t\"fmt\"\n\t\"html\"\n)\n",
+		"package a\n\nimport (\n\t\"fmt\"\n)\n":                "package a\n\nimport (\n\t\"fmt\"\n\t\"html\"\n)\n",
+		"package a\n\nimport (\n\t\"os\"\n\n\t\"x.io/y\"\n)\n": "package a\n\nimport (\n\t\"html\"\n\t\"os\"\n\n\t\"x.io/y\"\n)\n",
+		"package a\n\nimport \"html\"\n":                       "package a\n\nimport \"html\"\n",
+	}
+	for src, want := range tests {
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, "a.go", src, parser.ParseComments)
+		if err != nil {
+			t.Fatal(err)
+		}
+		in := &Input{Fset: fset, File: file, Src: []byte(src)}
+		got, err := diff.Apply([]byte(src), in.AddImport("html"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != want {
+			t.Errorf("AddImport(%q) = %q, want %q", src, got, want)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package fixers
+
+import (
+	"go/ast"
+	"go/types"
+
+	"github.com/Siddhant-K-code/sentinel-ai/internal/diff"
+)
+
+func init() {
+	Register("go/xss", Fixer{
+		Name:        "html-escape",
+		Description: "Escape untrusted data with html.EscapeString",
+		Fix:         escapeHTML,
+	})
+}
+
+// htmlTypes are html/template types whose content is trusted as HTML
+var htmlTypes = map[string]bool{"HTML": true, "HTMLAttr": true}
+
+// escapeHTML wraps the untrusted values at the finding in
+// html.EscapeString: the operand of a template.HTML conversion, the
+// arguments of fmt.Fprint* and io.WriteString, and the bytes passed to
+// Write. In a concatenation only the non-literal operands are wrapped, so
+// markup written as literals still renders. Only operands of type string
+// are wrapped and numbers and booleans are left alone; any other operand
+// leaves the call to the LLM.
+func escapeHTML(in *Input) ([]diff.Edit, error) {
+	for _, call := range in.CallsAt() {
+		var targets []ast.Expr
+		switch fun := call.Fun.(type) {
+		case *ast.SelectorExpr:
+			switch {
+			case htmlTypes[fun.Sel.Name] && isIdent(fun.X, "template") && len(call.Args) == 1:
+				targets = call.Args
+			case isIdent(fun.X, "fmt") && (fun.Sel.Name == "Fprintf" || fun.Sel.Name == "Fprint" || fun.Sel.Name == "Fprintln"):
+				start := 1
+				if fun.Sel.Name == "Fprintf" {
+					start = 2
+				}
+				if len(call.Args) > start && !call.Ellipsis.IsValid() {
+					targets = call.Args[start:]
+				}
+			case isIdent(fun.X, "io") && fun.Sel.Name == "WriteString" && len(call.Args) == 2:
+				targets = call.Args[1:]
+			case fun.Sel.Name == "Write" && len(call.Args) == 1:
+				// w.Write([]byte(s))
+				if conv, ok := call.Args[0].(*ast.CallExpr); ok && len(conv.Args) == 1 {
+					if arr, ok := conv.Fun.(*ast.ArrayType); ok && arr.Len == nil && isIdent(arr.Elt, "byte") {
+						targets = conv.Args
+					}
+				}
+			}
+		}
+
+		var operands []ast.Expr
+		for _, target := range targets {
+			if parts, ok := concatOperands(target); ok {
+				operands = append(operands, parts...)
+				continue
+			}
+			operands = append(operands, target)
+		}
+
+		var edits []diff.Edit
+		applies := true
+		for _, target := range operands {
+			if _, _, isLit := stringLit(target); isLit || isEscaped(target) {
+				continue
+			}
+			basic, _ := types.Unalias(in.TypeOf(target)).(*types.Basic)
+			switch {
+			case basic == nil:
+				applies = false
+			case basic.Info()&types.IsString != 0:
+				edits = append(edits, in.Replace(target, "html.EscapeString("+in.Text(target)+")"))
+			case basic.Info()&(types.IsNumeric|types.IsBoolean) == 0:
+				applies = false
+			}
+		}
+		if !applies || len(edits) == 0 {
+			continue
+		}
+		return append(edits, in.AddImport("html")...), nil
+	}
+	return nil, ErrNotApplicable
+}
+
+// isEscaped reports whether expr already calls an escaping function
+func isEscaped(expr ast.Expr) bool {
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	return ok && (sel.Sel.Name == "EscapeString" || sel.Sel.Name == "HTMLEscapeString")
+}
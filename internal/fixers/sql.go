@@ -0,0 +1,343 @@
+package fixers
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strconv"
+	"strings"
+
+	"github.com/Siddhant-K-code/sentinel-ai/internal/diff"
+)
+
+func init() {
+	Register("go/sql-injection", Fixer{
+		Name:        "sql-parameterize",
+		Description: "Pass untrusted values as query parameters",
+		Fix:         parameterizeQuery,
+	})
+	Register("go/unclosed-rows", Fixer{
+		Name:        "sql-rows-close",
+		Description: "Close the result set when the function returns",
+		Fix:         closeRows,
+	})
+}
+
+// queryArg maps database/sql methods to the index of their query argument
+var queryArg = map[string]int{
+	"Query":           0,
+	"QueryRow":        0,
+	"Exec":            0,
+	"QueryContext":    1,
+	"QueryRowContext": 1,
+	"ExecContext":     1,
+}
+
+// dollarDrivers use $1, $2, ... instead of ? placeholders
+var dollarDrivers = []string{"github.com/lib/pq", "github.com/jackc/pgx"}
+
+// parameterizeQuery rewrites a query built with fmt.Sprintf or string
+// concatenation into a constant query with placeholders, passing the
+// interpolated values as arguments
+func parameterizeQuery(in *Input) ([]diff.Edit, error) {
+	for _, call := range in.CallsAt() {
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			continue
+		}
+		i, ok := queryArg[sel.Sel.Name]
+		if !ok || len(call.Args) != i+1 {
+			continue
+		}
+
+		query, args, raw, ok := in.splitQuery(call.Args[i])
+		if !ok {
+			continue
+		}
+		text, ok := placeholders(query, len(args), in.dollarPlaceholders())
+		if !ok {
+			continue
+		}
+
+		lit := strconv.Quote(text)
+		if raw && !strings.Contains(text, "`") {
+			lit = "`" + text + "`"
+		}
+		for _, arg := range args {
+			lit += ", " + in.Text(arg)
+		}
+		return []diff.Edit{in.Replace(call.Args[i], lit)}, nil
+	}
+	return nil, ErrNotApplicable
+}
+
+// paramMark stands for an interpolated value in a split query
+const paramMark = "\x00"
+
+// splitQuery separates a fmt.Sprintf call or a concatenation into the
+// query text, with paramMark for each value, and the values. raw reports
+// whether the query was written as a raw string.
+func (in *Input) splitQuery(expr ast.Expr) (query string, args []ast.Expr, raw, ok bool) {
+	expr = ast.Unparen(expr)
+
+	if call, isCall := expr.(*ast.CallExpr); isCall {
+		sel, isSel := call.Fun.(*ast.SelectorExpr)
+		if !isSel || sel.Sel.Name != "Sprintf" || !isIdent(sel.X, "fmt") || len(call.Args) < 2 || call.Ellipsis.IsValid() {
+			return "", nil, false, false
+		}
+		format, isRaw, isLit := stringLit(call.Args[0])
+		if !isLit {
+			return "", nil, false, false
+		}
+		var b strings.Builder
+		n := 0
+		for i := 0; i < len(format); i++ {
+			if format[i] != '%' {
+				b.WriteByte(format[i])
+				continue
+			}
+			if i+1 == len(format) {
+				return "", nil, false, false
+			}
+			i++
+			switch format[i] {
+			case '%':
+				b.WriteByte('%')
+			case 's', 'd', 'v', 'q':
+				b.WriteString(paramMark)
+				n++
+			default:
+				return "", nil, false, false
+			}
+		}
+		if n != len(call.Args)-1 {
+			return "", nil, false, false
+		}
+		return b.String(), call.Args[1:], isRaw, true
+	}
+
+	parts, isConcat := concatOperands(expr)
+	if !isConcat {
+		return "", nil, false, false
+	}
+
+	var b strings.Builder
+	for i, part := range parts {
+		if s, isRaw, isLit := stringLit(part); isLit {
+			b.WriteString(s)
+			raw = raw || (i == 0 && isRaw)
+			continue
+		}
+		b.WriteString(paramMark)
+		args = append(args, part)
+	}
+	if len(args) == 0 {
+		return "", nil, false, false
+	}
+	return b.String(), args, raw, true
+}
+
+// placeholders replaces each paramMark, and the quotes around it, with a
+// placeholder. Only values can be parameters: a mark must be quoted,
+// follow a comparison, or sit in a VALUES list. Identifiers such as table
+// or column names cannot, so the query is left alone.
+func placeholders(query string, n int, dollar bool) (string, bool) {
+	var b strings.Builder
+	k := 0
+	for i := 0; i < len(query); i++ {
+		if query[i] != paramMark[0] {
+			b.WriteByte(query[i])
+			continue
+		}
+		k++
+		// Drop quotes the value was interpolated between
+		if s := b.String(); strings.HasSuffix(s, "'") && i+1 < len(query) && query[i+1] == '\'' {
+			b.Reset()
+			b.WriteString(strings.TrimSuffix(s, "'"))
+			i++
+		} else if strings.Count(s, "'")%2 == 1 {
+			// Part of a larger quoted string, e.g. LIKE '%x%'
+			return "", false
+		} else if !valuePosition(s) {
+			return "", false
+		}
+		if dollar {
+			fmt.Fprintf(&b, "$%d", k)
+		} else {
+			b.WriteByte('?')
+		}
+	}
+	return b.String(), k == n
+}
+
+// comparisons are operators whose right operand is a value
+var comparisons = []string{"<=", ">=", "<>", "!=", "=", "<", ">"}
+
+// valuePosition reports whether an unquoted value following query would
+// be an SQL value: after a comparison or LIKE, or in a VALUES (...) list.
+// An unquoted value in IN (...) is often a joined list of IDs, which one
+// placeholder cannot hold.
+func valuePosition(query string) bool {
+	t := strings.TrimRight(query, " \t\r\n")
+	for _, op := range comparisons {
+		if strings.HasSuffix(t, op) {
+			return true
+		}
+	}
+	upper := strings.ToUpper(t)
+	if hasWord(upper, "LIKE") {
+		return true
+	}
+	if !strings.HasSuffix(t, "(") && !strings.HasSuffix(t, ",") {
+		return false
+	}
+	depth := 0
+	for i := len(t) - 1; i >= 0; i-- {
+		switch t[i] {
+		case ')':
+			depth++
+		case '(':
+			if depth == 0 {
+				head := strings.TrimRight(upper[:i], " \t\r\n")
+				return hasWord(head, "VALUES")
+			}
+			depth--
+		}
+	}
+	return false
+}
+
+// hasWord reports whether s ends with the keyword word
+func hasWord(s, word string) bool {
+	if !strings.HasSuffix(s, word) {
+		return false
+	}
+	if len(s) == len(word) {
+		return true
+	}
+	c := s[len(s)-len(word)-1]
+	return !(c == '_' || c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || c >= '0' && c <= '9')
+}
+
+// concatOperands flattens a string concatenation a + b + ... into its
+// operands, in order. ok is false when expr is not a concatenation.
+func concatOperands(expr ast.Expr) (parts []ast.Expr, ok bool) {
+	var flatten func(e ast.Expr) bool
+	flatten = func(e ast.Expr) bool {
+		e = ast.Unparen(e)
+		if bin, isBin := e.(*ast.BinaryExpr); isBin {
+			if bin.Op != token.ADD {
+				return false
+			}
+			return flatten(bin.X) && flatten(bin.Y)
+		}
+		parts = append(parts, e)
+		return true
+	}
+	if _, isBin := ast.Unparen(expr).(*ast.BinaryExpr); !isBin || !flatten(expr) {
+		return nil, false
+	}
+	return parts, true
+}
+
+// dollarPlaceholders reports whether the file imports a PostgreSQL driver
+func (in *Input) dollarPlaceholders() bool {
+	for _, imp := range in.File.Imports {
+		path, _ := strconv.Unquote(imp.Path.Value)
+		for _, driver := range dollarDrivers {
+			if path == driver || strings.HasPrefix(path, driver+"/") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// closeRows adds defer rows.Close() after the query that opens rows, or
+// after the error check that follows it
+func closeRows(in *Input) ([]diff.Edit, error) {
+	var (
+		found  *ast.AssignStmt
+		after  ast.Stmt
+		closed bool
+		name   string
+	)
+	ast.Inspect(in.File, func(n ast.Node) bool {
+		block, ok := n.(*ast.BlockStmt)
+		if !ok || found != nil {
+			return found == nil
+		}
+		for i, stmt := range block.List {
+			assign, ok := stmt.(*ast.AssignStmt)
+			if !ok || in.Line(assign.Pos()) != in.Finding.Line || len(assign.Lhs) != 2 || len(assign.Rhs) != 1 {
+				continue
+			}
+			call, ok := assign.Rhs[0].(*ast.CallExpr)
+			if !ok {
+				continue
+			}
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok || (sel.Sel.Name != "Query" && sel.Sel.Name != "QueryContext") {
+				continue
+			}
+			id, ok := assign.Lhs[0].(*ast.Ident)
+			if !ok || id.Name == "_" {
+				continue
+			}
+			found, after, name = assign, assign, id.Name
+			if i+1 < len(block.List) {
+				if check, ok := block.List[i+1].(*ast.IfStmt); ok && mentionsErr(check.Cond, assign.Lhs[1]) {
+					after = check
+				}
+			}
+			for _, rest := range block.List[i+1:] {
+				ast.Inspect(rest, func(n ast.Node) bool {
+					if call, ok := n.(*ast.CallExpr); ok {
+						if sel, ok := call.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "Close" && isIdent(sel.X, name) {
+							closed = true
+						}
+					}
+					return !closed
+				})
+			}
+			return false
+		}
+		return true
+	})
+	if found == nil || closed {
+		return nil, ErrNotApplicable
+	}
+
+	end := in.Offset(after.End())
+	return []diff.Edit{{Start: end, End: end, New: "\n" + in.indent(found.Pos()) + "defer " + name + ".Close()"}}, nil
+}
+
+// mentionsErr reports whether cond refers to the error variable
+func mentionsErr(cond ast.Expr, errVar ast.Expr) bool {
+	id, ok := errVar.(*ast.Ident)
+	if !ok {
+		return false
+	}
+	found := false
+	ast.Inspect(cond, func(n ast.Node) bool {
+		found = found || isIdent(n, id.Name)
+		return !found
+	})
+	return found
+}
+
+// isIdent reports whether node is the identifier name
+func isIdent(node ast.Node, name string) bool {
+	id, ok := node.(*ast.Ident)
+	return ok && id.Name == name
+}
+
+// stringLit returns the value of a string literal and whether it is raw
+func stringLit(expr ast.Expr) (value string, raw, ok bool) {
+	lit, isLit := ast.Unparen(expr).(*ast.BasicLit)
+	if !isLit || lit.Kind != token.STRING {
+		return "", false, false
+	}
+	value, err := strconv.Unquote(lit.Value)
+	return value, strings.HasPrefix(lit.Value, "`"), err == nil
+}
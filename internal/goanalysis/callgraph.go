@@ -0,0 +1,197 @@
+package goanalysis
+
+import (
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/callgraph"
+	"golang.org/x/tools/go/callgraph/cha"
+	"golang.org/x/tools/go/callgraph/vta"
+	"golang.org/x/tools/go/ssa"
+	"golang.org/x/tools/go/ssa/ssautil"
+)
+
+// WorkspaceFuncs returns every function declared in the workspace,
+// including closures and methods, ordered by position
+func (p *Program) WorkspaceFuncs() []*ssa.Function {
+	var funcs []*ssa.Function
+	for fn := range ssautil.AllFunctions(p.SSA()) {
+		if fn.Blocks != nil && p.InWorkspace(fn) {
+			funcs = append(funcs, fn)
+		}
+	}
+	sortFuncs(funcs)
+	return funcs
+}
+
+// EntryPoints returns the functions execution can start from: main and
+// init of commands, Test, Benchmark, Fuzz and Example functions, and the
+// exported functions and methods of library packages
+func (p *Program) EntryPoints() []*ssa.Function {
+	var entries []*ssa.Function
+	for _, fn := range p.WorkspaceFuncs() {
+		if fn.Parent() != nil || fn.Pkg == nil {
+			continue
+		}
+		name := fn.Name()
+		isMain := fn.Pkg.Pkg.Name() == "main"
+		switch {
+		case fn.Signature.Recv() == nil && (name == "init" || strings.HasPrefix(name, "init#")):
+			entries = append(entries, fn)
+		case isMain && name == "main" && fn.Signature.Recv() == nil:
+			entries = append(entries, fn)
+		case isTestFunc(fn):
+			entries = append(entries, fn)
+		case !isMain && token.IsExported(name) && exportedRecv(fn):
+			entries = append(entries, fn)
+		}
+	}
+	return entries
+}
+
+// isTestFunc reports whether fn is run by go test
+func isTestFunc(fn *ssa.Function) bool {
+	pos := fn.Prog.Fset.Position(fn.Pos())
+	if !strings.HasSuffix(pos.Filename, "_test.go") || fn.Signature.Recv() != nil {
+		return false
+	}
+	for _, prefix := range []string{"Test", "Benchmark", "Fuzz", "Example"} {
+		if strings.HasPrefix(fn.Name(), prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// exportedRecv reports whether fn is a function or a method of an
+// exported type
+func exportedRecv(fn *ssa.Function) bool {
+	recv := fn.Signature.Recv()
+	if recv == nil {
+		return true
+	}
+	t := recv.Type()
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, ok := types.Unalias(t).(*types.Named)
+	return ok && named.Obj().Exported()
+}
+
+// CallGraph builds the call graph of everything the workspace can call:
+// class hierarchy analysis resolves dynamic calls soundly, then variable
+// type analysis narrows them to the types that actually flow there
+func (p *Program) CallGraph() *callgraph.Graph {
+	initial := cha.CallGraph(p.SSA())
+	initial.DeleteSyntheticNodes()
+
+	funcs := make(map[*ssa.Function]bool)
+	queue := p.WorkspaceFuncs()
+	for _, fn := range queue {
+		funcs[fn] = true
+	}
+	for len(queue) > 0 {
+		fn := queue[0]
+		queue = queue[1:]
+		node := initial.Nodes[fn]
+		if node == nil {
+			continue
+		}
+		for _, edge := range node.Out {
+			if callee := edge.Callee.Func; !funcs[callee] {
+				funcs[callee] = true
+				queue = append(queue, callee)
+			}
+		}
+	}
+
+	cg := vta.CallGraph(funcs, initial)
+	cg.DeleteSyntheticNodes()
+	return cg
+}
+
+// Reachability holds the shortest known call path to every function
+// reached from a set of entry points
+type Reachability struct {
+	parent map[*ssa.Function]*callgraph.Edge
+}
+
+// Reach walks the call graph breadth first from entries
+func Reach(cg *callgraph.Graph, entries []*ssa.Function) *Reachability {
+	r := &Reachability{parent: make(map[*ssa.Function]*callgraph.Edge)}
+
+	var queue []*ssa.Function
+	for _, fn := range entries {
+		if _, seen := r.parent[fn]; !seen {
+			r.parent[fn] = nil
+			queue = append(queue, fn)
+		}
+	}
+
+	for len(queue) > 0 {
+		fn := queue[0]
+		queue = queue[1:]
+		node := cg.Nodes[fn]
+		if node == nil {
+			continue
+		}
+
+		// Visit call sites in source order so paths are deterministic
+		out := append([]*callgraph.Edge{}, node.Out...)
+		sort.SliceStable(out, func(i, j int) bool {
+			if pi, pj := out[i].Pos(), out[j].Pos(); pi != pj {
+				return pi < pj
+			}
+			return out[i].Callee.Func.String() < out[j].Callee.Func.String()
+		})
+		for _, edge := range out {
+			callee := edge.Callee.Func
+			if _, seen := r.parent[callee]; !seen {
+				r.parent[callee] = edge
+				queue = append(queue, callee)
+			}
+		}
+	}
+	return r
+}
+
+// Reached reports whether fn is an entry point or called from one
+func (r *Reachability) Reached(fn *ssa.Function) bool {
+	_, ok := r.parent[fn]
+	return ok
+}
+
+// Path returns the calls leading from an entry point to fn, outermost
+// first. It is empty for entry points and unreached functions.
+func (r *Reachability) Path(fn *ssa.Function) []*callgraph.Edge {
+	var path []*callgraph.Edge
+	for edge := r.parent[fn]; edge != nil; edge = r.parent[edge.Caller.Func] {
+		path = append(path, edge)
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// Functions returns the reached functions ordered by position
+func (r *Reachability) Functions() []*ssa.Function {
+	funcs := make([]*ssa.Function, 0, len(r.parent))
+	for fn := range r.parent {
+		funcs = append(funcs, fn)
+	}
+	sortFuncs(funcs)
+	return funcs
+}
+
+// sortFuncs orders functions by position, then name for synthetic ones
+func sortFuncs(funcs []*ssa.Function) {
+	sort.Slice(funcs, func(i, j int) bool {
+		if pi, pj := funcs[i].Pos(), funcs[j].Pos(); pi != pj {
+			return pi < pj
+		}
+		return funcs[i].String() < funcs[j].String()
+	})
+}
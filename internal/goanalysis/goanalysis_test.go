@@ -0,0 +1,102 @@
+package goanalysis
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/tools/go/ssa"
+
+	"github.com/Siddhant-K-code/sentinel-ai/internal/testutil"
+	"github.com/Siddhant-K-code/sentinel-ai/internal/tools"
+)
+
+func TestReach(t *testing.T) {
+	root := testutil.WriteFiles(t, map[string]string{
+		"go.mod": "module example.com/app\n\ngo 1.22\n",
+		"main.go": `package main
+
+type greeter interface{ greet() string }
+
+type english struct{}
+
+func (english) greet() string { return "hello" }
+
+type french struct{}
+
+func (french) greet() string { return "bonjour" }
+
+func run(g greeter) string { return g.greet() }
+
+func main() { println(run(english{})) }
+`,
+	})
+
+	runner := tools.NewRunner(root, [][]string{ListCommand}, time.Minute)
+	prog, err := Load(context.Background(), runner, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if errs := prog.Errors(); len(errs) > 0 {
+		t.Fatal(errs)
+	}
+
+	main := prog.SSA().Package(prog.Packages[0].Types).Func("main")
+	reach := Reach(prog.CallGraph(), []*ssa.Function{main})
+
+	funcs := make(map[string]*ssa.Function)
+	for _, fn := range reach.Functions() {
+		_, name := SymbolName(fn)
+		funcs[name] = fn
+	}
+	if funcs["english.greet"] == nil {
+		t.Fatal("english.greet is not reached")
+	}
+	// Variable type analysis sees that only english flows into run
+	if funcs["french.greet"] != nil {
+		t.Error("french.greet is reached")
+	}
+
+	var path []string
+	for _, edge := range reach.Path(funcs["english.greet"]) {
+		path = append(path, edge.Caller.Func.Name()+"->"+edge.Callee.Func.Name())
+	}
+	if got := strings.Join(path, " "); got != "main->run run->greet" {
+		t.Errorf("path = %q", got)
+	}
+}
+
+func TestLoadRequiresAllowlist(t *testing.T) {
+	root := testutil.WriteFiles(t, map[string]string{"go.mod": "module example.com/app\n"})
+	runner := tools.NewRunner(root, nil, time.Minute)
+	if _, err := Load(context.Background(), runner, Options{}); err == nil || !strings.Contains(err.Error(), "go list") {
+		t.Errorf("Load() = %v, want an allowlist error", err)
+	}
+}
+
+func TestLoaderSharesPrograms(t *testing.T) {
+	root := testutil.WriteFiles(t, map[string]string{
+		"go.mod":  "module example.com/app\n\ngo 1.22\n",
+		"main.go": "package main\n\nfunc main() {}\n",
+	})
+	runner := tools.NewRunner(root, [][]string{ListCommand}, time.Minute)
+	loader := NewLoader(runner)
+	ctx := context.Background()
+
+	first, err := loader.Load(ctx, Options{Tags: []string{"b", "a"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := loader.Load(ctx, Options{Tags: []string{"a", "b"}}); again != first {
+		t.Error("Expected the same options to share one program")
+	}
+	if tests, _ := loader.Load(ctx, Options{Tests: true}); tests == first {
+		t.Error("Expected other options to load another program")
+	}
+
+	loader.Reset()
+	if fresh, _ := loader.Load(ctx, Options{Tags: []string{"a", "b"}}); fresh == first {
+		t.Error("Expected Reset to drop loaded programs")
+	}
+}
@@ -0,0 +1,198 @@
+// Package goanalysis loads the Go packages of a workspace with full type
+// information and builds SSA form and call graphs for in-process analyzers
+package goanalysis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go/build"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/ssa"
+	"golang.org/x/tools/go/ssa/ssautil"
+
+	"github.com/Siddhant-K-code/sentinel-ai/internal/tools"
+)
+
+// ListCommand is the allowlist entry that permits package loading, which
+// runs go list outside the tool runner
+var ListCommand = []string{"go", "list", "./..."}
+
+// Options controls package loading
+type Options struct {
+	// Tests also loads _test.go files and test packages
+	Tests bool
+	// Tags are extra build tags
+	Tags []string
+	// Network lets go list download missing modules; otherwise
+	// GOPROXY=off makes loading use the module cache only
+	Network bool
+}
+
+// Program is the type-checked code of a workspace and its dependencies
+type Program struct {
+	// Root is the absolute workspace directory
+	Root string
+	Fset *token.FileSet
+	// Packages are the workspace packages; Deps maps every loaded package,
+	// including dependencies, by import path
+	Packages []*packages.Package
+	Deps     map[string]*packages.Package
+
+	workspace map[string]bool
+	ssaOnce   sync.Once
+	ssa       *ssa.Program
+}
+
+// Load loads ./... in the runner's workspace from source, dependencies
+// included. Packages with errors are kept; Errors lists their problems.
+func Load(ctx context.Context, runner *tools.Runner, opts Options) (*Program, error) {
+	if !runner.Allowed(ListCommand[0], ListCommand[1:]...) {
+		return nil, fmt.Errorf("loading Go packages requires %q in the allowlist", strings.Join(ListCommand, " "))
+	}
+	root, err := runner.WorkDir("")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(filepath.Join(root, "go.mod")); err != nil {
+		return nil, errors.New("no go.mod in workspace")
+	}
+
+	if runner.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, runner.Timeout)
+		defer cancel()
+	}
+
+	env := append(os.Environ(), "GOWORK=off")
+	if !opts.Network {
+		env = append(env, "GOPROXY=off")
+	}
+	cfg := &packages.Config{
+		Mode:    packages.LoadAllSyntax | packages.NeedModule,
+		Context: ctx,
+		Dir:     root,
+		Env:     env,
+		Tests:   opts.Tests,
+	}
+	if len(opts.Tags) > 0 {
+		cfg.BuildFlags = []string{"-tags=" + strings.Join(opts.Tags, ",")}
+	}
+
+	pkgs, err := packages.Load(cfg, "./...")
+	if err != nil {
+		return nil, fmt.Errorf("load packages: %w", err)
+	}
+	if len(pkgs) == 0 {
+		return nil, errors.New("no Go packages in workspace")
+	}
+
+	prog := &Program{
+		Root:      root,
+		Fset:      pkgs[0].Fset,
+		Packages:  pkgs,
+		Deps:      make(map[string]*packages.Package),
+		workspace: make(map[string]bool),
+	}
+	for _, pkg := range pkgs {
+		prog.workspace[pkg.PkgPath] = true
+	}
+	packages.Visit(pkgs, nil, func(p *packages.Package) {
+		prog.Deps[p.PkgPath] = p
+	})
+	return prog, nil
+}
+
+// SSA returns the SSA form of every well-typed package with function
+// bodies, building it on first use. Packages with errors have no SSA
+// package.
+func (p *Program) SSA() *ssa.Program {
+	p.ssaOnce.Do(func() {
+		p.ssa, _ = ssautil.AllPackages(p.Packages, ssa.InstantiateGenerics)
+		p.ssa.Build()
+	})
+	return p.ssa
+}
+
+// Errors returns the load and type errors of the workspace packages
+func (p *Program) Errors() []packages.Error {
+	var errs []packages.Error
+	for _, pkg := range p.Packages {
+		errs = append(errs, pkg.Errors...)
+	}
+	return errs
+}
+
+// InWorkspace reports whether a function is declared in a workspace
+// package, as opposed to a dependency (vendored or not) or the standard
+// library
+func (p *Program) InWorkspace(fn *ssa.Function) bool {
+	for fn.Parent() != nil {
+		fn = fn.Parent()
+	}
+	if origin := fn.Origin(); origin != nil {
+		fn = origin
+	}
+	if fn.Pkg == nil {
+		return false
+	}
+	return p.workspace[fn.Pkg.Pkg.Path()]
+}
+
+// RelPath returns a file path relative to the workspace. Files in
+// dependencies are named module@version/path instead; standard library
+// files keep their GOROOT-relative path.
+func (p *Program) RelPath(file string) string {
+	if rel, err := filepath.Rel(p.Root, file); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	for _, pkg := range p.Deps {
+		mod := pkg.Module
+		if mod == nil || mod.Dir == "" {
+			continue
+		}
+		if rel, err := filepath.Rel(mod.Dir, file); err == nil && !strings.HasPrefix(rel, "..") {
+			return filepath.Join(mod.Path+"@"+mod.Version, rel)
+		}
+	}
+	if rel, err := filepath.Rel(filepath.Join(build.Default.GOROOT, "src"), file); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return file
+}
+
+// SymbolName returns the package path and the name of a function as
+// vulnerability databases list it: "Func" or "Type.Method". Closures
+// are named after the function declaring them.
+func SymbolName(fn *ssa.Function) (pkgPath, name string) {
+	for fn.Parent() != nil {
+		fn = fn.Parent()
+	}
+	if origin := fn.Origin(); origin != nil {
+		fn = origin
+	}
+	if fn.Pkg != nil {
+		pkgPath = fn.Pkg.Pkg.Path()
+	} else if obj := fn.Object(); obj != nil && obj.Pkg() != nil {
+		pkgPath = obj.Pkg().Path()
+	}
+
+	name = fn.Name()
+	if recv := fn.Signature.Recv(); recv != nil {
+		t := recv.Type()
+		if ptr, ok := t.(*types.Pointer); ok {
+			t = ptr.Elem()
+		}
+		if named, ok := types.Unalias(t).(*types.Named); ok {
+			name = named.Obj().Name() + "." + name
+		}
+	}
+	return pkgPath, name
+}
@@ -0,0 +1,90 @@
+package goanalysis
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Siddhant-K-code/sentinel-ai/internal/tools"
+)
+
+// Loader shares programs between the analyzers of a scan, so the
+// workspace is loaded once per set of options. Shared programs must not
+// be modified.
+type Loader struct {
+	runner *tools.Runner
+
+	mu    sync.Mutex
+	loads map[string]*sharedLoad
+}
+
+// sharedLoad is a load in progress or done; done is closed when prog and
+// err are set
+type sharedLoad struct {
+	done chan struct{}
+	prog *Program
+	err  error
+}
+
+// NewLoader creates a loader for the runner's workspace
+func NewLoader(runner *tools.Runner) *Loader {
+	return &Loader{runner: runner, loads: make(map[string]*sharedLoad)}
+}
+
+// Load returns the program for opts, loading it on the first call and
+// waiting for that load on later ones. A load cut short by its caller's
+// context is retried by the next caller.
+func (l *Loader) Load(ctx context.Context, opts Options) (*Program, error) {
+	key := opts.key()
+
+	l.mu.Lock()
+	load, ok := l.loads[key]
+	if !ok {
+		load = &sharedLoad{done: make(chan struct{})}
+		l.loads[key] = load
+	}
+	l.mu.Unlock()
+
+	if !ok {
+		load.prog, load.err = Load(ctx, l.runner, opts)
+		if load.err != nil && ctx.Err() != nil {
+			l.mu.Lock()
+			delete(l.loads, key)
+			l.mu.Unlock()
+		}
+		close(load.done)
+		return load.prog, load.err
+	}
+
+	select {
+	case <-load.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	// The load was dropped because its caller gave up
+	l.mu.Lock()
+	dropped := load.err != nil && l.loads[key] != load
+	l.mu.Unlock()
+	if dropped {
+		return l.Load(ctx, opts)
+	}
+	return load.prog, load.err
+}
+
+// Reset drops the loaded programs, so the next load sees the current
+// workspace
+func (l *Loader) Reset() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.loads = make(map[string]*sharedLoad)
+}
+
+// key identifies the options; tag order does not matter
+func (o Options) key() string {
+	tags := append([]string(nil), o.Tags...)
+	sort.Strings(tags)
+	return fmt.Sprintf("%t\x00%t\x00%s", o.Tests, o.Network, strings.Join(tags, ","))
+}
@@ -0,0 +1,505 @@
+package goanalysis
+
+import (
+	"fmt"
+	"go/token"
+	"go/types"
+	"sort"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// maxFlowSteps bounds the length of a reported taint path
+const maxFlowSteps = 25
+
+// TaintSpec declares where untrusted data enters, where it must not
+// arrive and what makes it safe. Functions are named as go/ssa prints
+// them: "os.Getenv", "(*net/http.Request).FormValue".
+type TaintSpec struct {
+	Sources    []TaintSource `yaml:"sources"`
+	Sinks      []TaintSink   `yaml:"sinks"`
+	Sanitizers []string      `yaml:"sanitizers"`
+}
+
+// TaintSource is one way untrusted data enters the program; exactly one
+// field is set
+type TaintSource struct {
+	// Function's results are tainted
+	Function string `yaml:"function"`
+	// Type's fields are tainted when read, e.g. "net/http.Request"
+	Type string `yaml:"type"`
+	// Global is a tainted package variable, e.g. "os.Args"
+	Global string `yaml:"global"`
+}
+
+// TaintSink is a rule reporting tainted data reaching calls or
+// conversions
+type TaintSink struct {
+	RuleID   string `yaml:"rule_id"`
+	Message  string `yaml:"message"`
+	CWE      string `yaml:"cwe"`
+	Severity string `yaml:"severity"`
+	// Calls are sink functions and the arguments that must not be tainted
+	Calls []TaintCall `yaml:"calls"`
+	// Types are named types that tainted data must not be converted to,
+	// e.g. "html/template.HTML"
+	Types []string `yaml:"types"`
+}
+
+// TaintCall is a sink function. Args are parameter indexes, not counting
+// the receiver; empty means every argument.
+type TaintCall struct {
+	Function string `yaml:"function"`
+	Args     []int  `yaml:"args"`
+}
+
+// TaintResult is tainted data reaching a sink
+type TaintResult struct {
+	Sink *TaintSink
+	// Callee is the sink function or conversion type
+	Callee string
+	Pos    token.Position
+	// Source describes where the data came from
+	Source string
+	// Steps lead from the source to the sink
+	Steps []TaintStep
+}
+
+// TaintStep is one location along a taint path
+type TaintStep struct {
+	Pos     token.Position
+	Message string
+}
+
+// fact records why a value is tainted
+type fact struct {
+	prev ssa.Value
+	pos  token.Pos
+	note string
+}
+
+// taint is the state of one analysis
+type taint struct {
+	prog    *Program
+	spec    *TaintSpec
+	sources map[string]string // function or global -> description
+	types   map[string]bool
+	clean   map[string]bool
+	facts   map[ssa.Value]*fact
+	changed bool
+}
+
+// Taint tracks untrusted data through the workspace functions. The
+// analysis is interprocedural but context insensitive: a parameter that
+// receives tainted data at one call site is tainted for all callers.
+// Calls into dependencies and the standard library taint their results
+// and pointer arguments when any argument or receiver is tainted.
+func (p *Program) Taint(spec *TaintSpec) []TaintResult {
+	t := &taint{
+		prog:    p,
+		spec:    spec,
+		sources: make(map[string]string),
+		types:   make(map[string]bool),
+		clean:   make(map[string]bool),
+		facts:   make(map[ssa.Value]*fact),
+	}
+	for _, src := range spec.Sources {
+		switch {
+		case src.Function != "":
+			t.sources[src.Function] = src.Function
+		case src.Global != "":
+			t.sources[src.Global] = src.Global
+		case src.Type != "":
+			t.types[src.Type] = true
+		}
+	}
+	for _, fn := range spec.Sanitizers {
+		t.clean[fn] = true
+	}
+
+	funcs := p.WorkspaceFuncs()
+	for t.changed = true; t.changed; {
+		t.changed = false
+		for _, fn := range funcs {
+			for _, b := range fn.Blocks {
+				for _, instr := range b.Instrs {
+					t.transfer(instr)
+				}
+			}
+		}
+	}
+
+	var results []TaintResult
+	seen := make(map[string]bool)
+	for _, fn := range funcs {
+		for _, b := range fn.Blocks {
+			for _, instr := range b.Instrs {
+				for _, r := range t.sinks(instr) {
+					key := fmt.Sprintf("%s:%s:%d:%d", r.Sink.RuleID, r.Pos.Filename, r.Pos.Line, r.Pos.Column)
+					if !seen[key] {
+						seen[key] = true
+						results = append(results, r)
+					}
+				}
+			}
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i].Pos, results[j].Pos
+		if a.Filename != b.Filename {
+			return a.Filename < b.Filename
+		}
+		return a.Line < b.Line
+	})
+	return results
+}
+
+// mark taints v, keeping the first reason found
+func (t *taint) mark(v ssa.Value, prev ssa.Value, pos token.Pos, note string) {
+	if v == nil {
+		return
+	}
+	if _, ok := t.facts[v]; ok {
+		return
+	}
+	t.facts[v] = &fact{prev: prev, pos: pos, note: note}
+	t.changed = true
+}
+
+// tainted reports whether v carries untrusted data
+func (t *taint) tainted(v ssa.Value) bool {
+	if _, ok := t.facts[v]; ok {
+		return true
+	}
+	if g, ok := v.(*ssa.Global); ok {
+		if desc, ok := t.sources[g.String()]; ok {
+			t.facts[g] = &fact{pos: g.Pos(), note: "source: " + desc}
+			return true
+		}
+	}
+	return false
+}
+
+// firstTainted returns the first tainted value of vs
+func (t *taint) firstTainted(vs []ssa.Value) ssa.Value {
+	for _, v := range vs {
+		if v != nil && t.tainted(v) {
+			return v
+		}
+	}
+	return nil
+}
+
+// transfer propagates taint across one instruction
+func (t *taint) transfer(instr ssa.Instruction) {
+	switch in := instr.(type) {
+	case ssa.CallInstruction:
+		t.call(in)
+
+	case *ssa.Store:
+		if t.tainted(in.Val) {
+			t.mark(in.Addr, in.Val, in.Pos(), "")
+			t.mark(root(in.Addr), in.Val, in.Pos(), "")
+		}
+
+	case *ssa.MapUpdate:
+		if v := t.firstTainted([]ssa.Value{in.Key, in.Value}); v != nil {
+			t.mark(root(in.Map), v, in.Pos(), "")
+		}
+
+	case *ssa.Send:
+		if t.tainted(in.X) {
+			t.mark(root(in.Chan), in.X, in.Pos(), "")
+		}
+
+	case *ssa.MakeClosure:
+		fn := in.Fn.(*ssa.Function)
+		for i, b := range in.Bindings {
+			if t.tainted(b) && i < len(fn.FreeVars) {
+				t.mark(fn.FreeVars[i], b, b.Pos(), "")
+			}
+		}
+		t.propagate(in)
+
+	case *ssa.FieldAddr:
+		if t.sourceType(in.X.Type()) {
+			t.mark(in, nil, in.Pos(), "source: "+fieldName(in.X.Type(), in.Field))
+		}
+		t.propagate(in)
+
+	case *ssa.Field:
+		if t.sourceType(in.X.Type()) {
+			t.mark(in, nil, in.Pos(), "source: "+fieldName(in.X.Type(), in.Field))
+		}
+		t.propagate(in)
+
+	case ssa.Value:
+		t.propagate(in)
+	}
+}
+
+// propagate taints a value computed from a tainted operand
+func (t *taint) propagate(v ssa.Value) {
+	if _, ok := t.facts[v]; ok {
+		return
+	}
+	instr, ok := v.(ssa.Instruction)
+	if !ok {
+		return
+	}
+	for _, op := range instr.Operands(nil) {
+		if op != nil && *op != nil && t.tainted(*op) {
+			t.mark(v, *op, instr.Pos(), "")
+			return
+		}
+	}
+}
+
+// call handles sources, sanitizers and calls into workspace functions
+func (t *taint) call(call ssa.CallInstruction) {
+	common := call.Common()
+	value := call.Value()
+	callee := common.StaticCallee()
+	name := funcName(callee)
+
+	if desc, ok := t.sources[name]; ok && value != nil {
+		t.mark(value, nil, call.Pos(), "source: "+desc)
+		return
+	}
+	if t.clean[name] {
+		return
+	}
+
+	args := common.Args
+	if common.IsInvoke() {
+		args = append([]ssa.Value{common.Value}, args...)
+	}
+
+	// Calls into the workspace are followed through parameters and
+	// return values
+	if callee != nil && callee.Blocks != nil && t.prog.InWorkspace(callee) {
+		for i, arg := range args {
+			if i < len(callee.Params) && t.tainted(arg) {
+				param := callee.Params[i]
+				t.mark(param, arg, param.Pos(), fmt.Sprintf("passed to %s as %s", shortName(callee), param.Name()))
+			}
+		}
+		if value != nil {
+			if ret := t.taintedReturn(callee); ret != nil {
+				t.mark(value, ret, call.Pos(), "returned from "+shortName(callee))
+			}
+		}
+		return
+	}
+
+	from := t.firstTainted(args)
+	if from == nil && !common.IsInvoke() && callee == nil {
+		// A call through a tainted function value
+		from = t.firstTainted([]ssa.Value{common.Value})
+	}
+	if from == nil {
+		return
+	}
+	t.mark(value, from, call.Pos(), "")
+	for _, arg := range args {
+		if arg != from && isPointerLike(arg.Type()) {
+			t.mark(root(arg), from, call.Pos(), "")
+		}
+	}
+}
+
+// taintedReturn returns a tainted result of fn, if any
+func (t *taint) taintedReturn(fn *ssa.Function) ssa.Value {
+	for _, b := range fn.Blocks {
+		if ret, ok := b.Instrs[len(b.Instrs)-1].(*ssa.Return); ok {
+			if v := t.firstTainted(ret.Results); v != nil {
+				return v
+			}
+		}
+	}
+	return nil
+}
+
+// sourceType reports whether fields of t (or *t) are untrusted
+func (t *taint) sourceType(typ types.Type) bool {
+	if ptr, ok := typ.Underlying().(*types.Pointer); ok {
+		typ = ptr.Elem()
+	}
+	named, ok := types.Unalias(typ).(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return false
+	}
+	return t.types[named.Obj().Pkg().Path()+"."+named.Obj().Name()]
+}
+
+// sinks returns the sink rules an instruction violates
+func (t *taint) sinks(instr ssa.Instruction) []TaintResult {
+	var results []TaintResult
+	switch in := instr.(type) {
+	case ssa.CallInstruction:
+		common := in.Common()
+		callee := common.StaticCallee()
+		name := funcName(callee)
+		if name == "" {
+			return nil
+		}
+		offset := 0
+		if callee.Signature.Recv() != nil {
+			offset = 1
+		}
+		for s := range t.spec.Sinks {
+			sink := &t.spec.Sinks[s]
+			for _, c := range sink.Calls {
+				if c.Function != name {
+					continue
+				}
+				var args []ssa.Value
+				if len(c.Args) == 0 {
+					args = common.Args[offset:]
+				}
+				for _, i := range c.Args {
+					if i+offset < len(common.Args) {
+						args = append(args, common.Args[i+offset])
+					}
+				}
+				if v := t.firstTainted(args); v != nil {
+					results = append(results, t.result(sink, name, v, in.Pos()))
+				}
+			}
+		}
+
+	case *ssa.ChangeType, *ssa.Convert:
+		v := in.(ssa.Value)
+		named, ok := types.Unalias(v.Type()).(*types.Named)
+		if !ok || named.Obj().Pkg() == nil {
+			return nil
+		}
+		typeName := named.Obj().Pkg().Path() + "." + named.Obj().Name()
+		operand := in.Operands(nil)[0]
+		for s := range t.spec.Sinks {
+			sink := &t.spec.Sinks[s]
+			for _, typ := range sink.Types {
+				if typ == typeName && t.tainted(*operand) {
+					results = append(results, t.result(sink, typeName, *operand, v.Pos()))
+				}
+			}
+		}
+	}
+	return results
+}
+
+// result builds the path from the source of v to a sink
+func (t *taint) result(sink *TaintSink, callee string, v ssa.Value, pos token.Pos) TaintResult {
+	fset := t.prog.Fset
+	r := TaintResult{Sink: sink, Callee: callee, Pos: fset.Position(pos)}
+
+	var steps []TaintStep
+	for cur := v; cur != nil; {
+		f := t.facts[cur]
+		if f == nil {
+			break
+		}
+		if p := fset.Position(f.pos); p.IsValid() {
+			steps = append(steps, TaintStep{Pos: p, Message: f.note})
+		}
+		if f.prev == nil {
+			r.Source = trimSource(f.note)
+		}
+		cur = f.prev
+	}
+
+	// Reverse to source-first order and drop repeated lines
+	for i, j := 0, len(steps)-1; i < j; i, j = i+1, j-1 {
+		steps[i], steps[j] = steps[j], steps[i]
+	}
+	var flow []TaintStep
+	for _, s := range steps {
+		if n := len(flow); n > 0 && flow[n-1].Pos.Filename == s.Pos.Filename && flow[n-1].Pos.Line == s.Pos.Line {
+			if flow[n-1].Message == "" {
+				flow[n-1].Message = s.Message
+			}
+			continue
+		}
+		flow = append(flow, s)
+	}
+	if len(flow) > maxFlowSteps {
+		flow = append(flow[:1], flow[len(flow)-maxFlowSteps+1:]...)
+	}
+	flow = append(flow, TaintStep{Pos: r.Pos, Message: "sink: " + callee})
+	r.Steps = flow
+	return r
+}
+
+// trimSource strips the "source: " prefix from a note
+func trimSource(note string) string {
+	const prefix = "source: "
+	if len(note) > len(prefix) && note[:len(prefix)] == prefix {
+		return note[len(prefix):]
+	}
+	return note
+}
+
+// root returns the variable an address or reference points into
+func root(v ssa.Value) ssa.Value {
+	for {
+		switch x := v.(type) {
+		case *ssa.FieldAddr:
+			v = x.X
+		case *ssa.IndexAddr:
+			v = x.X
+		case *ssa.Slice:
+			v = x.X
+		case *ssa.ChangeType:
+			v = x.X
+		case *ssa.MakeInterface:
+			v = x.X
+		default:
+			return v
+		}
+	}
+}
+
+// isPointerLike reports whether a callee can write through a value
+func isPointerLike(typ types.Type) bool {
+	switch typ.Underlying().(type) {
+	case *types.Pointer, *types.Slice, *types.Map, *types.Chan, *types.Interface:
+		return true
+	}
+	return false
+}
+
+// funcName returns the go/ssa name of a callee, "" for dynamic calls
+func funcName(fn *ssa.Function) string {
+	if fn == nil {
+		return ""
+	}
+	if origin := fn.Origin(); origin != nil {
+		fn = origin
+	}
+	return fn.String()
+}
+
+// shortName names a function by package name and symbol
+func shortName(fn *ssa.Function) string {
+	_, name := SymbolName(fn)
+	if fn.Pkg == nil {
+		return name
+	}
+	return fn.Pkg.Pkg.Name() + "." + name
+}
+
+// fieldName names field i of a struct or pointer to struct, as Type.Field
+func fieldName(typ types.Type, i int) string {
+	if ptr, ok := typ.Underlying().(*types.Pointer); ok {
+		typ = ptr.Elem()
+	}
+	st, ok := typ.Underlying().(*types.Struct)
+	if !ok || i >= st.NumFields() {
+		return typ.String()
+	}
+	name := typ.String()
+	if named, ok := types.Unalias(typ).(*types.Named); ok {
+		name = named.Obj().Pkg().Name() + "." + named.Obj().Name()
+	}
+	return name + "." + st.Field(i).Name()
+}
@@ -0,0 +1,102 @@
+package iac
+
+import (
+	"path"
+	"strings"
+)
+
+// Instruction is one Dockerfile instruction with continuation lines joined
+type Instruction struct {
+	// Cmd is the upper-case instruction name, e.g. "FROM"
+	Cmd  string
+	Args string
+	Line int
+	// Stage is the index of the build stage, counted from 0
+	Stage int
+	// StageRef marks a FROM that starts from an earlier stage or scratch
+	StageRef bool
+}
+
+// IsDockerfile reports whether a file name is a Dockerfile or Containerfile
+func IsDockerfile(name string) bool {
+	base := path.Base(name)
+	lower := strings.ToLower(base)
+	return lower == "dockerfile" || lower == "containerfile" ||
+		strings.HasPrefix(lower, "dockerfile.") || strings.HasSuffix(lower, ".dockerfile")
+}
+
+// ParseDockerfile splits a Dockerfile into instructions. Comments and
+// here-documents are skipped.
+func ParseDockerfile(data []byte) []Instruction {
+	var (
+		instrs  []Instruction
+		stage   = -1
+		stages  = make(map[string]bool)
+		current *Instruction
+		heredoc string
+	)
+
+	lines := strings.Split(string(data), "\n")
+	for i, raw := range lines {
+		line := strings.TrimRight(raw, "\r")
+		trimmed := strings.TrimSpace(line)
+
+		if heredoc != "" {
+			if trimmed == heredoc {
+				heredoc = ""
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "#") || (trimmed == "" && current == nil) {
+			continue
+		}
+
+		if current == nil {
+			cmd, args, _ := strings.Cut(trimmed, " ")
+			current = &Instruction{Cmd: strings.ToUpper(cmd), Args: strings.TrimSpace(args), Line: i + 1}
+		} else {
+			current.Args += " " + trimmed
+		}
+
+		if strings.HasSuffix(current.Args, "\\") {
+			current.Args = strings.TrimSpace(strings.TrimSuffix(current.Args, "\\"))
+			continue
+		}
+
+		if idx := strings.Index(current.Args, "<<"); idx >= 0 {
+			marker := strings.Fields(current.Args[idx+2:])
+			if len(marker) > 0 {
+				heredoc = strings.Trim(strings.TrimPrefix(marker[0], "-"), `"'`)
+			}
+		}
+
+		if current.Cmd == "FROM" {
+			stage++
+			image, alias := fromImage(current.Args)
+			current.StageRef = stages[strings.ToLower(image)] || image == "scratch"
+			if alias != "" {
+				stages[strings.ToLower(alias)] = true
+			}
+		}
+		current.Stage = stage
+		instrs = append(instrs, *current)
+		current = nil
+	}
+	return instrs
+}
+
+// fromImage returns the image and stage alias of FROM arguments
+func fromImage(args string) (image, alias string) {
+	fields := strings.Fields(args)
+	for len(fields) > 0 && strings.HasPrefix(fields[0], "--") {
+		fields = fields[1:]
+	}
+	if len(fields) == 0 {
+		return "", ""
+	}
+	image = fields[0]
+	if len(fields) >= 3 && strings.EqualFold(fields[1], "AS") {
+		alias = fields[2]
+	}
+	return image, alias
+}
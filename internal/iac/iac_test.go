@@ -0,0 +1,194 @@
+package iac
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func compiled(t *testing.T) []Rule {
+	t.Helper()
+	rules := append([]Rule{}, DefaultRules...)
+	for i := range rules {
+		if err := rules[i].Compile(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return rules
+}
+
+func summarize(results []Result) []string {
+	var out []string
+	for _, r := range results {
+		out = append(out, fmt.Sprintf("%s:%d", r.Rule.ID, r.Line))
+	}
+	sort.Strings(out)
+	return out
+}
+
+func TestParseDockerfile(t *testing.T) {
+	src := `# syntax=docker/dockerfile:1
+FROM golang:1.25 AS build
+RUN go build \
+    -o /app .
+RUN <<EOF
+USER root
+EOF
+FROM build AS test
+FROM scratch
+COPY --from=build /app /app
+`
+	instrs := ParseDockerfile([]byte(src))
+
+	var got []string
+	for _, in := range instrs {
+		got = append(got, fmt.Sprintf("%d:%d:%s %s:%v", in.Line, in.Stage, in.Cmd, in.Args, in.StageRef))
+	}
+	want := []string{
+		"2:0:FROM golang:1.25 AS build:false",
+		"3:0:RUN go build -o /app .:false",
+		"5:0:RUN <<EOF:false",
+		"8:1:FROM build AS test:true",
+		"9:2:FROM scratch:true",
+		"10:2:COPY --from=build /app /app:false",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Unexpected instructions:\n%s", strings.Join(got, "\n"))
+	}
+}
+
+func TestCheckDockerfile(t *testing.T) {
+	src := `FROM node AS deps
+FROM registry.example.com:5000/base:latest
+FROM alpine:3.20
+ADD https://example.com/tool.tar.gz /tmp/
+ADD --checksum=sha256:abc https://example.com/ok.tar.gz /tmp/
+ENV APP_ENV=prod DB_PASSWORD=hunter2
+ARG API_TOKEN
+ENV SECRET_KEY=$SECRET_KEY
+USER app
+USER root
+`
+	got := summarize(CheckDockerfile(compiled(t), ParseDockerfile([]byte(src))))
+	want := []string{
+		"iac/dockerfile-add-url:4",
+		"iac/dockerfile-latest-tag:1",
+		"iac/dockerfile-latest-tag:2",
+		"iac/dockerfile-root-user:3",
+		"iac/dockerfile-secret-env:6",
+	}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+
+	clean := "FROM golang:1.25@sha256:abc AS build\nFROM gcr.io/distroless/static:nonroot\nUSER 65532:65532\n"
+	if got := CheckDockerfile(compiled(t), ParseDockerfile([]byte(clean))); len(got) != 0 {
+		t.Errorf("Expected no findings, got %v", summarize(got))
+	}
+}
+
+func TestCheckManifests(t *testing.T) {
+	src := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+        - name: app
+          image: nginx
+          securityContext:
+            privileged: true
+            runAsUser: 0
+          env:
+            - name: DB_PASSWORD
+              value: hunter2
+            - name: API_TOKEN
+              valueFrom:
+                secretKeyRef: {name: api, key: token}
+        - name: sidecar
+          image: envoy:v1.30
+          resources:
+            limits: {cpu: 100m, memory: 64Mi}
+      volumes:
+        - name: docker
+          hostPath:
+            path: /var/run/docker.sock
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+data:
+  image: nginx
+`
+	targets, err := ParseManifests([]byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := summarize(CheckTargets(compiled(t), targets))
+	want := []string{
+		"iac/k8s-host-path:27",
+		"iac/k8s-latest-tag:10",
+		"iac/k8s-missing-limits:9",
+		"iac/k8s-privileged:12",
+		"iac/k8s-run-as-root:13",
+		"iac/k8s-secret-env:15",
+	}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestCheckCompose(t *testing.T) {
+	src := `services:
+  web:
+    image: myapp:latest
+    privileged: true
+    user: root
+    environment:
+      - API_KEY=abc123
+      - DB_PASSWORD=${DB_PASSWORD}
+    volumes:
+      - /var/run/docker.sock:/var/run/docker.sock
+      - data:/data
+  db:
+    image: postgres:16
+    environment:
+      POSTGRES_PASSWORD: example
+volumes:
+  data:
+`
+	targets, err := ParseManifests([]byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := summarize(CheckTargets(compiled(t), targets))
+	want := []string{
+		"iac/compose-host-path:10",
+		"iac/compose-latest-tag:3",
+		"iac/compose-privileged:4",
+		"iac/compose-root-user:5",
+		"iac/compose-secret-env:15",
+		"iac/compose-secret-env:7",
+	}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestCompileRejectsInvalidRules(t *testing.T) {
+	for _, rule := range []Rule{
+		{Kind: KindContainer, Path: "image"},
+		{ID: "x", Kind: "terraform", Path: "image"},
+		{ID: "x", Kind: KindDockerfile},
+		{ID: "x", Kind: KindPod},
+		{ID: "x", Kind: KindContainer, Path: "image", Match: "("},
+	} {
+		if err := rule.Compile(); err == nil {
+			t.Errorf("Expected error for %+v", rule)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package iac
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Target kinds that YAML rules apply to
+const (
+	KindContainer = "container"
+	KindPod       = "pod"
+	KindService   = "service"
+)
+
+// Target is a Kubernetes container or pod spec, or a Compose service
+type Target struct {
+	Kind string
+	// Name identifies the target, e.g. "Deployment/web container app"
+	Name string
+	Node *yaml.Node
+}
+
+// podSpecPaths locate the pod spec in each workload kind
+var podSpecPaths = map[string][]string{
+	"Pod":                   {"spec"},
+	"Deployment":            {"spec", "template", "spec"},
+	"StatefulSet":           {"spec", "template", "spec"},
+	"DaemonSet":             {"spec", "template", "spec"},
+	"ReplicaSet":            {"spec", "template", "spec"},
+	"ReplicationController": {"spec", "template", "spec"},
+	"Job":                   {"spec", "template", "spec"},
+	"CronJob":               {"spec", "jobTemplate", "spec", "template", "spec"},
+}
+
+// ParseManifests returns the Kubernetes workloads and Compose services in
+// a YAML file; other YAML yields no targets
+func ParseManifests(data []byte) ([]Target, error) {
+	var targets []Target
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		var doc yaml.Node
+		err := dec.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			return targets, nil
+		}
+		if err != nil {
+			return targets, err
+		}
+		if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+			continue
+		}
+		root := doc.Content[0]
+
+		if kind := scalar(lookup(root, "kind")); kind != "" && lookup(root, "apiVersion") != nil {
+			if kind == "List" {
+				if items := lookup(root, "items"); items != nil && items.Kind == yaml.SequenceNode {
+					for _, item := range items.Content {
+						targets = append(targets, kubeTargets(item)...)
+					}
+				}
+				continue
+			}
+			targets = append(targets, kubeTargets(root)...)
+			continue
+		}
+
+		if services := lookup(root, "services"); services != nil && services.Kind == yaml.MappingNode {
+			for i := 0; i+1 < len(services.Content); i += 2 {
+				svc := services.Content[i+1]
+				if svc.Kind != yaml.MappingNode {
+					continue
+				}
+				normalizeEnvironment(svc)
+				targets = append(targets, Target{
+					Kind: KindService,
+					Name: "service " + services.Content[i].Value,
+					Node: svc,
+				})
+			}
+		}
+	}
+}
+
+// kubeTargets returns the pod spec and containers of a workload object
+func kubeTargets(obj *yaml.Node) []Target {
+	kind := scalar(lookup(obj, "kind"))
+	keys, ok := podSpecPaths[kind]
+	if !ok {
+		return nil
+	}
+	spec := obj
+	for _, key := range keys {
+		if spec = lookup(spec, key); spec == nil {
+			return nil
+		}
+	}
+
+	name := kind
+	if n := scalar(lookup(lookup(obj, "metadata"), "name")); n != "" {
+		name += "/" + n
+	}
+
+	targets := []Target{{Kind: KindPod, Name: name, Node: spec}}
+	for _, key := range []string{"initContainers", "containers"} {
+		list := lookup(spec, key)
+		if list == nil || list.Kind != yaml.SequenceNode {
+			continue
+		}
+		for _, c := range list.Content {
+			targets = append(targets, Target{
+				Kind: KindContainer,
+				Name: fmt.Sprintf("%s container %s", name, scalar(lookup(c, "name"))),
+				Node: c,
+			})
+		}
+	}
+	return targets
+}
+
+// normalizeEnvironment turns a Compose environment list ("KEY=value")
+// into a mapping so rules can match keys and values alike
+func normalizeEnvironment(svc *yaml.Node) {
+	env := lookup(svc, "environment")
+	if env == nil || env.Kind != yaml.SequenceNode {
+		return
+	}
+	mapping := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Line: env.Line, Column: env.Column}
+	for _, item := range env.Content {
+		key, value, _ := strings.Cut(item.Value, "=")
+		mapping.Content = append(mapping.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key, Line: item.Line, Column: item.Column},
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value, Line: item.Line, Column: item.Column},
+		)
+	}
+	*env = *mapping
+}
+
+// lookup returns the value of key in a mapping node
+func lookup(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// scalar returns the value of a scalar node, or ""
+func scalar(node *yaml.Node) string {
+	if node == nil || node.Kind != yaml.ScalarNode {
+		return ""
+	}
+	return node.Value
+}
+
+// match is a node selected by a rule path; key is set for map entries
+type match struct {
+	key  string
+	node *yaml.Node
+}
+
+// selectPath evaluates a dotted path: "a.b" descends into mappings,
+// "a[]" iterates a sequence and "*" iterates the entries of a mapping
+func selectPath(node *yaml.Node, path string) []match {
+	current := []match{{node: node}}
+	for _, seg := range strings.Split(path, ".") {
+		var next []match
+		for _, m := range current {
+			switch {
+			case seg == "*":
+				if m.node.Kind == yaml.MappingNode {
+					for i := 0; i+1 < len(m.node.Content); i += 2 {
+						next = append(next, match{key: m.node.Content[i].Value, node: m.node.Content[i+1]})
+					}
+				}
+			case strings.HasSuffix(seg, "[]"):
+				list := lookup(m.node, strings.TrimSuffix(seg, "[]"))
+				if list != nil && list.Kind == yaml.SequenceNode {
+					for _, item := range list.Content {
+						next = append(next, match{node: item})
+					}
+				}
+			default:
+				if child := lookup(m.node, seg); child != nil {
+					next = append(next, match{key: seg, node: child})
+				}
+			}
+		}
+		current = next
+	}
+	return current
+}
@@ -0,0 +1,361 @@
+// Package iac checks Dockerfiles, Kubernetes manifests and Compose files
+// for insecure configuration using declarative rules
+package iac
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Rule kinds besides the YAML target kinds
+const KindDockerfile = "dockerfile"
+
+// Rule is one declarative check.
+//
+// Dockerfile rules select instructions by name with Instruction and match
+// their arguments. YAML rules select values under a target with Path; a
+// rule matches when the selected value matches Match, its map key matches
+// Key and, for mappings, every Where field matches. With Absent set the
+// rule reports when nothing matches instead: in a Dockerfile, the last
+// selected instruction of the final stage is checked.
+type Rule struct {
+	ID          string            `yaml:"id"`
+	Kind        string            `yaml:"kind"`
+	Message     string            `yaml:"message"`
+	Description string            `yaml:"description"`
+	Severity    string            `yaml:"severity"`
+	CWE         string            `yaml:"cwe"`
+	Instruction string            `yaml:"instruction"`
+	Path        string            `yaml:"path"`
+	Key         string            `yaml:"key"`
+	Match       string            `yaml:"match"`
+	NotMatch    string            `yaml:"not_match"`
+	Where       map[string]string `yaml:"where"`
+	Absent      bool              `yaml:"absent"`
+
+	instruction *regexp.Regexp
+	key         *regexp.Regexp
+	match       *regexp.Regexp
+	notMatch    *regexp.Regexp
+	where       map[string]*regexp.Regexp
+}
+
+// secretName matches variable names that usually hold credentials
+const secretName = `(?i)(passw(or)?d|secret|token|api_?key|access_?key|private_?key|credential)`
+
+// mutableImage matches image references without a tag or digest, or
+// tagged latest
+const mutableImage = `^(?:[^/\s@]+/)*[^/\s:@]+(?::latest)?$`
+
+// DefaultRules are the built-in checks
+var DefaultRules = []Rule{
+	{
+		ID:          "iac/dockerfile-root-user",
+		Kind:        KindDockerfile,
+		Message:     "Container runs as root: the final stage sets no non-root USER",
+		Description: "Add a USER instruction naming an unprivileged user to the final stage.",
+		Severity:    "warning",
+		CWE:         "CWE-250",
+		Instruction: "USER",
+		NotMatch:    `^(root|0)(:.*)?$`,
+		Absent:      true,
+	},
+	{
+		ID:          "iac/dockerfile-latest-tag",
+		Kind:        KindDockerfile,
+		Message:     "Base image is not pinned to a version",
+		Description: "Pin base images to a version tag or digest so builds are reproducible.",
+		Severity:    "warning",
+		CWE:         "CWE-1357",
+		Instruction: "FROM",
+		Match:       `^(?:--\S+\s+)*(?:[^/\s@]+/)*[^/\s:@]+(?::latest)?(?:\s+(?i:as)\s+\S+)?$`,
+		NotMatch:    `\$`,
+	},
+	{
+		ID:          "iac/dockerfile-add-url",
+		Kind:        KindDockerfile,
+		Message:     "ADD downloads a remote file without verifying it",
+		Description: "Download with RUN and verify a checksum, or use ADD --checksum.",
+		Severity:    "warning",
+		CWE:         "CWE-494",
+		Instruction: "ADD",
+		Match:       `(?:^|\s)https?://`,
+		NotMatch:    `--checksum=`,
+	},
+	{
+		ID:          "iac/dockerfile-secret-env",
+		Kind:        KindDockerfile,
+		Message:     "Secret is baked into the image through ENV or ARG",
+		Description: "Pass secrets at run time or with build secrets (RUN --mount=type=secret).",
+		Severity:    "error",
+		CWE:         "CWE-798",
+		Instruction: "ENV|ARG",
+		Match:       `(?i)\w*` + strings.TrimPrefix(secretName, "(?i)") + `\w*(=|\s+)["']?[^\s"'$]`,
+	},
+	{
+		ID:          "iac/k8s-privileged",
+		Kind:        KindContainer,
+		Message:     "Container runs privileged",
+		Description: "Privileged containers have full access to the host; drop privileged and grant only the capabilities needed.",
+		Severity:    "error",
+		CWE:         "CWE-250",
+		Path:        "securityContext.privileged",
+		Match:       `^true$`,
+	},
+	{
+		ID:          "iac/k8s-run-as-root",
+		Kind:        KindContainer,
+		Message:     "Container runs as root (runAsUser: 0)",
+		Description: "Run as an unprivileged user and set runAsNonRoot: true.",
+		Severity:    "warning",
+		CWE:         "CWE-250",
+		Path:        "securityContext.runAsUser",
+		Match:       `^0$`,
+	},
+	{
+		ID:          "iac/k8s-latest-tag",
+		Kind:        KindContainer,
+		Message:     "Container image is not pinned to a version",
+		Description: "Pin images to a version tag or digest.",
+		Severity:    "warning",
+		CWE:         "CWE-1357",
+		Path:        "image",
+		Match:       mutableImage,
+	},
+	{
+		ID:          "iac/k8s-secret-env",
+		Kind:        KindContainer,
+		Message:     "Secret is set as a literal environment value",
+		Description: "Load secrets with valueFrom.secretKeyRef instead of literal values.",
+		Severity:    "error",
+		CWE:         "CWE-798",
+		Path:        "env[]",
+		Where:       map[string]string{"name": secretName, "value": `\S`},
+	},
+	{
+		ID:          "iac/k8s-missing-limits",
+		Kind:        KindContainer,
+		Message:     "Container has no resource limits",
+		Description: "Set resources.limits for cpu and memory so one container cannot exhaust the node.",
+		Severity:    "warning",
+		CWE:         "CWE-770",
+		Path:        "resources.limits",
+		Absent:      true,
+	},
+	{
+		ID:          "iac/k8s-host-path",
+		Kind:        KindPod,
+		Message:     "Pod mounts a hostPath volume",
+		Description: "hostPath volumes expose the node's filesystem; use a persistent volume instead.",
+		Severity:    "warning",
+		CWE:         "CWE-668",
+		Path:        "volumes[].hostPath",
+	},
+	{
+		ID:          "iac/compose-privileged",
+		Kind:        KindService,
+		Message:     "Service runs privileged",
+		Description: "Privileged containers have full access to the host; drop privileged and add only the capabilities needed.",
+		Severity:    "error",
+		CWE:         "CWE-250",
+		Path:        "privileged",
+		Match:       `^true$`,
+	},
+	{
+		ID:          "iac/compose-root-user",
+		Kind:        KindService,
+		Message:     "Service runs as root",
+		Description: "Set user to an unprivileged user.",
+		Severity:    "warning",
+		CWE:         "CWE-250",
+		Path:        "user",
+		Match:       `^(root|0)(:.*)?$`,
+	},
+	{
+		ID:          "iac/compose-latest-tag",
+		Kind:        KindService,
+		Message:     "Service image is not pinned to a version",
+		Description: "Pin images to a version tag or digest.",
+		Severity:    "warning",
+		CWE:         "CWE-1357",
+		Path:        "image",
+		Match:       mutableImage,
+	},
+	{
+		ID:          "iac/compose-secret-env",
+		Kind:        KindService,
+		Message:     "Secret is set as a literal environment value",
+		Description: "Reference secrets from the environment (${VAR}) or use Compose secrets.",
+		Severity:    "error",
+		CWE:         "CWE-798",
+		Path:        "environment.*",
+		Key:         secretName,
+		Match:       `^[^$\s]`,
+	},
+	{
+		ID:          "iac/compose-host-path",
+		Kind:        KindService,
+		Message:     "Service bind-mounts a host path",
+		Description: "Bind mounts expose the host's filesystem; use a named volume instead.",
+		Severity:    "note",
+		CWE:         "CWE-668",
+		Path:        "volumes[]",
+		Match:       `^(/|~)`,
+	},
+}
+
+// Compile validates a rule and compiles its expressions
+func (r *Rule) Compile() error {
+	if r.ID == "" {
+		return fmt.Errorf("rule has no id")
+	}
+	switch r.Kind {
+	case KindDockerfile:
+		if r.Instruction == "" {
+			return fmt.Errorf("rule %s: dockerfile rules need an instruction", r.ID)
+		}
+	case KindContainer, KindPod, KindService:
+		if r.Path == "" {
+			return fmt.Errorf("rule %s: %s rules need a path", r.ID, r.Kind)
+		}
+	default:
+		return fmt.Errorf("rule %s: unknown kind %q", r.ID, r.Kind)
+	}
+	if r.Severity == "" {
+		r.Severity = "warning"
+	}
+
+	var err error
+	compile := func(expr string) *regexp.Regexp {
+		if expr == "" || err != nil {
+			return nil
+		}
+		var re *regexp.Regexp
+		if re, err = regexp.Compile(expr); err != nil {
+			err = fmt.Errorf("rule %s: %w", r.ID, err)
+		}
+		return re
+	}
+	if r.Instruction != "" {
+		r.instruction = compile(`^(?i:` + r.Instruction + `)$`)
+	}
+	r.key = compile(r.Key)
+	r.match = compile(r.Match)
+	r.notMatch = compile(r.NotMatch)
+	r.where = make(map[string]*regexp.Regexp, len(r.Where))
+	for field, expr := range r.Where {
+		r.where[field] = compile(expr)
+	}
+	return err
+}
+
+// Result is a rule match
+type Result struct {
+	Rule   *Rule
+	Line   int
+	Column int
+	// Subject names what matched, e.g. the container or the instruction
+	Subject string
+}
+
+// CheckDockerfile applies the Dockerfile rules to parsed instructions
+func CheckDockerfile(rules []Rule, instrs []Instruction) []Result {
+	var results []Result
+	if len(instrs) == 0 {
+		return nil
+	}
+	final := instrs[len(instrs)-1].Stage
+	finalFrom := instrs[0]
+	for _, in := range instrs {
+		if in.Cmd == "FROM" && in.Stage == final {
+			finalFrom = in
+		}
+	}
+
+	for i := range rules {
+		rule := &rules[i]
+		if rule.Kind != KindDockerfile {
+			continue
+		}
+
+		if rule.Absent {
+			var last *Instruction
+			for j := range instrs {
+				if instrs[j].Stage == final && rule.instruction.MatchString(instrs[j].Cmd) {
+					last = &instrs[j]
+				}
+			}
+			if last == nil || !rule.matchText(last.Args) {
+				results = append(results, Result{Rule: rule, Line: finalFrom.Line, Column: 1, Subject: "FROM " + finalFrom.Args})
+			}
+			continue
+		}
+
+		for _, in := range instrs {
+			if in.StageRef || !rule.instruction.MatchString(in.Cmd) || !rule.matchText(in.Args) {
+				continue
+			}
+			results = append(results, Result{Rule: rule, Line: in.Line, Column: 1, Subject: in.Cmd + " " + in.Args})
+		}
+	}
+	return results
+}
+
+// CheckTargets applies the YAML rules to Kubernetes and Compose targets
+func CheckTargets(rules []Rule, targets []Target) []Result {
+	var results []Result
+	for _, t := range targets {
+		for i := range rules {
+			rule := &rules[i]
+			if rule.Kind != t.Kind {
+				continue
+			}
+
+			var matched []match
+			for _, m := range selectPath(t.Node, rule.Path) {
+				if rule.matchNode(m) {
+					matched = append(matched, m)
+				}
+			}
+
+			if rule.Absent {
+				if len(matched) == 0 {
+					results = append(results, Result{Rule: rule, Line: t.Node.Line, Column: t.Node.Column, Subject: t.Name})
+				}
+				continue
+			}
+			for _, m := range matched {
+				results = append(results, Result{Rule: rule, Line: m.node.Line, Column: m.node.Column, Subject: t.Name})
+			}
+		}
+	}
+	return results
+}
+
+// matchText applies Match and NotMatch to text
+func (r *Rule) matchText(text string) bool {
+	if r.match != nil && !r.match.MatchString(text) {
+		return false
+	}
+	return r.notMatch == nil || !r.notMatch.MatchString(text)
+}
+
+// matchNode applies Key, Match, NotMatch and Where to a selected node
+func (r *Rule) matchNode(m match) bool {
+	if r.key != nil && !r.key.MatchString(m.key) {
+		return false
+	}
+	if (r.match != nil || r.notMatch != nil) && (m.node.Kind != yaml.ScalarNode || !r.matchText(m.node.Value)) {
+		return false
+	}
+	for field, re := range r.where {
+		value := lookup(m.node, field)
+		if value == nil || value.Kind != yaml.ScalarNode || !re.MatchString(value.Value) {
+			return false
+		}
+	}
+	return true
+}
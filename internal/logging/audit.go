@@ -5,11 +5,13 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"sync"
 	"time"
 )
 
-// AuditLogger handles structured audit logging
+// AuditLogger handles structured audit logging. It is safe for concurrent use.
 type AuditLogger struct {
+	mu     sync.Mutex
 	file   *os.File
 	redact bool
 }
@@ -146,6 +148,8 @@ func (a *AuditLogger) writeEntry(entry LogEntry) {
 		return
 	}
 
+	a.mu.Lock()
+	defer a.mu.Unlock()
 	fmt.Fprintln(a.file, string(data))
 }
 
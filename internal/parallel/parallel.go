@@ -0,0 +1,108 @@
+package parallel
+
+import (
+	"context"
+	"runtime"
+	"time"
+)
+
+// Task is a named unit of work run by the pool
+type Task[T any] struct {
+	Name string
+	Run  func(ctx context.Context) (T, error)
+}
+
+// Result is the outcome of a Task. Done is false when the task had not
+// finished by the time the context was cancelled; Err then holds the
+// context error.
+type Result[T any] struct {
+	Name     string
+	Value    T
+	Err      error
+	Duration time.Duration
+	Done     bool
+}
+
+// DefaultConcurrency is used when a non-positive concurrency is requested
+func DefaultConcurrency() int {
+	return runtime.NumCPU()
+}
+
+// Run executes tasks with at most concurrency running at once and returns
+// one result per task, in task order. Run returns as soon as every task
+// has finished or ctx is done, whichever comes first, so results from
+// tasks that completed before a deadline are kept while stragglers are
+// reported as not done. Tasks are expected to honor ctx.
+func Run[T any](ctx context.Context, concurrency int, tasks []Task[T]) []Result[T] {
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency()
+	}
+
+	results := make([]Result[T], len(tasks))
+	for i, task := range tasks {
+		results[i] = Result[T]{Name: task.Name}
+	}
+	if len(tasks) == 0 {
+		return results
+	}
+
+	type indexed struct {
+		index  int
+		result Result[T]
+	}
+
+	// Buffered so abandoned workers never block after Run returns
+	done := make(chan indexed, len(tasks))
+	slots := make(chan struct{}, concurrency)
+	start := time.Now()
+
+	go func() {
+		for i, task := range tasks {
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+
+			go func(i int, task Task[T]) {
+				defer func() { <-slots }()
+
+				taskStart := time.Now()
+				value, err := task.Run(ctx)
+				done <- indexed{i, Result[T]{
+					Name:     task.Name,
+					Value:    value,
+					Err:      err,
+					Duration: time.Since(taskStart),
+					Done:     true,
+				}}
+			}(i, task)
+		}
+	}()
+
+	for remaining := len(tasks); remaining > 0; remaining-- {
+		select {
+		case r := <-done:
+			results[r.index] = r.result
+		case <-ctx.Done():
+			// Keep results that raced with the cancellation
+			for drained := false; !drained; {
+				select {
+				case r := <-done:
+					results[r.index] = r.result
+				default:
+					drained = true
+				}
+			}
+			for i := range results {
+				if !results[i].Done {
+					results[i].Err = ctx.Err()
+					results[i].Duration = time.Since(start)
+				}
+			}
+			return results
+		}
+	}
+
+	return results
+}
@@ -0,0 +1,73 @@
+package parallel
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRunBoundsConcurrency(t *testing.T) {
+	var running, peak int32
+
+	var tasks []Task[int]
+	for i := 0; i < 8; i++ {
+		i := i
+		tasks = append(tasks, Task[int]{
+			Name: "task",
+			Run: func(ctx context.Context) (int, error) {
+				n := atomic.AddInt32(&running, 1)
+				for {
+					p := atomic.LoadInt32(&peak)
+					if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+						break
+					}
+				}
+				time.Sleep(10 * time.Millisecond)
+				atomic.AddInt32(&running, -1)
+				return i, nil
+			},
+		})
+	}
+
+	results := Run(context.Background(), 2, tasks)
+
+	if peak > 2 {
+		t.Errorf("Expected at most 2 concurrent tasks, saw %d", peak)
+	}
+	for i, r := range results {
+		if !r.Done || r.Err != nil || r.Value != i {
+			t.Errorf("Expected result %d in order, got %+v", i, r)
+		}
+	}
+}
+
+func TestRunKeepsPartialResults(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	tasks := []Task[string]{
+		{Name: "fast", Run: func(ctx context.Context) (string, error) {
+			return "finished", nil
+		}},
+		{Name: "slow", Run: func(ctx context.Context) (string, error) {
+			<-ctx.Done()
+			time.Sleep(time.Second)
+			return "", ctx.Err()
+		}},
+	}
+
+	start := time.Now()
+	results := Run(ctx, 2, tasks)
+
+	if time.Since(start) > 500*time.Millisecond {
+		t.Error("Expected Run to return at the deadline")
+	}
+	if !results[0].Done || results[0].Value != "finished" {
+		t.Errorf("Expected fast task result, got %+v", results[0])
+	}
+	if results[1].Done || !errors.Is(results[1].Err, context.DeadlineExceeded) {
+		t.Errorf("Expected slow task to time out, got %+v", results[1])
+	}
+}
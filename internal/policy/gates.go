@@ -0,0 +1,105 @@
+package policy
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Gate kinds
+const (
+	GateSecurity = "security"
+	GateDeadCode = "deadcode"
+)
+
+// Gate actions
+const (
+	GateFail = "fail"
+	GateWarn = "warn"
+)
+
+// Severities is the canonical severity scale, most severe first
+var Severities = []string{"critical", "high", "medium", "low", "info"}
+
+// Confidences are finding confidence levels, most certain first
+var Confidences = []string{"high", "medium", "low"}
+
+// Gate decides whether a scan fails. It counts the findings or dead-code
+// symbols that match its filters and trips when there are more than Max.
+type Gate struct {
+	Name string `yaml:"name" json:"name"`
+	// Kind is "security" or "deadcode"
+	Kind string `yaml:"kind" json:"kind"`
+	// Action is "fail" (the default) or "warn"
+	Action string `yaml:"action" json:"action"`
+	// Max is how many matches are tolerated
+	Max int `yaml:"max" json:"max"`
+
+	// Security gates: severities to count, given as a list or a minimum
+	Severities    []string `yaml:"severities" json:"severities,omitempty"`
+	MinSeverity   string   `yaml:"min_severity" json:"min_severity,omitempty"`
+	MinConfidence string   `yaml:"min_confidence" json:"min_confidence,omitempty"`
+	// Rules limits the gate to rule IDs or path.Match patterns
+	Rules []string `yaml:"rules" json:"rules,omitempty"`
+
+	// Dead-code gates: only exported symbols, and symbol kinds
+	ExportedOnly bool     `yaml:"exported_only" json:"exported_only,omitempty"`
+	SymbolKinds  []string `yaml:"symbol_kinds" json:"symbol_kinds,omitempty"`
+
+	// ExcludeTests ignores findings and symbols in test code
+	ExcludeTests bool `yaml:"exclude_tests" json:"exclude_tests,omitempty"`
+}
+
+// DefaultGates fail on any security finding and on any dead code, which
+// is what a policy without gates does
+func DefaultGates() []Gate {
+	return []Gate{
+		{Name: "security", Kind: GateSecurity, Action: GateFail},
+		{Name: "deadcode", Kind: GateDeadCode, Action: GateFail},
+	}
+}
+
+// FailOnGate is the security gate for a --fail-on severity threshold
+func FailOnGate(severity string) (Gate, error) {
+	if !oneOf(severity, Severities) {
+		return Gate{}, fmt.Errorf("fail-on: %q is not one of %s", severity, strings.Join(Severities, ", "))
+	}
+	return Gate{Name: "fail-on", Kind: GateSecurity, Action: GateFail, MinSeverity: severity}, nil
+}
+
+// Validate checks the gate's kind, action and levels
+func (g Gate) Validate() error {
+	name := g.Name
+	if name == "" {
+		name = g.Kind
+	}
+	switch g.Kind {
+	case GateSecurity, GateDeadCode:
+	default:
+		return fmt.Errorf("gate %q: kind must be %s or %s", name, GateSecurity, GateDeadCode)
+	}
+	if g.Action != "" && g.Action != GateFail && g.Action != GateWarn {
+		return fmt.Errorf("gate %q: action must be %s or %s", name, GateFail, GateWarn)
+	}
+	if g.Max < 0 {
+		return fmt.Errorf("gate %q: max must not be negative", name)
+	}
+	for _, s := range append(append([]string{}, g.Severities...), g.MinSeverity) {
+		if s != "" && !oneOf(s, Severities) {
+			return fmt.Errorf("gate %q: %q is not one of %s", name, s, strings.Join(Severities, ", "))
+		}
+	}
+	if g.MinConfidence != "" && !oneOf(g.MinConfidence, Confidences) {
+		return fmt.Errorf("gate %q: %q is not one of %s", name, g.MinConfidence, strings.Join(Confidences, ", "))
+	}
+	return nil
+}
+
+// oneOf reports whether s is in list
+func oneOf(s string, list []string) bool {
+	for _, v := range list {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
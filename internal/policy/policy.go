@@ -3,6 +3,7 @@ package policy
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -19,7 +20,11 @@ type Policy struct {
 	Allowlist Allowlist            `yaml:"allowlist" json:"allowlist"`
 	Patch    PatchConfig           `yaml:"patch" json:"patch"`
 	Security SecurityConfig        `yaml:"security" json:"security"`
+	DeadCode DeadCodeConfig        `yaml:"deadcode" json:"deadcode"`
 	Logging  LoggingConfig         `yaml:"logging" json:"logging"`
+	Cache    CacheConfig           `yaml:"cache" json:"cache"`
+	// Gates decide the exit code; DefaultGates apply when none are set
+	Gates    []Gate                `yaml:"gates" json:"gates"`
 }
 
 // Mode defines operational modes
@@ -42,6 +47,7 @@ type Limits struct {
 	MaxFileBytes   int `yaml:"max_file_bytes" json:"max_file_bytes"`
 	MaxPatchBytes  int `yaml:"max_patch_bytes" json:"max_patch_bytes"`
 	MaxIterations  int `yaml:"max_iterations" json:"max_iterations"`
+	MaxConcurrency int `yaml:"max_concurrency" json:"max_concurrency"`
 }
 
 // Allowlist defines allowed commands
@@ -60,6 +66,27 @@ type PatchConfig struct {
 type SecurityConfig struct {
 	DenyPaths  []string `yaml:"deny_paths" json:"deny_paths"`
 	DenyGlobs  []string `yaml:"deny_globs" json:"deny_globs"`
+	Analyzers  map[string]AnalyzerConfig `yaml:"analyzers" json:"analyzers"`
+	// SeverityOverrides sets the severity of findings by rule ID or
+	// path.Match pattern, e.g. "iac/*": low
+	SeverityOverrides map[string]string `yaml:"severity_overrides" json:"severity_overrides"`
+}
+
+// Dead-code modes
+const (
+	// DeadCodeReferences reports declarations nothing references
+	DeadCodeReferences = "references"
+	// DeadCodeReachability also reports functions that are referenced but
+	// unreachable from any entry point
+	DeadCodeReachability = "reachability"
+)
+
+// DeadCodeConfig defines dead-code detection settings
+type DeadCodeConfig struct {
+	// Tags are extra build tags used when loading packages
+	Tags []string `yaml:"tags" json:"tags"`
+	// Mode is DeadCodeReferences, the default, or DeadCodeReachability
+	Mode string `yaml:"mode" json:"mode"`
 }
 
 // LoggingConfig defines logging settings
@@ -67,6 +94,13 @@ type LoggingConfig struct {
 	PIIRedaction bool `yaml:"pii_redaction" json:"pii_redaction"`
 }
 
+// CacheConfig defines the tool result cache
+type CacheConfig struct {
+	Enabled    bool   `yaml:"enabled" json:"enabled"`
+	Dir        string `yaml:"dir" json:"dir"`
+	MaxAgeDays int    `yaml:"max_age_days" json:"max_age_days"`
+}
+
 // DefaultPolicy returns a default policy configuration
 func DefaultPolicy() Policy {
 	return Policy{
@@ -94,15 +128,41 @@ func DefaultPolicy() Policy {
 			MaxFileBytes:   800000,
 			MaxPatchBytes:  200000,
 			MaxIterations:  4,
+			MaxConcurrency: 4,
 		},
 		Allowlist: Allowlist{
 			Commands: [][]string{
 				{"go", "build"},
+				{"go", "build", "./..."},
 				{"go", "test", "-cover"},
+				{"go", "test", "-cover", "./..."},
+				{"go", "test", "-coverprofile=.sentinel/coverage.out", "./..."},
+				{"go", "test", "-run", "*", "-cover", "./..."},
+				{"go", "list", "./..."},
 				{"cargo", "build"},
+				{"cargo", "test"},
+				{"cargo", "test", "*"},
 				{"cargo", "llvm-cov"},
-				{"semgrep", "--config", "auto"},
-				{"codeql", "database", "analyze"},
+				{"npm", "run", "build"},
+				{"npm", "test"},
+				{"npm", "run", "coverage"},
+				{"yarn", "build"},
+				{"yarn", "test"},
+				{"yarn", "coverage"},
+				{"pnpm", "build"},
+				{"pnpm", "test"},
+				{"pnpm", "coverage"},
+				{"pytest"},
+				{"pytest", "--cov"},
+				{"make", "build"},
+				{"make", "test"},
+				{"make", "coverage"},
+				{"semgrep", "--metrics", "off", "--disable-version-check", "--config", "*", "--json", "*"},
+				{"codeql", "database", "create", "*", "--language", "*", "--source-root", ".", "--overwrite"},
+				{"codeql", "database", "analyze", "*", "*", "--format", "sarif-latest", "--output", "*"},
+				{"git", "log", "--reverse", "--no-renames", "--raw", "--no-abbrev", "--format=%x00%H%x00%an%x00%aI", "-n", "*", "--all"},
+				{"git", "log", "--reverse", "--no-renames", "--raw", "--no-abbrev", "--format=%x00%H%x00%an%x00%aI", "-n", "*", "*"},
+				{"git", "cat-file", "blob", "*"},
 				{"gh", "pr", "create"},
 			},
 		},
@@ -118,6 +178,10 @@ func DefaultPolicy() Policy {
 		Logging: LoggingConfig{
 			PIIRedaction: true,
 		},
+		Cache: CacheConfig{
+			Enabled:    true,
+			MaxAgeDays: 30,
+		},
 	}
 }
 
@@ -159,6 +223,18 @@ func (p Policy) Validate() error {
 		return errors.New("max_files must be positive")
 	}
 
+	switch p.DeadCode.Mode {
+	case "", DeadCodeReferences, DeadCodeReachability:
+	default:
+		return fmt.Errorf("deadcode mode must be %s or %s, got %q", DeadCodeReferences, DeadCodeReachability, p.DeadCode.Mode)
+	}
+
+	for _, gate := range p.Gates {
+		if err := gate.Validate(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -173,7 +249,7 @@ func (p Policy) IsPathAllowed(path string) bool {
 
 	// Check deny globs
 	for _, denyGlob := range p.Security.DenyGlobs {
-		if matched, _ := filepath.Match(denyGlob, path); matched {
+		if MatchGlob(denyGlob, path) {
 			return false
 		}
 	}
@@ -181,13 +257,41 @@ func (p Policy) IsPathAllowed(path string) bool {
 	return true
 }
 
-// IsCommandAllowed checks if a command is in the allowlist
+// MatchGlob reports whether a slash-separated path matches a glob in which
+// "**" stands for any number of directories, e.g. "**/.git/**"
+func MatchGlob(pattern, path string) bool {
+	return matchSegments(strings.Split(strings.Trim(pattern, "/"), "/"), strings.Split(strings.Trim(filepath.ToSlash(path), "/"), "/"))
+}
+
+func matchSegments(pattern, path []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(path); i++ {
+				if matchSegments(pattern[1:], path[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(path) == 0 {
+			return false
+		}
+		if ok, _ := filepath.Match(pattern[0], path[0]); !ok {
+			return false
+		}
+		pattern, path = pattern[1:], path[1:]
+	}
+	return len(path) == 0
+}
+
+// IsCommandAllowed checks if a command is in the allowlist. An allowlist
+// element of "*" matches any single argument that is not a flag.
 func (p Policy) IsCommandAllowed(cmd string, args []string) bool {
 	for _, allowed := range p.Allowlist.Commands {
 		if len(allowed) == len(args)+1 && allowed[0] == cmd {
 			matches := true
 			for i := 1; i < len(allowed); i++ {
-				if allowed[i] != args[i-1] {
+				if allowed[i] == "*" && strings.HasPrefix(args[i-1], "-") || allowed[i] != "*" && allowed[i] != args[i-1] {
 					matches = false
 					break
 				}
@@ -205,3 +309,21 @@ func (p Policy) JSON() []byte {
 	data, _ := json.Marshal(p)
 	return data
 }
+
+// AnalyzerConfig enables a security analyzer and carries its options
+type AnalyzerConfig struct {
+	Enabled bool                   `yaml:"enabled" json:"enabled"`
+	Options map[string]interface{} `yaml:"options,omitempty" json:"options,omitempty"`
+}
+
+// Decode unmarshals the analyzer options into v
+func (c AnalyzerConfig) Decode(v interface{}) error {
+	if len(c.Options) == 0 {
+		return nil
+	}
+	data, err := yaml.Marshal(c.Options)
+	if err != nil {
+		return err
+	}
+	return yaml.Unmarshal(data, v)
+}
@@ -42,6 +42,51 @@ func TestPolicyValidation(t *testing.T) {
 	}
 }
 
+func TestGateValidation(t *testing.T) {
+	valid := []Gate{
+		{Kind: GateSecurity, MinSeverity: "high", MinConfidence: "medium", ExcludeTests: true},
+		{Kind: GateDeadCode, ExportedOnly: true, Max: 5, Action: GateWarn},
+	}
+	for _, g := range valid {
+		if err := g.Validate(); err != nil {
+			t.Errorf("Expected %+v to be valid: %v", g, err)
+		}
+	}
+
+	invalid := []Gate{
+		{Kind: "lint"},
+		{Kind: GateSecurity, Action: "block"},
+		{Kind: GateSecurity, Severities: []string{"error"}},
+		{Kind: GateSecurity, MinConfidence: "certain"},
+		{Kind: GateDeadCode, Max: -1},
+	}
+	for _, g := range invalid {
+		if err := g.Validate(); err == nil {
+			t.Errorf("Expected %+v to be invalid", g)
+		}
+	}
+
+	policy := DefaultPolicy()
+	policy.Gates = invalid[:1]
+	if err := policy.Validate(); err == nil {
+		t.Error("Expected policy with an invalid gate to fail validation")
+	}
+}
+
+func TestDeadCodeModeValidation(t *testing.T) {
+	policy := DefaultPolicy()
+	for _, mode := range []string{"", DeadCodeReferences, DeadCodeReachability} {
+		policy.DeadCode.Mode = mode
+		if err := policy.Validate(); err != nil {
+			t.Errorf("Expected mode %q to be valid: %v", mode, err)
+		}
+	}
+	policy.DeadCode.Mode = "rta"
+	if err := policy.Validate(); err == nil {
+		t.Error("Expected an unknown dead-code mode to fail validation")
+	}
+}
+
 func TestIsPathAllowed(t *testing.T) {
 	policy := DefaultPolicy()
 
@@ -60,6 +105,27 @@ func TestIsPathAllowed(t *testing.T) {
 	}
 }
 
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern, path string
+		want          bool
+	}{
+		{"**/.git/**", ".git/config", true},
+		{"**/.git/**", "vendor/lib/.git/HEAD", true},
+		{"**/.git/**", "src/git/main.go", false},
+		{"**/testdata/**", "internal/security/testdata/aws.txt", true},
+		{"*.pem", "server.pem", true},
+		{"*.pem", "certs/server.pem", false},
+		{"**/*.pem", "certs/server.pem", true},
+		{"docs/*.md", "docs/guide/intro.md", false},
+	}
+	for _, tt := range tests {
+		if got := MatchGlob(tt.pattern, tt.path); got != tt.want {
+			t.Errorf("MatchGlob(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
+
 func TestIsCommandAllowed(t *testing.T) {
 	policy := DefaultPolicy()
 
@@ -77,4 +143,12 @@ func TestIsCommandAllowed(t *testing.T) {
 	if policy.IsCommandAllowed("go", []string{"build", "--unsafe"}) {
 		t.Error("go build --unsafe should not be allowed")
 	}
+
+	// Test wildcards, which never match flags
+	if !policy.IsCommandAllowed("cargo", []string{"test", "login"}) {
+		t.Error("cargo test login should be allowed")
+	}
+	if policy.IsCommandAllowed("cargo", []string{"test", "--no-run"}) {
+		t.Error("cargo test --no-run should not be allowed")
+	}
 }
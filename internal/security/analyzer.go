@@ -0,0 +1,177 @@
+package security
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/Siddhant-K-code/sentinel-ai/internal/goanalysis"
+	"github.com/Siddhant-K-code/sentinel-ai/internal/policy"
+	"github.com/Siddhant-K-code/sentinel-ai/internal/tools"
+)
+
+// Analyzer is a source of security findings. Implementations are created
+// by a Factory registered under their name.
+type Analyzer interface {
+	// Name identifies the analyzer in results, SARIF and policy
+	Name() string
+	// Languages lists the languages the analyzer understands; nil means any
+	Languages() []string
+	// Available returns an error explaining why the analyzer cannot run
+	Available() error
+	// Run scans the workspace. Failures are reported in ScanResult.Error.
+	Run(ctx context.Context) *ScanResult
+}
+
+// Env is what an analyzer needs from its host
+type Env struct {
+	Runner    *tools.Runner
+	Workspace string
+	Policy    policy.Policy
+	// Programs shares loaded Go programs between the analyzers of a scan;
+	// without it each analyzer loads its own
+	Programs *goanalysis.Loader
+}
+
+// LoadGo loads the workspace's Go program, sharing it through Programs
+// when set
+func (e Env) LoadGo(ctx context.Context, opts goanalysis.Options) (*goanalysis.Program, error) {
+	if e.Programs != nil {
+		return e.Programs.Load(ctx, opts)
+	}
+	return goanalysis.Load(ctx, e.Runner, opts)
+}
+
+// Factory creates an analyzer from its policy configuration
+type Factory func(env Env, cfg policy.AnalyzerConfig) (Analyzer, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Factory)
+	defaults   = make(map[string]bool)
+)
+
+// Register makes an analyzer available under name. Default analyzers run
+// when the policy does not list any analyzers. Register panics on
+// duplicate names, as it is meant to be called from init.
+func Register(name string, factory Factory, isDefault bool) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, dup := registry[name]; dup {
+		panic(fmt.Sprintf("security: analyzer %q registered twice", name))
+	}
+	registry[name] = factory
+	if isDefault {
+		defaults[name] = true
+	}
+}
+
+// Registered returns the sorted names of all registered analyzers
+func Registered() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Configure replaces the scanner's analyzers with the ones enabled in
+// configs. An empty configs map enables the default analyzers.
+func (s *Scanner) Configure(pol policy.Policy, configs map[string]policy.AnalyzerConfig) error {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	if err := validateSeverityOverrides(pol.Security.SeverityOverrides); err != nil {
+		return err
+	}
+
+	if len(configs) == 0 {
+		configs = make(map[string]policy.AnalyzerConfig)
+		for name := range defaults {
+			configs[name] = policy.AnalyzerConfig{Enabled: true}
+		}
+	}
+
+	names := make([]string, 0, len(configs))
+	for name := range configs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	env := Env{Runner: s.runner, Workspace: s.workspace, Policy: pol, Programs: s.programs}
+	var analyzers []Analyzer
+	for _, name := range names {
+		cfg := configs[name]
+		if !cfg.Enabled {
+			continue
+		}
+
+		factory, ok := registry[name]
+		if !ok {
+			return fmt.Errorf("unknown analyzer %q", name)
+		}
+
+		analyzer, err := factory(env, cfg)
+		if err != nil {
+			return fmt.Errorf("configure analyzer %s: %w", name, err)
+		}
+		analyzers = append(analyzers, analyzer)
+	}
+
+	s.analyzers = analyzers
+	s.severities = pol.Security.SeverityOverrides
+	return nil
+}
+
+// SharePrograms makes the analyzers configured afterwards load Go
+// programs through l
+func (s *Scanner) SharePrograms(l *goanalysis.Loader) {
+	s.programs = l
+}
+
+// AddAnalyzer adds an analyzer that is not in the registry, e.g. a fake
+// in tests
+func (s *Scanner) AddAnalyzer(a Analyzer) {
+	s.analyzers = append(s.analyzers, a)
+}
+
+// Analyzers returns the analyzers the scanner will run
+func (s *Scanner) Analyzers() []Analyzer {
+	return s.analyzers
+}
+
+// runAnalyzer checks availability and language support before running
+func (s *Scanner) runAnalyzer(ctx context.Context, a Analyzer, langs map[string]bool) *ScanResult {
+	if err := a.Available(); err != nil {
+		return &ScanResult{
+			Tool:     a.Name(),
+			Findings: []Finding{},
+			Error:    err.Error(),
+		}
+	}
+
+	if supported := a.Languages(); supported != nil {
+		match := false
+		for _, lang := range supported {
+			if langs[lang] {
+				match = true
+				break
+			}
+		}
+		if !match {
+			return &ScanResult{
+				Tool:     a.Name(),
+				Findings: []Finding{},
+				Error:    "no supported languages in workspace",
+			}
+		}
+	}
+
+	return a.Run(ctx)
+}
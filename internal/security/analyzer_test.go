@@ -0,0 +1,104 @@
+package security
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Siddhant-K-code/sentinel-ai/internal/parallel"
+	"github.com/Siddhant-K-code/sentinel-ai/internal/policy"
+	"github.com/Siddhant-K-code/sentinel-ai/internal/tools"
+)
+
+// scanAll runs every analyzer of s the way the engine does
+func scanAll(ctx context.Context, s *Scanner) []ScanResult {
+	return s.Collect(parallel.Run(ctx, 0, s.Tasks()))
+}
+
+// fakeAnalyzer returns canned findings
+type fakeAnalyzer struct {
+	name      string
+	languages []string
+	available error
+	findings  []Finding
+}
+
+func (f *fakeAnalyzer) Name() string        { return f.name }
+func (f *fakeAnalyzer) Languages() []string { return f.languages }
+func (f *fakeAnalyzer) Available() error    { return f.available }
+
+func (f *fakeAnalyzer) Run(ctx context.Context) *ScanResult {
+	return &ScanResult{Tool: f.name, Findings: f.findings}
+}
+
+func TestConfigure(t *testing.T) {
+	runner := tools.NewRunner(t.TempDir(), nil, time.Second)
+	scanner := NewScanner(runner, ".")
+
+	names := func() map[string]bool {
+		m := make(map[string]bool)
+		for _, a := range scanner.Analyzers() {
+			m[a.Name()] = true
+		}
+		return m
+	}
+
+	// Defaults
+	if got := names(); !got["semgrep"] || !got["codeql"] {
+		t.Errorf("Expected default analyzers, got %v", got)
+	}
+
+	// Policy selection
+	err := scanner.Configure(policy.DefaultPolicy(), map[string]policy.AnalyzerConfig{
+		"semgrep": {Enabled: true},
+		"codeql":  {Enabled: false},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := names(); !got["semgrep"] || got["codeql"] {
+		t.Errorf("Expected only semgrep, got %v", got)
+	}
+
+	// Unknown analyzer
+	err = scanner.Configure(policy.DefaultPolicy(), map[string]policy.AnalyzerConfig{
+		"nope": {Enabled: true},
+	})
+	if err == nil {
+		t.Error("Expected unknown analyzer to be rejected")
+	}
+}
+
+func TestScanWithFakeAnalyzers(t *testing.T) {
+	workspace := t.TempDir()
+	runner := tools.NewRunner(workspace, nil, time.Second)
+	scanner := &Scanner{runner: runner, workspace: workspace}
+
+	scanner.AddAnalyzer(&fakeAnalyzer{
+		name:     "fake",
+		findings: []Finding{{RuleID: "fake/rule", File: "main.go", Line: 1}},
+	})
+	scanner.AddAnalyzer(&fakeAnalyzer{
+		name:      "missing",
+		available: errors.New("missing not found in PATH"),
+	})
+	scanner.AddAnalyzer(&fakeAnalyzer{
+		name:      "rust-only",
+		languages: []string{"rust"},
+	})
+
+	results := scanAll(context.Background(), scanner)
+	if len(results) != 3 {
+		t.Fatalf("Expected 3 results, got %d", len(results))
+	}
+	if len(results[0].Findings) != 1 || results[0].Error != "" {
+		t.Errorf("Expected fake finding, got %+v", results[0])
+	}
+	if results[1].Error != "missing not found in PATH" {
+		t.Errorf("Expected availability error, got %+v", results[1])
+	}
+	if results[2].Error == "" {
+		t.Errorf("Expected language mismatch error, got %+v", results[2])
+	}
+}
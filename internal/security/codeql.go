@@ -0,0 +1,275 @@
+package security
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Siddhant-K-code/sentinel-ai/internal/cache"
+	"github.com/Siddhant-K-code/sentinel-ai/internal/policy"
+	"github.com/Siddhant-K-code/sentinel-ai/internal/tools"
+)
+
+// DefaultCodeQLSuite is the query suite used when none is configured.
+// "{lang}" is replaced with the CodeQL language name.
+const DefaultCodeQLSuite = "codeql/{lang}-queries:codeql-suites/{lang}-security-extended.qls"
+
+// sourceHashFile records the source hash a database was built from
+const sourceHashFile = ".sentinel-source-hash"
+
+// errNoCodeQLLanguages is reported when nothing in the workspace can be analyzed
+var errNoCodeQLLanguages = errors.New("no CodeQL-supported languages found")
+
+// CodeQLOptions configures the CodeQL analyzer
+type CodeQLOptions struct {
+	// QuerySuite is passed to "codeql database analyze"; "{lang}" is
+	// replaced with the language being analyzed
+	QuerySuite string `yaml:"query_suite"`
+	// DatabaseDir holds one database per language. Defaults to a
+	// directory under the user cache keyed by the workspace path.
+	DatabaseDir string `yaml:"database_dir"`
+}
+
+// codeqlAnalyzer builds CodeQL databases and runs a query suite over them
+type codeqlAnalyzer struct {
+	env  Env
+	opts CodeQLOptions
+}
+
+func init() {
+	Register("codeql", newCodeQLAnalyzer, true)
+}
+
+// newCodeQLAnalyzer creates the CodeQL analyzer from its policy options
+func newCodeQLAnalyzer(env Env, cfg policy.AnalyzerConfig) (Analyzer, error) {
+	var opts CodeQLOptions
+	if err := cfg.Decode(&opts); err != nil {
+		return nil, err
+	}
+	return &codeqlAnalyzer{env: env, opts: opts}, nil
+}
+
+// Name returns the analyzer name
+func (a *codeqlAnalyzer) Name() string {
+	return "codeql"
+}
+
+// Languages returns the languages CodeQL can extract
+func (a *codeqlAnalyzer) Languages() []string {
+	seen := make(map[string]bool)
+	var langs []string
+	for _, lang := range codeqlLanguages {
+		if !seen[lang] {
+			seen[lang] = true
+			langs = append(langs, lang)
+		}
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// Available checks that codeql is installed
+func (a *codeqlAnalyzer) Available() error {
+	if _, err := exec.LookPath("codeql"); err != nil {
+		return errors.New("codeql not found in PATH")
+	}
+	return nil
+}
+
+// Run runs CodeQL security scanning
+func (a *codeqlAnalyzer) Run(ctx context.Context) *ScanResult {
+	start := time.Now()
+
+	root, err := a.env.Runner.WorkDir("")
+	if err != nil {
+		return &ScanResult{
+			Tool:     "codeql",
+			Findings: []Finding{},
+			Duration: time.Since(start),
+			Error:    err.Error(),
+		}
+	}
+
+	langs, err := detectLanguages(root)
+	if err == nil && len(langs) == 0 {
+		err = errNoCodeQLLanguages
+	}
+	if err != nil {
+		return &ScanResult{
+			Tool:     "codeql",
+			Findings: []Finding{},
+			Duration: time.Since(start),
+			Error:    err.Error(),
+		}
+	}
+
+	dbDir, err := a.databaseDir(root)
+	if err == nil {
+		err = os.MkdirAll(dbDir, 0755)
+	}
+	if err != nil {
+		return &ScanResult{
+			Tool:     "codeql",
+			Findings: []Finding{},
+			Duration: time.Since(start),
+			Error:    fmt.Sprintf("failed to prepare CodeQL database directory: %v", err),
+		}
+	}
+
+	// Analyze each language; one failing language does not discard the others
+	findings := []Finding{}
+	var errs []string
+	for _, lang := range langs {
+		langFindings, err := a.analyze(ctx, root, dbDir, lang)
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		findings = append(findings, langFindings...)
+	}
+
+	return &ScanResult{
+		Tool:     "codeql",
+		Findings: findings,
+		Duration: time.Since(start),
+		Error:    strings.Join(errs, "; "),
+	}
+}
+
+// codeqlLanguages maps file extensions to CodeQL language names
+var codeqlLanguages = map[string]string{
+	".go":    "go",
+	".js":    "javascript",
+	".jsx":   "javascript",
+	".ts":    "javascript",
+	".tsx":   "javascript",
+	".py":    "python",
+	".java":  "java",
+	".kt":    "java",
+	".c":     "cpp",
+	".cc":    "cpp",
+	".cpp":   "cpp",
+	".h":     "cpp",
+	".hpp":   "cpp",
+	".cs":    "csharp",
+	".rb":    "ruby",
+	".swift": "swift",
+}
+
+// codeqlSkipDirs are not considered when detecting languages
+var codeqlSkipDirs = map[string]bool{
+	".git":         true,
+	".sentinel":    true,
+	"node_modules": true,
+	"vendor":       true,
+	"target":       true,
+}
+
+// detectLanguages returns the sorted languages present in root, named as
+// CodeQL names them
+func detectLanguages(root string) ([]string, error) {
+	seen := make(map[string]bool)
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != root && codeqlSkipDirs[d.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if lang, ok := codeqlLanguages[filepath.Ext(d.Name())]; ok {
+			seen[lang] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var langs []string
+	for lang := range seen {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs, nil
+}
+
+// codeqlSourcePatterns returns the file patterns that feed a language's database
+func codeqlSourcePatterns(lang string) []string {
+	var patterns []string
+	for ext, l := range codeqlLanguages {
+		if l == lang {
+			patterns = append(patterns, "*"+ext)
+		}
+	}
+	sort.Strings(patterns)
+	return patterns
+}
+
+// databaseDir resolves the directory holding the per-language databases
+func (a *codeqlAnalyzer) databaseDir(root string) (string, error) {
+	if a.opts.DatabaseDir != "" {
+		return a.opts.DatabaseDir, nil
+	}
+	base, err := cache.DefaultDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, "codeql", cache.Key(root)[:16]), nil
+}
+
+// analyze creates (or reuses) a database for lang and runs the
+// configured query suite over it, returning the parsed findings
+func (a *codeqlAnalyzer) analyze(ctx context.Context, root, dbDir, lang string) ([]Finding, error) {
+	db := filepath.Join(dbDir, lang)
+
+	// Reuse the database when the sources it was built from are unchanged
+	hash, err := tools.HashFiles(root, codeqlSourcePatterns(lang))
+	if err != nil {
+		return nil, fmt.Errorf("hash %s sources: %w", lang, err)
+	}
+	hashPath := filepath.Join(db, sourceHashFile)
+	if recorded, err := os.ReadFile(hashPath); err != nil || string(recorded) != hash {
+		result := a.env.Runner.Run(ctx, "codeql", "database", "create", db, "--language", lang, "--source-root", ".", "--overwrite")
+		if result.Error != nil {
+			return nil, fmt.Errorf("codeql database create (%s): %v: %s", lang, result.Error, tail(result.Stderr))
+		}
+		if err := os.WriteFile(hashPath, []byte(hash), 0644); err != nil {
+			return nil, fmt.Errorf("record %s source hash: %w", lang, err)
+		}
+	}
+
+	suite := a.opts.QuerySuite
+	if suite == "" {
+		suite = DefaultCodeQLSuite
+	}
+	suite = strings.ReplaceAll(suite, "{lang}", lang)
+
+	sarifPath := filepath.Join(dbDir, lang+".sarif")
+	result := a.env.Runner.Run(ctx, "codeql", "database", "analyze", db, suite, "--format", "sarif-latest", "--output", sarifPath)
+	if result.Error != nil {
+		return nil, fmt.Errorf("codeql database analyze (%s): %v: %s", lang, result.Error, tail(result.Stderr))
+	}
+
+	data, err := os.ReadFile(sarifPath)
+	if err != nil {
+		return nil, fmt.Errorf("read %s SARIF: %w", lang, err)
+	}
+
+	return parseSARIF(data, root)
+}
+
+// tail returns the last line of tool diagnostics for error messages
+func tail(output []byte) string {
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	return lines[len(lines)-1]
+}
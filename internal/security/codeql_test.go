@@ -0,0 +1,124 @@
+package security
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Siddhant-K-code/sentinel-ai/internal/policy"
+	"github.com/Siddhant-K-code/sentinel-ai/internal/tools"
+)
+
+// fakeCodeQL installs a codeql script on PATH that creates empty
+// databases, counts creations and emits the canned SARIF file
+func fakeCodeQL(t *testing.T) (countFile string) {
+	t.Helper()
+
+	sarif, err := filepath.Abs("testdata/codeql.sarif")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bin := t.TempDir()
+	countFile = filepath.Join(bin, "creates")
+	script := `#!/bin/sh
+case "$2" in
+create)
+	mkdir -p "$3"
+	echo create >> "` + countFile + `"
+	;;
+analyze)
+	while [ $# -gt 0 ]; do
+		if [ "$1" = "--output" ]; then cp "` + sarif + `" "$2"; fi
+		shift
+	done
+	;;
+esac
+`
+	if err := os.WriteFile(filepath.Join(bin, "codeql"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	return countFile
+}
+
+func creates(t *testing.T, countFile string) int {
+	data, _ := os.ReadFile(countFile)
+	return strings.Count(string(data), "create")
+}
+
+func TestRunCodeQL(t *testing.T) {
+	countFile := fakeCodeQL(t)
+
+	workspace := t.TempDir()
+	mainGo := filepath.Join(workspace, "main.go")
+	if err := os.WriteFile(mainGo, []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	runner := tools.NewRunner(workspace, [][]string{
+		{"codeql", "database", "create", "*", "--language", "*", "--source-root", ".", "--overwrite"},
+		{"codeql", "database", "analyze", "*", "*", "--format", "sarif-latest", "--output", "*"},
+	}, 10*time.Second)
+	analyzer, err := newCodeQLAnalyzer(Env{Runner: runner, Workspace: workspace}, policy.AnalyzerConfig{
+		Enabled: true,
+		Options: map[string]interface{}{"database_dir": t.TempDir()},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := analyzer.Run(context.Background())
+	if result.Error != "" {
+		t.Fatalf("Expected CodeQL to succeed: %s", result.Error)
+	}
+	if len(result.Findings) != 1 {
+		t.Fatalf("Expected 1 finding, got %d", len(result.Findings))
+	}
+
+	finding := result.Findings[0]
+	if finding.RuleID != "go/reflected-xss" || finding.File != "main.go" || finding.Line != 21 {
+		t.Errorf("Unexpected finding: %+v", finding)
+	}
+	if finding.Severity != "error" || finding.Confidence != "high" {
+		t.Errorf("Expected rule metadata, got severity %q confidence %q", finding.Severity, finding.Confidence)
+	}
+	if len(finding.CodeFlows) != 1 || len(finding.CodeFlows[0].Steps) != 2 {
+		t.Fatalf("Expected one code flow with 2 steps, got %+v", finding.CodeFlows)
+	}
+	if finding.CodeFlows[0].Steps[0].Message != "call to Query" {
+		t.Errorf("Expected source step message, got %+v", finding.CodeFlows[0].Steps[0])
+	}
+
+	// Unchanged sources reuse the database
+	analyzer.Run(context.Background())
+	if n := creates(t, countFile); n != 1 {
+		t.Errorf("Expected database to be reused, created %d times", n)
+	}
+
+	// Changed sources rebuild it
+	if err := os.WriteFile(mainGo, []byte("package main\n\nfunc main() {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	analyzer.Run(context.Background())
+	if n := creates(t, countFile); n != 2 {
+		t.Errorf("Expected database rebuild after source change, created %d times", n)
+	}
+}
+
+func TestRunCodeQLNoLanguages(t *testing.T) {
+	fakeCodeQL(t)
+
+	workspace := t.TempDir()
+	runner := tools.NewRunner(workspace, nil, 10*time.Second)
+	analyzer := &codeqlAnalyzer{env: Env{Runner: runner, Workspace: workspace}}
+
+	result := analyzer.Run(context.Background())
+	if result.Error != errNoCodeQLLanguages.Error() {
+		t.Errorf("Expected no-languages error, got %q", result.Error)
+	}
+}
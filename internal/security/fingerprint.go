@@ -0,0 +1,320 @@
+package security
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// FingerprintKey is the partialFingerprints key under which the stable
+// sentinel-ai fingerprint is published in SARIF
+const FingerprintKey = "sentinelFingerprint/v1"
+
+// cwePattern matches CWE references such as "CWE-79", "cwe-079: ..." or
+// CodeQL's "external/cwe/cwe-079"
+var cwePattern = regexp.MustCompile(`(?i)\bcwe[-/]0*(\d+)`)
+
+// funcPattern matches function headers in common non-Go languages
+var funcPattern = regexp.MustCompile(`^\s*(?:export\s+)?(?:async\s+)?(?:pub\s+)?(?:def|function|fn|func)\s+([A-Za-z_$][\w$]*)`)
+
+// whitespace collapses runs of whitespace when normalizing snippets
+var whitespace = regexp.MustCompile(`\s+`)
+
+// CWEs returns the sorted, normalized CWE identifiers attached to a finding
+func (f Finding) CWEs() []string {
+	cwes := f.taggedCWEs()
+	sort.Strings(cwes)
+	return cwes
+}
+
+// PrimaryCWE returns the first CWE the tool lists for a finding, or "" when
+// it has none. Tools list the weakness itself before related ones, e.g.
+// CodeQL tags reflected XSS with CWE-79 and then CWE-116.
+func (f Finding) PrimaryCWE() string {
+	if cwes := f.taggedCWEs(); len(cwes) > 0 {
+		return cwes[0]
+	}
+	return ""
+}
+
+// taggedCWEs returns the normalized CWE identifiers of a finding in the
+// order its rule tags, classification and rule ID list them
+func (f Finding) taggedCWEs() []string {
+	seen := make(map[string]bool)
+	var sources []string
+	if f.Rule != nil {
+		sources = append(sources, f.Rule.Tags...)
+	}
+	if f.Classification != nil {
+		sources = append(sources, f.Classification.CWE...)
+	}
+	sources = append(sources, f.RuleID)
+
+	var cwes []string
+	for _, src := range sources {
+		for _, m := range cwePattern.FindAllStringSubmatch(src, -1) {
+			id := "CWE-" + m[1]
+			if !seen[id] {
+				seen[id] = true
+				cwes = append(cwes, id)
+			}
+		}
+	}
+	return cwes
+}
+
+// fingerprinter computes stable finding fingerprints, caching parsed files
+type fingerprinter struct {
+	root  string
+	lines map[string][]string
+	funcs map[string][]goFunc
+}
+
+// goFunc is the line span of a Go function declaration
+type goFunc struct {
+	name       string
+	start, end int
+}
+
+func newFingerprinter(root string) *fingerprinter {
+	return &fingerprinter{
+		root:  root,
+		lines: make(map[string][]string),
+		funcs: make(map[string][]goFunc),
+	}
+}
+
+// Fingerprint identifies a finding independently of its line number and
+// of the tool that reported it. It hashes the rule category (its primary
+// CWE, or the rule ID when it has none), the normalized path, the
+// enclosing function and the whitespace-normalized source line. Tools
+// disagree on secondary CWEs, so only the primary one is used.
+func (fp *fingerprinter) Fingerprint(f Finding) string {
+	category := f.PrimaryCWE()
+	if category == "" {
+		category = f.RuleID
+	}
+
+	file := normalizePath(f.File)
+	parts := []string{category, file}
+
+	// A secret is identified by its value, which also matches copies of
+	// it found in git history, and a vulnerable dependency by its advisory
+	if secret := f.Fingerprints[secretHashKey]; secret != "" {
+		parts = append(parts, "", secret)
+	} else if advisory := f.Fingerprints[advisoryKey]; advisory != "" {
+		parts = append(parts, "", advisory)
+	} else {
+		snippet := sha256.Sum256([]byte(fp.snippet(file, f.Line)))
+		parts = append(parts, fp.enclosingFunc(file, f.Line), hex.EncodeToString(snippet[:]))
+	}
+
+	h := sha256.New()
+	for _, part := range parts {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))[:32]
+}
+
+// normalizePath returns a clean, slash-separated relative path
+func normalizePath(file string) string {
+	p := path.Clean(filepath.ToSlash(file))
+	return strings.TrimPrefix(p, "./")
+}
+
+// snippet returns the whitespace-normalized source line, or "" if the
+// file cannot be read
+func (fp *fingerprinter) snippet(file string, line int) string {
+	lines := fp.readLines(file)
+	if line < 1 || line > len(lines) {
+		return ""
+	}
+	return strings.TrimSpace(whitespace.ReplaceAllString(lines[line-1], " "))
+}
+
+// readLines loads and caches the lines of a workspace file
+func (fp *fingerprinter) readLines(file string) []string {
+	if lines, ok := fp.lines[file]; ok {
+		return lines
+	}
+	data, err := os.ReadFile(filepath.Join(fp.root, filepath.FromSlash(file)))
+	var lines []string
+	if err == nil {
+		lines = strings.Split(string(data), "\n")
+	}
+	fp.lines[file] = lines
+	return lines
+}
+
+// enclosingFunc names the function containing line. Go files are parsed;
+// other languages fall back to the nearest preceding function header.
+func (fp *fingerprinter) enclosingFunc(file string, line int) string {
+	if strings.HasSuffix(file, ".go") {
+		for _, fn := range fp.goFuncs(file) {
+			if line >= fn.start && line <= fn.end {
+				return fn.name
+			}
+		}
+		return ""
+	}
+
+	lines := fp.readLines(file)
+	for i := line - 1; i >= 0 && i < len(lines); i-- {
+		if m := funcPattern.FindStringSubmatch(lines[i]); m != nil {
+			return m[1]
+		}
+	}
+	return ""
+}
+
+// goFuncs parses a Go file and caches its function spans
+func (fp *fingerprinter) goFuncs(file string) []goFunc {
+	if funcs, ok := fp.funcs[file]; ok {
+		return funcs
+	}
+
+	var funcs []goFunc
+	fset := token.NewFileSet()
+	node, err := parser.ParseFile(fset, filepath.Join(fp.root, filepath.FromSlash(file)), nil, parser.SkipObjectResolution)
+	if err == nil {
+		for _, decl := range node.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok {
+				continue
+			}
+			name := fn.Name.Name
+			if fn.Recv != nil && len(fn.Recv.List) > 0 {
+				name = receiverName(fn.Recv.List[0].Type) + "." + name
+			}
+			funcs = append(funcs, goFunc{
+				name:  name,
+				start: fset.Position(fn.Pos()).Line,
+				end:   fset.Position(fn.End()).Line,
+			})
+		}
+	}
+
+	fp.funcs[file] = funcs
+	return funcs
+}
+
+// receiverName returns the base type name of a method receiver
+func receiverName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return receiverName(t.X)
+	case *ast.IndexExpr:
+		return receiverName(t.X)
+	case *ast.IndexListExpr:
+		return receiverName(t.X)
+	case *ast.Ident:
+		return t.Name
+	}
+	return ""
+}
+
+// severityRank orders severities from tools and the canonical scale so
+// that merged findings keep the most severe value
+func severityRank(severity string) int {
+	switch strings.ToLower(severity) {
+	case "critical":
+		return 5
+	case "high", "error":
+		return 4
+	case "medium", "warning":
+		return 3
+	case "low", "note", "recommendation":
+		return 2
+	case "info", "none":
+		return 1
+	}
+	return 0
+}
+
+// fingerprintAndMerge assigns fingerprints to every finding and merges
+// findings that share one. The merged finding stays in the first result
+// that reported it and lists every detecting tool; later duplicates are
+// removed from their results.
+func fingerprintAndMerge(results []ScanResult, root string) {
+	fp := newFingerprinter(root)
+
+	type position struct{ result, finding int }
+	seen := make(map[string]position)
+
+	for ri := range results {
+		kept := results[ri].Findings[:0]
+		for _, finding := range results[ri].Findings {
+			finding.Fingerprint = fp.Fingerprint(finding)
+			if finding.Tool == "" {
+				finding.Tool = results[ri].Tool
+			}
+			if len(finding.Tools) == 0 {
+				finding.Tools = []string{finding.Tool}
+			}
+
+			if pos, dup := seen[finding.Fingerprint]; dup {
+				var target *Finding
+				if pos.result == ri {
+					target = &kept[pos.finding]
+				} else {
+					target = &results[pos.result].Findings[pos.finding]
+				}
+				mergeFinding(target, finding)
+				continue
+			}
+
+			seen[finding.Fingerprint] = position{ri, len(kept)}
+			kept = append(kept, finding)
+		}
+		results[ri].Findings = kept
+	}
+}
+
+// mergeFinding folds a duplicate into the finding that is kept
+func mergeFinding(dst *Finding, dup Finding) {
+	for _, tool := range dup.Tools {
+		found := false
+		for _, t := range dst.Tools {
+			if t == tool {
+				found = true
+				break
+			}
+		}
+		if !found {
+			dst.Tools = append(dst.Tools, tool)
+		}
+	}
+
+	if severityRank(dup.Severity) > severityRank(dst.Severity) {
+		dst.Severity = dup.Severity
+	}
+	if len(dst.CodeFlows) == 0 {
+		dst.CodeFlows = dup.CodeFlows
+	}
+	if dst.Description == "" {
+		dst.Description = dup.Description
+	}
+	if dst.Rule == nil {
+		dst.Rule = dup.Rule
+	}
+	if dst.Commit == nil {
+		dst.Commit = dup.Commit
+	}
+	for k, v := range dup.Fingerprints {
+		if dst.Fingerprints == nil {
+			dst.Fingerprints = make(map[string]string)
+		}
+		if _, ok := dst.Fingerprints[k]; !ok {
+			dst.Fingerprints[k] = v
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package security
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Siddhant-K-code/sentinel-ai/internal/tools"
+)
+
+const handlerSrc = `package main
+
+import "net/http"
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("name")
+	w.Write([]byte("<p>" + name + "</p>"))
+}
+`
+
+func TestFingerprintStableAcrossLineShifts(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "main.go")
+	if err := os.WriteFile(path, []byte(handlerSrc), 0644); err != nil {
+		t.Fatal(err)
+	}
+	finding := Finding{RuleID: "go/reflected-xss", File: "main.go", Line: 7,
+		Rule: &RuleMetadata{Tags: []string{"external/cwe/cwe-079"}}}
+	before := newFingerprinter(dir).Fingerprint(finding)
+
+	// Adding code above the finding moves it without changing it
+	shifted := strings.Replace(handlerSrc, "import", "// Package main serves pages\n\nimport", 1)
+	if err := os.WriteFile(path, []byte(shifted), 0644); err != nil {
+		t.Fatal(err)
+	}
+	finding.Line = 9
+	finding.File = "./main.go"
+	if after := newFingerprinter(dir).Fingerprint(finding); after != before {
+		t.Errorf("Fingerprint changed after line shift: %s != %s", after, before)
+	}
+
+	// Changing the flagged line does change it
+	finding.Line = 8
+	if other := newFingerprinter(dir).Fingerprint(finding); other == before {
+		t.Error("Expected a different fingerprint for a different line")
+	}
+}
+
+func TestCWEs(t *testing.T) {
+	finding := Finding{
+		RuleID: "python.flask.xss",
+		Rule: &RuleMetadata{Tags: []string{
+			"CWE-79: Improper Neutralization of Input",
+			"external/cwe/cwe-079",
+			"cwe-116",
+			"A03:2021 - Injection",
+		}},
+	}
+	want := []string{"CWE-116", "CWE-79"}
+	if got := finding.CWEs(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	if got := finding.PrimaryCWE(); got != "CWE-79" {
+		t.Errorf("Expected primary CWE-79, got %s", got)
+	}
+}
+
+func TestScanMergesDuplicatesAcrossTools(t *testing.T) {
+	workspace := t.TempDir()
+	if err := os.WriteFile(filepath.Join(workspace, "main.go"), []byte(handlerSrc), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runner := tools.NewRunner(workspace, nil, time.Second)
+	scanner := &Scanner{runner: runner, workspace: workspace}
+
+	flow := []CodeFlow{{Steps: []FlowStep{{File: "main.go", Line: 6}, {File: "main.go", Line: 7}}}}
+	scanner.AddAnalyzer(&fakeAnalyzer{
+		name: "semgrep",
+		findings: []Finding{{
+			RuleID: "go.lang.security.xss", File: "main.go", Line: 7, Column: 2, Severity: "warning",
+			Rule: &RuleMetadata{Tags: []string{"CWE-79: Improper Neutralization of Input During Web Page Generation"}},
+		}},
+	})
+	scanner.AddAnalyzer(&fakeAnalyzer{
+		name: "codeql",
+		findings: []Finding{
+			{
+				RuleID: "go/reflected-xss", File: "main.go", Line: 7, Column: 10, Severity: "error", CodeFlows: flow,
+				Rule: &RuleMetadata{Tags: []string{"security", "external/cwe/cwe-079", "external/cwe/cwe-116"}},
+			},
+			{RuleID: "go/unused-name", File: "main.go", Line: 6},
+		},
+	})
+
+	results := scanAll(context.Background(), scanner)
+
+	if len(results[0].Findings) != 1 {
+		t.Fatalf("Expected 1 semgrep finding, got %d", len(results[0].Findings))
+	}
+	merged := results[0].Findings[0]
+	if !reflect.DeepEqual(merged.Tools, []string{"semgrep", "codeql"}) {
+		t.Errorf("Expected both tools, got %v", merged.Tools)
+	}
+	if merged.Severity != "high" || merged.ToolSeverity != "error" {
+		t.Errorf("Expected highest severity normalized to high, got %q from %q", merged.Severity, merged.ToolSeverity)
+	}
+	if len(merged.CodeFlows) != 1 {
+		t.Errorf("Expected code flow from codeql, got %v", merged.CodeFlows)
+	}
+	if merged.Fingerprint == "" {
+		t.Error("Expected fingerprint")
+	}
+
+	if len(results[1].Findings) != 1 || results[1].Findings[0].RuleID != "go/unused-name" {
+		t.Errorf("Expected only the unrelated codeql finding, got %+v", results[1].Findings)
+	}
+
+	// The fingerprint is published for code scanning deduplication
+	sarif, err := scanner.GenerateSARIF(results)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(sarif), FingerprintKey) || !strings.Contains(string(sarif), merged.Fingerprint) {
+		t.Error("Expected fingerprint in SARIF partialFingerprints")
+	}
+}
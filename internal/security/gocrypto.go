@@ -0,0 +1,563 @@
+package security
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/types/typeutil"
+
+	"github.com/Siddhant-K-code/sentinel-ai/internal/diff"
+	"github.com/Siddhant-K-code/sentinel-ai/internal/goanalysis"
+	"github.com/Siddhant-K-code/sentinel-ai/internal/policy"
+)
+
+func init() {
+	Register("gocrypto", newGoCryptoAnalyzer, true)
+}
+
+// defaultSecurityNames matches identifiers that mark code as security
+// sensitive, so checksums and cache keys are not reported
+const defaultSecurityNames = `(?i)passw|secret|token|nonce|salt|session|signature|credential|auth|api_?key|otp|csrf|private`
+
+// tlsVersion12 is tls.VersionTLS12
+const tlsVersion12 = 0x0303
+
+// cryptoRule describes one weak cryptography check
+type cryptoRule struct {
+	message  string
+	cwe      string
+	severity string
+}
+
+var cryptoRules = map[string]cryptoRule{
+	"go/weak-hash":                {"Weak hash function is used for a security purpose", "CWE-328", "warning"},
+	"go/insecure-random":          {"math/rand is not a cryptographically secure random source", "CWE-338", "error"},
+	"go/tls-insecure-skip-verify": {"TLS certificate verification is disabled", "CWE-295", "error"},
+	"go/tls-min-version":          {"TLS configuration allows versions older than TLS 1.2", "CWE-327", "warning"},
+	"go/hardcoded-key":            {"Cryptographic key is hard-coded", "CWE-321", "error"},
+	"go/hardcoded-iv":             {"Initialization vector or nonce is hard-coded", "CWE-329", "error"},
+	"go/weak-rsa-key":             {"RSA key is shorter than 2048 bits", "CWE-326", "error"},
+}
+
+// weakHashFuncs are reported in security-sensitive code
+var weakHashFuncs = map[string]bool{
+	"crypto/md5.New":  true,
+	"crypto/md5.Sum":  true,
+	"crypto/sha1.New": true,
+	"crypto/sha1.Sum": true,
+}
+
+// cryptoKeyArgs maps functions to the index of their key argument
+var cryptoKeyArgs = map[string]int{
+	"crypto/aes.NewCipher":                     0,
+	"crypto/des.NewCipher":                     0,
+	"crypto/des.NewTripleDESCipher":            0,
+	"crypto/rc4.NewCipher":                     0,
+	"crypto/hmac.New":                          1,
+	"golang.org/x/crypto/chacha20poly1305.New": 0,
+}
+
+// cryptoIVArgs maps functions to the index of their IV or nonce argument
+var cryptoIVArgs = map[string]int{
+	"crypto/cipher.NewCBCEncrypter": 1,
+	"crypto/cipher.NewCBCDecrypter": 1,
+	"crypto/cipher.NewCFBEncrypter": 1,
+	"crypto/cipher.NewCFBDecrypter": 1,
+	"crypto/cipher.NewCTR":          1,
+	"crypto/cipher.NewOFB":          1,
+	"(crypto/cipher.AEAD).Seal":     1,
+}
+
+// rsaBitsArgs maps RSA key generators to the index of their size argument
+var rsaBitsArgs = map[string]int{
+	"crypto/rsa.GenerateKey":           1,
+	"crypto/rsa.GenerateMultiPrimeKey": 2,
+}
+
+// GoCryptoOptions configures the weak cryptography analyzer
+type GoCryptoOptions struct {
+	// SecurityNames is a regex of identifiers that mark the enclosing
+	// function or statement as security sensitive
+	SecurityNames string `yaml:"security_names"`
+	// Tags are build tags used when loading packages
+	Tags []string `yaml:"tags"`
+}
+
+// goCryptoAnalyzer reports weak hashes, insecure randomness, insecure TLS
+// settings, hard-coded keys and IVs and short RSA keys in Go code
+type goCryptoAnalyzer struct {
+	env           Env
+	opts          GoCryptoOptions
+	securityNames *regexp.Regexp
+}
+
+// newGoCryptoAnalyzer creates the analyzer from its policy options
+func newGoCryptoAnalyzer(env Env, cfg policy.AnalyzerConfig) (Analyzer, error) {
+	var opts GoCryptoOptions
+	if err := cfg.Decode(&opts); err != nil {
+		return nil, err
+	}
+	if opts.SecurityNames == "" {
+		opts.SecurityNames = defaultSecurityNames
+	}
+	re, err := regexp.Compile(opts.SecurityNames)
+	if err != nil {
+		return nil, fmt.Errorf("security_names: %w", err)
+	}
+	return &goCryptoAnalyzer{env: env, opts: opts, securityNames: re}, nil
+}
+
+// Name returns the analyzer name
+func (a *goCryptoAnalyzer) Name() string {
+	return "gocrypto"
+}
+
+// Languages returns the languages the analyzer understands
+func (a *goCryptoAnalyzer) Languages() []string {
+	return []string{"go"}
+}
+
+// Available always succeeds; the analysis runs in process
+func (a *goCryptoAnalyzer) Available() error {
+	return nil
+}
+
+// Run type-checks the workspace and inspects every file
+func (a *goCryptoAnalyzer) Run(ctx context.Context) *ScanResult {
+	start := time.Now()
+	result := &ScanResult{Tool: "gocrypto", Findings: []Finding{}}
+
+	prog, err := a.env.LoadGo(ctx, goanalysis.Options{
+		Tags:    a.opts.Tags,
+		Network: a.env.Policy.Modes["default"].Network,
+	})
+	if err != nil {
+		result.Error = err.Error()
+		result.Duration = time.Since(start)
+		return result
+	}
+	if errs := prog.Errors(); len(errs) > 0 {
+		result.Error = fmt.Sprintf("type errors: %v", errs[0])
+	}
+
+	for _, pkg := range prog.Packages {
+		if pkg.TypesInfo == nil {
+			continue
+		}
+		c := &cryptoChecker{analyzer: a, prog: prog, pkg: pkg}
+		for _, file := range pkg.Syntax {
+			result.Findings = append(result.Findings, c.checkFile(file)...)
+		}
+	}
+	sort.SliceStable(result.Findings, func(i, j int) bool {
+		fi, fj := result.Findings[i], result.Findings[j]
+		if fi.File != fj.File {
+			return fi.File < fj.File
+		}
+		return fi.Line < fj.Line
+	})
+
+	result.Duration = time.Since(start)
+	return result
+}
+
+// cryptoChecker inspects the files of one package
+type cryptoChecker struct {
+	analyzer *goCryptoAnalyzer
+	prog     *goanalysis.Program
+	pkg      *packages.Package
+	// inits holds the initializer of each variable and whether it is
+	// assigned again
+	inits    map[*types.Var]ast.Expr
+	assigned map[*types.Var]bool
+
+	file     *ast.File
+	findings []Finding
+}
+
+// checkFile walks a file keeping the stack of enclosing nodes
+func (c *cryptoChecker) checkFile(file *ast.File) []Finding {
+	c.file = file
+	c.findings = nil
+
+	var stack []ast.Node
+	ast.Inspect(file, func(n ast.Node) bool {
+		if n == nil {
+			stack = stack[:len(stack)-1]
+			return true
+		}
+		stack = append(stack, n)
+		switch n := n.(type) {
+		case *ast.CallExpr:
+			c.checkCall(n, stack)
+		case *ast.CompositeLit:
+			c.checkTLSLiteral(n)
+		case *ast.AssignStmt:
+			c.checkTLSAssign(n)
+		}
+		return true
+	})
+	return c.findings
+}
+
+// checkCall applies the checks keyed by the called function
+func (c *cryptoChecker) checkCall(call *ast.CallExpr, stack []ast.Node) {
+	fn, ok := typeutil.Callee(c.pkg.TypesInfo, call).(*types.Func)
+	if !ok || fn.Pkg() == nil {
+		return
+	}
+	name := fn.FullName()
+
+	switch {
+	case weakHashFuncs[name]:
+		if c.sensitive(stack) {
+			c.report("go/weak-hash", call, name, nil, "")
+		}
+
+	case isMathRand(fn):
+		if fn.Pkg().Path() == "math/rand" && fn.Name() == "Read" {
+			edits, desc := c.cryptoRandFix(call)
+			c.report("go/insecure-random", call, name, edits, desc)
+		} else if c.sensitive(stack) {
+			c.report("go/insecure-random", call, name, nil, "")
+		}
+	}
+
+	if i, ok := cryptoKeyArgs[name]; ok && i < len(call.Args) && c.hardcoded(call.Args[i], 0) {
+		c.report("go/hardcoded-key", call.Args[i], name, nil, "")
+	}
+	if i, ok := cryptoIVArgs[name]; ok && i < len(call.Args) && c.hardcoded(call.Args[i], 0) {
+		c.report("go/hardcoded-iv", call.Args[i], name, nil, "")
+	}
+	if i, ok := rsaBitsArgs[name]; ok && i < len(call.Args) {
+		arg := call.Args[i]
+		if bits, ok := c.intValue(arg); ok && bits < 2048 {
+			var edits []diff.Edit
+			if lit, ok := ast.Unparen(arg).(*ast.BasicLit); ok {
+				edits = []diff.Edit{c.replace(lit, "2048")}
+			}
+			c.report("go/weak-rsa-key", arg, fmt.Sprintf("%d bits", bits), edits, "Generate a 2048-bit RSA key")
+		}
+	}
+}
+
+// isMathRand reports whether fn belongs to math/rand or math/rand/v2
+func isMathRand(fn *types.Func) bool {
+	path := fn.Pkg().Path()
+	return path == "math/rand" || path == "math/rand/v2"
+}
+
+// checkTLSLiteral checks the fields of a tls.Config literal
+func (c *cryptoChecker) checkTLSLiteral(lit *ast.CompositeLit) {
+	if !isTLSConfig(c.pkg.TypesInfo.TypeOf(lit)) {
+		return
+	}
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		if key, ok := kv.Key.(*ast.Ident); ok {
+			c.checkTLSField(key.Name, kv.Value)
+		}
+	}
+}
+
+// checkTLSAssign checks assignments to tls.Config fields
+func (c *cryptoChecker) checkTLSAssign(assign *ast.AssignStmt) {
+	if len(assign.Lhs) != len(assign.Rhs) {
+		return
+	}
+	for i, lhs := range assign.Lhs {
+		sel, ok := lhs.(*ast.SelectorExpr)
+		if !ok {
+			continue
+		}
+		selection := c.pkg.TypesInfo.Selections[sel]
+		if selection == nil || selection.Kind() != types.FieldVal || !isTLSConfig(selection.Recv()) {
+			continue
+		}
+		c.checkTLSField(sel.Sel.Name, assign.Rhs[i])
+	}
+}
+
+// checkTLSField reports an insecure value of a tls.Config field
+func (c *cryptoChecker) checkTLSField(field string, value ast.Expr) {
+	tv, ok := c.pkg.TypesInfo.Types[value]
+	if !ok || tv.Value == nil {
+		return
+	}
+
+	switch field {
+	case "InsecureSkipVerify":
+		if tv.Value.Kind() == constant.Bool && constant.BoolVal(tv.Value) {
+			c.report("go/tls-insecure-skip-verify", value, "InsecureSkipVerify: true",
+				[]diff.Edit{c.replace(value, "false")}, "Enable TLS certificate verification")
+		}
+	case "MinVersion":
+		v, ok := constant.Int64Val(tv.Value)
+		if !ok || v == 0 || v >= tlsVersion12 {
+			return
+		}
+		var edits []diff.Edit
+		if sel, ok := ast.Unparen(value).(*ast.SelectorExpr); ok && c.isPackage(sel.X, "crypto/tls") {
+			edits = []diff.Edit{c.replace(sel.Sel, "VersionTLS12")}
+		}
+		c.report("go/tls-min-version", value, fmt.Sprintf("MinVersion 0x%04x", v), edits, "Require TLS 1.2 or later")
+	}
+}
+
+// isTLSConfig reports whether t is tls.Config or a pointer to it
+func isTLSConfig(t types.Type) bool {
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, ok := types.Unalias(t).(*types.Named)
+	return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == "crypto/tls" && named.Obj().Name() == "Config"
+}
+
+// isPackage reports whether expr names an import of path
+func (c *cryptoChecker) isPackage(expr ast.Expr, path string) bool {
+	id, ok := expr.(*ast.Ident)
+	if !ok {
+		return false
+	}
+	pkgName, ok := c.pkg.TypesInfo.Uses[id].(*types.PkgName)
+	return ok && pkgName.Imported().Path() == path
+}
+
+// sensitive reports whether the enclosing function or statement mentions
+// a security-related identifier
+func (c *cryptoChecker) sensitive(stack []ast.Node) bool {
+	var stmt ast.Node
+	for i := len(stack) - 1; i >= 0; i-- {
+		switch n := stack[i].(type) {
+		case *ast.FuncDecl:
+			if c.analyzer.securityNames.MatchString(n.Name.Name) {
+				return true
+			}
+		case ast.Stmt, *ast.ValueSpec:
+			if stmt == nil {
+				if _, ok := n.(*ast.BlockStmt); !ok {
+					stmt = n
+				}
+			}
+		}
+	}
+	if stmt == nil {
+		return false
+	}
+
+	found := false
+	ast.Inspect(stmt, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok && c.analyzer.securityNames.MatchString(id.Name) {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// hardcoded reports whether expr is built from constants only: a
+// constant, a []byte conversion or literal of constants, or a variable
+// initialized that way and never reassigned
+func (c *cryptoChecker) hardcoded(expr ast.Expr, depth int) bool {
+	if depth > 4 {
+		return false
+	}
+	expr = ast.Unparen(expr)
+	info := c.pkg.TypesInfo
+	if tv, ok := info.Types[expr]; ok && tv.Value != nil {
+		return true
+	}
+
+	switch e := expr.(type) {
+	case *ast.CallExpr:
+		if tv, ok := info.Types[e.Fun]; ok && tv.IsType() && len(e.Args) == 1 {
+			return c.hardcoded(e.Args[0], depth+1)
+		}
+	case *ast.CompositeLit:
+		if len(e.Elts) == 0 {
+			return false
+		}
+		for _, elt := range e.Elts {
+			if kv, ok := elt.(*ast.KeyValueExpr); ok {
+				elt = kv.Value
+			}
+			if tv, ok := info.Types[elt]; !ok || tv.Value == nil {
+				return false
+			}
+		}
+		return true
+	case *ast.Ident:
+		v, ok := info.Uses[e].(*types.Var)
+		if !ok {
+			return false
+		}
+		c.indexVars()
+		if init, ok := c.inits[v]; ok && !c.assigned[v] {
+			return c.hardcoded(init, depth+1)
+		}
+	}
+	return false
+}
+
+// indexVars records the initializer of every variable in the package
+// and which variables are assigned or have their address taken later
+func (c *cryptoChecker) indexVars() {
+	if c.inits != nil {
+		return
+	}
+	c.inits = make(map[*types.Var]ast.Expr)
+	c.assigned = make(map[*types.Var]bool)
+	info := c.pkg.TypesInfo
+
+	for _, file := range c.pkg.Syntax {
+		ast.Inspect(file, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.ValueSpec:
+				if len(n.Names) == len(n.Values) {
+					for i, name := range n.Names {
+						if v, ok := info.Defs[name].(*types.Var); ok {
+							c.inits[v] = n.Values[i]
+						}
+					}
+				}
+			case *ast.AssignStmt:
+				for i, lhs := range n.Lhs {
+					id, ok := lhs.(*ast.Ident)
+					if !ok {
+						continue
+					}
+					if v, ok := info.Defs[id].(*types.Var); ok && n.Tok == token.DEFINE && len(n.Lhs) == len(n.Rhs) {
+						c.inits[v] = n.Rhs[i]
+					} else if v, ok := info.Uses[id].(*types.Var); ok {
+						c.assigned[v] = true
+					}
+				}
+			case *ast.UnaryExpr:
+				if id, ok := n.X.(*ast.Ident); ok && n.Op == token.AND {
+					if v, ok := info.Uses[id].(*types.Var); ok {
+						c.assigned[v] = true
+					}
+				}
+			}
+			return true
+		})
+	}
+}
+
+// intValue returns the constant integer value of expr
+func (c *cryptoChecker) intValue(expr ast.Expr) (int64, bool) {
+	tv, ok := c.pkg.TypesInfo.Types[expr]
+	if !ok || tv.Value == nil {
+		return 0, false
+	}
+	return constant.Int64Val(constant.ToInt(tv.Value))
+}
+
+// cryptoRandFix switches the math/rand import to crypto/rand when the
+// file only uses its Read function, which has the same signature
+func (c *cryptoChecker) cryptoRandFix(call *ast.CallExpr) ([]diff.Edit, string) {
+	info := c.pkg.TypesInfo
+	sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+	if !ok {
+		return nil, ""
+	}
+	id, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return nil, ""
+	}
+	pkgName, ok := info.Uses[id].(*types.PkgName)
+	if !ok {
+		return nil, ""
+	}
+
+	onlyRead := true
+	ast.Inspect(c.file, func(n ast.Node) bool {
+		s, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return onlyRead
+		}
+		if x, ok := s.X.(*ast.Ident); ok && info.Uses[x] == pkgName && s.Sel.Name != "Read" {
+			onlyRead = false
+		}
+		return onlyRead
+	})
+	if !onlyRead {
+		return nil, ""
+	}
+
+	for _, imp := range c.file.Imports {
+		if imp.Path.Value != `"math/rand"` {
+			continue
+		}
+		if obj := info.PkgNameOf(imp); obj != pkgName {
+			continue
+		}
+		return []diff.Edit{c.replace(imp.Path, `"crypto/rand"`)}, "Read random bytes from crypto/rand"
+	}
+	return nil, ""
+}
+
+// replace returns an edit replacing node with text
+func (c *cryptoChecker) replace(node ast.Node, text string) diff.Edit {
+	return diff.Edit{
+		Start: c.prog.Fset.Position(node.Pos()).Offset,
+		End:   c.prog.Fset.Position(node.End()).Offset,
+		New:   text,
+	}
+}
+
+// report records a finding at node with an optional fix
+func (c *cryptoChecker) report(ruleID string, node ast.Node, detail string, edits []diff.Edit, fixDesc string) {
+	rule := cryptoRules[ruleID]
+	pos := c.prog.Fset.Position(node.Pos())
+	rel := c.prog.RelPath(pos.Filename)
+
+	f := Finding{
+		RuleID:     ruleID,
+		Message:    fmt.Sprintf("%s (%s)", rule.message, detail),
+		Severity:   rule.severity,
+		File:       filepath.FromSlash(rel),
+		Line:       pos.Line,
+		Column:     pos.Column,
+		Confidence: "high",
+		Tool:       "gocrypto",
+		Rule: &RuleMetadata{
+			Name:             strings.TrimPrefix(ruleID, "go/"),
+			ShortDescription: rule.message,
+			Tags:             []string{"crypto", rule.cwe},
+		},
+	}
+	if len(edits) > 0 {
+		if fix := fileFix(pos.Filename, filepath.ToSlash(rel), edits); fix != "" {
+			f.Fix = &Fix{Description: fixDesc, Diff: fix}
+		}
+	}
+	c.findings = append(c.findings, f)
+}
+
+// fileFix applies edits to a file and returns the unified diff
+func fileFix(path, rel string, edits []diff.Edit) string {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	fixed, err := diff.Apply(src, edits)
+	if err != nil {
+		return ""
+	}
+	return diff.Unified(rel, src, fixed)
+}
@@ -0,0 +1,157 @@
+package security
+
+import (
+	"context"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Siddhant-K-code/sentinel-ai/internal/goanalysis"
+	"github.com/Siddhant-K-code/sentinel-ai/internal/policy"
+	"github.com/Siddhant-K-code/sentinel-ai/internal/testutil"
+	"github.com/Siddhant-K-code/sentinel-ai/internal/tools"
+)
+
+const cryptoApp = `package app
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+)
+
+var key = []byte("0123456789abcdef")
+
+func Checksum(data []byte) [16]byte {
+	return md5.Sum(data)
+}
+
+func HashPassword(password string) [20]byte {
+	return sha1.Sum([]byte(password))
+}
+
+func Client() *http.Client {
+	cfg := &tls.Config{InsecureSkipVerify: true, MinVersion: tls.VersionTLS10}
+	cfg.InsecureSkipVerify = false
+	return &http.Client{Transport: &http.Transport{TLSClientConfig: cfg}}
+}
+
+func Encrypt(plaintext []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	nonce := []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}
+	return gcm.Seal(nil, nonce, plaintext, nil), nil
+}
+
+func RandomKey() ([]byte, error) {
+	k := make([]byte, 32)
+	_, err := rand.Read(k)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(k)
+	_ = block
+	return k, err
+}
+
+func KeyPair() (*rsa.PrivateKey, error) {
+	return rsa.GenerateKey(rand.Reader, 1024)
+}
+
+func Describe() string {
+	return fmt.Sprint(tls.VersionTLS13)
+}
+`
+
+const tokenApp = `package app
+
+import "math/rand"
+
+func SessionToken() []byte {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return b
+}
+`
+
+const shuffleApp = `package app
+
+import "math/rand"
+
+func Shuffle(xs []int) {
+	rand.Shuffle(len(xs), func(i, j int) { xs[i], xs[j] = xs[j], xs[i] })
+}
+`
+
+func TestGoCryptoAnalyzer(t *testing.T) {
+	root := testutil.WriteFiles(t, map[string]string{
+		"go.mod":          "module example.com/app\n\ngo 1.22\n",
+		"crypto.go":       cryptoApp,
+		"token.go":        tokenApp,
+		"shuffle/main.go": strings.Replace(shuffleApp, "package app", "package shuffle", 1),
+	})
+
+	runner := tools.NewRunner(root, [][]string{goanalysis.ListCommand}, time.Minute)
+	env := Env{Runner: runner, Workspace: root, Policy: policy.DefaultPolicy()}
+	analyzer, err := newGoCryptoAnalyzer(env, policy.AnalyzerConfig{Enabled: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := analyzer.Run(context.Background())
+	if result.Error != "" {
+		t.Fatal(result.Error)
+	}
+
+	got := make(map[string]Finding)
+	for _, f := range result.Findings {
+		got[f.RuleID+":"+filepath.ToSlash(f.File)+":"+strconv.Itoa(f.Line)] = f
+	}
+	want := []string{
+		"go/weak-hash:crypto.go:22",
+		"go/tls-insecure-skip-verify:crypto.go:26",
+		"go/tls-min-version:crypto.go:26",
+		"go/hardcoded-key:crypto.go:32",
+		"go/hardcoded-iv:crypto.go:41",
+		"go/weak-rsa-key:crypto.go:56",
+		"go/insecure-random:token.go:7",
+	}
+	for _, key := range want {
+		if _, ok := got[key]; !ok {
+			t.Errorf("Expected finding %s", key)
+		}
+	}
+	if len(result.Findings) != len(want) {
+		t.Errorf("Expected %d findings, got %d: %v", len(want), len(result.Findings), got)
+	}
+
+	fixes := map[string]string{
+		"go/tls-insecure-skip-verify:crypto.go:26": "+\tcfg := &tls.Config{InsecureSkipVerify: false, MinVersion: tls.VersionTLS10}",
+		"go/tls-min-version:crypto.go:26":          "+\tcfg := &tls.Config{InsecureSkipVerify: true, MinVersion: tls.VersionTLS12}",
+		"go/weak-rsa-key:crypto.go:56":             "+\treturn rsa.GenerateKey(rand.Reader, 2048)",
+		"go/insecure-random:token.go:7":            "--- a/token.go\n+++ b/token.go\n@@ -1,6 +1,6 @@\n package app\n \n-import \"math/rand\"\n+import \"crypto/rand\"\n",
+	}
+	for key, want := range fixes {
+		f := got[key]
+		if f.Fix == nil || !strings.Contains(f.Fix.Diff, want) {
+			t.Errorf("Expected %s fix containing %q, got %+v", key, want, f.Fix)
+		}
+	}
+	if f := got["go/hardcoded-key:crypto.go:32"]; f.Fix != nil {
+		t.Errorf("Expected no fix for a hard-coded key, got %+v", f.Fix)
+	}
+}
@@ -0,0 +1,217 @@
+package security
+
+import (
+	"context"
+	"fmt"
+	"go/types"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"golang.org/x/tools/go/ssa"
+
+	"github.com/Siddhant-K-code/sentinel-ai/internal/goanalysis"
+	"github.com/Siddhant-K-code/sentinel-ai/internal/policy"
+)
+
+func init() {
+	Register("gorows", newGoRowsAnalyzer, false)
+}
+
+// unclosedRowsMessage describes go/unclosed-rows
+const unclosedRowsMessage = "Result set from a database query is never closed"
+
+// rowsMethods are the methods of sql.Rows that read it without taking
+// ownership
+var rowsMethods = map[string]bool{
+	"Next": true, "NextResultSet": true, "Scan": true, "Err": true,
+	"Columns": true, "ColumnTypes": true,
+}
+
+// GoRowsOptions configures the result set analyzer
+type GoRowsOptions struct {
+	// Tags are build tags used when loading packages
+	Tags []string `yaml:"tags"`
+}
+
+// goRowsAnalyzer reports database/sql result sets that a function opens
+// and neither closes nor hands on, which holds the connection until the
+// garbage collector finds them
+type goRowsAnalyzer struct {
+	env  Env
+	opts GoRowsOptions
+}
+
+// newGoRowsAnalyzer creates the analyzer from its policy options
+func newGoRowsAnalyzer(env Env, cfg policy.AnalyzerConfig) (Analyzer, error) {
+	var opts GoRowsOptions
+	if err := cfg.Decode(&opts); err != nil {
+		return nil, err
+	}
+	return &goRowsAnalyzer{env: env, opts: opts}, nil
+}
+
+// Name returns the analyzer name
+func (a *goRowsAnalyzer) Name() string {
+	return "gorows"
+}
+
+// Languages returns the languages the analyzer understands
+func (a *goRowsAnalyzer) Languages() []string {
+	return []string{"go"}
+}
+
+// Available always succeeds; the analysis runs in process
+func (a *goRowsAnalyzer) Available() error {
+	return nil
+}
+
+// Run builds the SSA form of the workspace and follows every *sql.Rows a
+// call returns
+func (a *goRowsAnalyzer) Run(ctx context.Context) *ScanResult {
+	start := time.Now()
+	result := &ScanResult{Tool: "gorows", Findings: []Finding{}}
+
+	prog, err := a.env.LoadGo(ctx, goanalysis.Options{
+		Tags:    a.opts.Tags,
+		Network: a.env.Policy.Modes["default"].Network,
+	})
+	if err != nil {
+		result.Error = err.Error()
+		result.Duration = time.Since(start)
+		return result
+	}
+	if errs := prog.Errors(); len(errs) > 0 {
+		result.Error = fmt.Sprintf("type errors: %v", errs[0])
+	}
+
+	for _, fn := range prog.WorkspaceFuncs() {
+		for _, block := range fn.Blocks {
+			for _, instr := range block.Instrs {
+				call, ok := instr.(*ssa.Call)
+				if !ok {
+					continue
+				}
+				rows, ok := rowsResult(call)
+				if !ok || rows != nil && released(rows, make(map[ssa.Value]bool)) {
+					continue
+				}
+				result.Findings = append(result.Findings, rowsFinding(prog, call))
+			}
+		}
+	}
+	sort.SliceStable(result.Findings, func(i, j int) bool {
+		fi, fj := result.Findings[i], result.Findings[j]
+		if fi.File != fj.File {
+			return fi.File < fj.File
+		}
+		return fi.Line < fj.Line
+	})
+
+	result.Duration = time.Since(start)
+	return result
+}
+
+// rowsResult returns the *sql.Rows a call returns, and whether it returns
+// rows at all. The value is nil when the caller discards the rows.
+func rowsResult(call *ssa.Call) (ssa.Value, bool) {
+	t, ok := call.Type().(*types.Tuple)
+	if !ok {
+		if isSQLRows(call.Type()) {
+			return call, true
+		}
+		return nil, false
+	}
+	for i := 0; i < t.Len(); i++ {
+		if !isSQLRows(t.At(i).Type()) {
+			continue
+		}
+		for _, ref := range *call.Referrers() {
+			if ext, ok := ref.(*ssa.Extract); ok && ext.Index == i {
+				return ext, true
+			}
+		}
+		return nil, true
+	}
+	return nil, false
+}
+
+// isSQLRows reports whether t is *database/sql.Rows
+func isSQLRows(t types.Type) bool {
+	ptr, ok := t.(*types.Pointer)
+	if !ok {
+		return false
+	}
+	named, ok := types.Unalias(ptr.Elem()).(*types.Named)
+	return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == "database/sql" && named.Obj().Name() == "Rows"
+}
+
+// released reports whether the rows are closed or handed to code that may
+// close them: returned, stored, captured, converted to an interface or
+// passed to a function other than the methods that only read them
+func released(v ssa.Value, seen map[ssa.Value]bool) bool {
+	if seen[v] {
+		return false
+	}
+	seen[v] = true
+	refs := v.Referrers()
+	if refs == nil {
+		return false
+	}
+	for _, ref := range *refs {
+		switch r := ref.(type) {
+		case *ssa.Phi:
+			if released(r, seen) {
+				return true
+			}
+		case ssa.CallInstruction:
+			if callee := r.Common().StaticCallee(); callee != nil && isSQLRowsMethod(callee) {
+				if callee.Name() == "Close" {
+					return true
+				}
+				if rowsMethods[callee.Name()] {
+					continue
+				}
+			}
+			return true
+		case *ssa.DebugRef:
+			continue
+		default:
+			// Return, Store, MakeClosure, MakeInterface, ChangeType...
+			return true
+		}
+	}
+	return false
+}
+
+// isSQLRowsMethod reports whether fn is a method of *sql.Rows
+func isSQLRowsMethod(fn *ssa.Function) bool {
+	recv := fn.Signature.Recv()
+	return recv != nil && isSQLRows(recv.Type())
+}
+
+// rowsFinding reports the call that opened unclosed rows
+func rowsFinding(prog *goanalysis.Program, call *ssa.Call) Finding {
+	pos := prog.Fset.Position(call.Pos())
+	callee := "query"
+	if fn := call.Call.StaticCallee(); fn != nil {
+		callee = fn.Name()
+	} else if call.Call.Method != nil {
+		callee = call.Call.Method.Name()
+	}
+	return Finding{
+		RuleID:     "go/unclosed-rows",
+		Message:    fmt.Sprintf("%s (rows from %s)", unclosedRowsMessage, callee),
+		Severity:   "warning",
+		File:       filepath.FromSlash(prog.RelPath(pos.Filename)),
+		Line:       pos.Line,
+		Column:     pos.Column,
+		Confidence: "high",
+		Tool:       "gorows",
+		Rule: &RuleMetadata{
+			Name:             "unclosed-rows",
+			ShortDescription: unclosedRowsMessage,
+			Tags:             []string{"CWE-404"},
+		},
+	}
+}
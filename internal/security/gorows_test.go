@@ -0,0 +1,97 @@
+package security
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Siddhant-K-code/sentinel-ai/internal/goanalysis"
+	"github.com/Siddhant-K-code/sentinel-ai/internal/policy"
+	"github.com/Siddhant-K-code/sentinel-ai/internal/testutil"
+	"github.com/Siddhant-K-code/sentinel-ai/internal/tools"
+)
+
+const rowsApp = `package app
+
+import "database/sql"
+
+func Leak(db *sql.DB) error {
+	rows, err := db.Query("SELECT name FROM users")
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+	}
+	return rows.Err()
+}
+
+func Discard(db *sql.DB) {
+	db.Query("DELETE FROM sessions")
+}
+
+func Deferred(db *sql.DB) error {
+	rows, err := db.Query("SELECT name FROM users")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+	}
+	return nil
+}
+
+func Closed(db *sql.DB) {
+	rows, _ := db.Query("SELECT name FROM users")
+	rows.Close()
+}
+
+func Open(db *sql.DB) (*sql.Rows, error) {
+	return db.Query("SELECT name FROM users")
+}
+
+func Consume(db *sql.DB) error {
+	rows, err := Open(db)
+	if err != nil {
+		return err
+	}
+	return drain(rows)
+}
+
+func drain(rows *sql.Rows) error {
+	defer rows.Close()
+	return nil
+}
+`
+
+func TestGoRowsAnalyzer(t *testing.T) {
+	root := testutil.WriteFiles(t, map[string]string{
+		"go.mod":  "module example.com/app\n\ngo 1.22\n",
+		"rows.go": rowsApp,
+	})
+
+	runner := tools.NewRunner(root, [][]string{goanalysis.ListCommand}, time.Minute)
+	env := Env{Runner: runner, Workspace: root, Policy: policy.DefaultPolicy()}
+	analyzer, err := newGoRowsAnalyzer(env, policy.AnalyzerConfig{Enabled: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := analyzer.Run(context.Background())
+	if result.Error != "" {
+		t.Fatal(result.Error)
+	}
+
+	var got []int
+	for _, f := range result.Findings {
+		if f.RuleID != "go/unclosed-rows" || f.File != "rows.go" {
+			t.Errorf("Unexpected finding %+v", f)
+		}
+		got = append(got, f.Line)
+	}
+	// Rows that are closed, deferred-closed, returned or passed on are not
+	// reported
+	want := []int{6, 16}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("Expected findings on lines %v, got %v", want, got)
+	}
+}
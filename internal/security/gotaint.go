@@ -0,0 +1,244 @@
+package security
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/Siddhant-K-code/sentinel-ai/internal/goanalysis"
+	"github.com/Siddhant-K-code/sentinel-ai/internal/policy"
+)
+
+func init() {
+	Register("gotaint", newGoTaintAnalyzer, true)
+}
+
+// DefaultTaintSpec covers HTTP request data, command-line arguments and
+// the environment flowing into HTML, commands, SQL, file paths and
+// redirects
+var DefaultTaintSpec = goanalysis.TaintSpec{
+	Sources: []goanalysis.TaintSource{
+		{Type: "net/http.Request"},
+		{Function: "(*net/http.Request).FormValue"},
+		{Function: "(*net/http.Request).PostFormValue"},
+		{Function: "(*net/http.Request).FormFile"},
+		{Function: "(*net/http.Request).Cookie"},
+		{Function: "(*net/http.Request).Cookies"},
+		{Function: "(*net/http.Request).Referer"},
+		{Function: "(*net/http.Request).UserAgent"},
+		{Function: "(*net/http.Request).PathValue"},
+		{Function: "os.Getenv"},
+		{Function: "os.LookupEnv"},
+		{Function: "os.Environ"},
+		{Global: "os.Args"},
+	},
+	Sinks: []goanalysis.TaintSink{
+		{
+			RuleID:   "go/xss",
+			Message:  "Untrusted data is marked as safe HTML, bypassing html/template escaping",
+			CWE:      "CWE-79",
+			Severity: "error",
+			Types: []string{
+				"html/template.HTML", "html/template.HTMLAttr", "html/template.JS",
+				"html/template.JSStr", "html/template.CSS", "html/template.URL", "html/template.Srcset",
+			},
+		},
+		{
+			RuleID:   "go/command-injection",
+			Message:  "Untrusted data is used to build a command",
+			CWE:      "CWE-78",
+			Severity: "error",
+			Calls: []goanalysis.TaintCall{
+				{Function: "os/exec.Command"},
+				{Function: "os/exec.CommandContext", Args: []int{1, 2}},
+			},
+		},
+		{
+			RuleID:   "go/sql-injection",
+			Message:  "Untrusted data is used in an SQL query string",
+			CWE:      "CWE-89",
+			Severity: "error",
+			Calls:    sqlSinks(),
+		},
+		{
+			RuleID:   "go/path-traversal",
+			Message:  "Untrusted data is used as a file path",
+			CWE:      "CWE-22",
+			Severity: "warning",
+			Calls: []goanalysis.TaintCall{
+				{Function: "os.Open", Args: []int{0}},
+				{Function: "os.OpenFile", Args: []int{0}},
+				{Function: "os.ReadFile", Args: []int{0}},
+				{Function: "os.WriteFile", Args: []int{0}},
+				{Function: "os.Create", Args: []int{0}},
+				{Function: "os.Remove", Args: []int{0}},
+				{Function: "os.RemoveAll", Args: []int{0}},
+				{Function: "net/http.ServeFile", Args: []int{2}},
+			},
+		},
+		{
+			RuleID:   "go/open-redirect",
+			Message:  "Untrusted data is used as a redirect target",
+			CWE:      "CWE-601",
+			Severity: "warning",
+			Calls: []goanalysis.TaintCall{
+				{Function: "net/http.Redirect", Args: []int{2}},
+			},
+		},
+	},
+	Sanitizers: []string{
+		"html.EscapeString",
+		"html/template.HTMLEscapeString",
+		"html/template.JSEscapeString",
+		"html/template.URLQueryEscaper",
+		"net/url.QueryEscape",
+		"net/url.PathEscape",
+		"path.Base",
+		"path/filepath.Base",
+		"strconv.Atoi",
+		"strconv.ParseInt",
+		"strconv.ParseUint",
+		"strconv.ParseFloat",
+		"strconv.ParseBool",
+	},
+}
+
+// sqlSinks lists the query string argument of database/sql methods
+func sqlSinks() []goanalysis.TaintCall {
+	var calls []goanalysis.TaintCall
+	for _, recv := range []string{"DB", "Tx", "Conn"} {
+		for _, method := range []string{"Query", "QueryRow", "Exec", "Prepare"} {
+			calls = append(calls,
+				goanalysis.TaintCall{Function: fmt.Sprintf("(*database/sql.%s).%s", recv, method), Args: []int{0}},
+				goanalysis.TaintCall{Function: fmt.Sprintf("(*database/sql.%s).%sContext", recv, method), Args: []int{1}},
+			)
+		}
+	}
+	return calls
+}
+
+// GoTaintOptions configures the Go taint analyzer. Sources, sinks and
+// sanitizers are added to DefaultTaintSpec unless ReplaceDefaults is set.
+type GoTaintOptions struct {
+	goanalysis.TaintSpec `yaml:",inline"`
+	ReplaceDefaults      bool `yaml:"replace_defaults"`
+	// Tags are build tags used when loading packages
+	Tags []string `yaml:"tags"`
+}
+
+// goTaintAnalyzer tracks untrusted data through the SSA form of the
+// workspace's Go code
+type goTaintAnalyzer struct {
+	env  Env
+	opts GoTaintOptions
+	spec goanalysis.TaintSpec
+}
+
+// newGoTaintAnalyzer creates the taint analyzer from its policy options
+func newGoTaintAnalyzer(env Env, cfg policy.AnalyzerConfig) (Analyzer, error) {
+	var opts GoTaintOptions
+	if err := cfg.Decode(&opts); err != nil {
+		return nil, err
+	}
+
+	spec := opts.TaintSpec
+	if !opts.ReplaceDefaults {
+		spec.Sources = append(append([]goanalysis.TaintSource{}, DefaultTaintSpec.Sources...), spec.Sources...)
+		spec.Sinks = append(append([]goanalysis.TaintSink{}, DefaultTaintSpec.Sinks...), spec.Sinks...)
+		spec.Sanitizers = append(append([]string{}, DefaultTaintSpec.Sanitizers...), spec.Sanitizers...)
+	}
+	for i, sink := range spec.Sinks {
+		if sink.RuleID == "" {
+			return nil, fmt.Errorf("sink %d has no rule_id", i)
+		}
+		if len(sink.Calls) == 0 && len(sink.Types) == 0 {
+			return nil, fmt.Errorf("sink %s has no calls or types", sink.RuleID)
+		}
+		if sink.Severity == "" {
+			spec.Sinks[i].Severity = "warning"
+		}
+	}
+	return &goTaintAnalyzer{env: env, opts: opts, spec: spec}, nil
+}
+
+// Name returns the analyzer name
+func (a *goTaintAnalyzer) Name() string {
+	return "gotaint"
+}
+
+// Languages returns the languages the analyzer understands
+func (a *goTaintAnalyzer) Languages() []string {
+	return []string{"go"}
+}
+
+// Available always succeeds; the analysis runs in process
+func (a *goTaintAnalyzer) Available() error {
+	return nil
+}
+
+// Run loads the workspace packages and reports tainted data reaching a
+// sink, with the path it took as a code flow
+func (a *goTaintAnalyzer) Run(ctx context.Context) *ScanResult {
+	start := time.Now()
+	result := &ScanResult{Tool: "gotaint", Findings: []Finding{}}
+
+	prog, err := a.env.LoadGo(ctx, goanalysis.Options{
+		Tags:    a.opts.Tags,
+		Network: a.env.Policy.Modes["default"].Network,
+	})
+	if err != nil {
+		result.Error = err.Error()
+		result.Duration = time.Since(start)
+		return result
+	}
+	if errs := prog.Errors(); len(errs) > 0 {
+		result.Error = fmt.Sprintf("type errors: %v", errs[0])
+	}
+
+	for _, r := range prog.Taint(&a.spec) {
+		result.Findings = append(result.Findings, taintFinding(prog, r))
+	}
+
+	result.Duration = time.Since(start)
+	return result
+}
+
+// taintFinding converts a taint path into a finding
+func taintFinding(prog *goanalysis.Program, r goanalysis.TaintResult) Finding {
+	message := r.Sink.Message
+	if message == "" {
+		message = "Untrusted data reaches " + r.Callee
+	}
+	if r.Source != "" {
+		message += fmt.Sprintf(" (%s flows into %s)", r.Source, r.Callee)
+	}
+
+	flow := CodeFlow{}
+	for _, step := range r.Steps {
+		flow.Steps = append(flow.Steps, FlowStep{
+			File:    prog.RelPath(step.Pos.Filename),
+			Line:    step.Pos.Line,
+			Column:  step.Pos.Column,
+			Message: step.Message,
+		})
+	}
+
+	rule := &RuleMetadata{Name: r.Sink.RuleID, ShortDescription: r.Sink.Message}
+	if r.Sink.CWE != "" {
+		rule.Tags = []string{r.Sink.CWE}
+	}
+
+	return Finding{
+		RuleID:     r.Sink.RuleID,
+		Message:    message,
+		Severity:   r.Sink.Severity,
+		File:       filepath.FromSlash(prog.RelPath(r.Pos.Filename)),
+		Line:       r.Pos.Line,
+		Column:     r.Pos.Column,
+		Confidence: "medium",
+		Tool:       "gotaint",
+		Rule:       rule,
+		CodeFlows:  []CodeFlow{flow},
+	}
+}
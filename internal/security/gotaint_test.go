@@ -0,0 +1,159 @@
+package security
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Siddhant-K-code/sentinel-ai/internal/goanalysis"
+	"github.com/Siddhant-K-code/sentinel-ai/internal/policy"
+	"github.com/Siddhant-K-code/sentinel-ai/internal/tools"
+)
+
+const taintApp = `package main
+
+import (
+	"database/sql"
+	"html/template"
+	"net/http"
+	"os"
+	"os/exec"
+)
+
+var db *sql.DB
+
+func name(r *http.Request) string {
+	return r.URL.Query().Get("name")
+}
+
+func greet(w http.ResponseWriter, r *http.Request) {
+	t := template.Must(template.New("x").Parse("{{.}}"))
+	t.Execute(w, template.HTML("<h1>"+name(r)+"</h1>"))
+}
+
+func safeGreet(w http.ResponseWriter, r *http.Request) {
+	t := template.Must(template.New("x").Parse("{{.}}"))
+	t.Execute(w, template.HTML(template.HTMLEscapeString(name(r))))
+}
+
+func lookup(w http.ResponseWriter, r *http.Request) {
+	db.Query("SELECT * FROM users WHERE name = '" + r.FormValue("user") + "'")
+	db.Query("SELECT * FROM users WHERE name = ?", r.FormValue("user"))
+}
+
+func download(w http.ResponseWriter, r *http.Request) {
+	http.Redirect(w, r, r.Header.Get("Referer"), http.StatusFound)
+}
+
+func audit(path string) {
+	os.ReadFile(path)
+}
+
+func main() {
+	exec.Command("sh", "-c", os.Args[1]).Run()
+	audit(os.Getenv("AUDIT"))
+	http.HandleFunc("/greet", greet)
+	http.HandleFunc("/safe", safeGreet)
+	http.HandleFunc("/lookup", lookup)
+	http.HandleFunc("/download", download)
+}
+`
+
+func TestGoTaintAnalyzer(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"go.mod":  "module example.com/app\n\ngo 1.22\n",
+		"main.go": taintApp,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(root, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	runner := tools.NewRunner(root, [][]string{goanalysis.ListCommand}, time.Minute)
+	env := Env{Runner: runner, Workspace: root, Policy: policy.DefaultPolicy()}
+	cfg := policy.AnalyzerConfig{
+		Enabled: true,
+		Options: map[string]interface{}{
+			"sinks": []interface{}{
+				map[string]interface{}{
+					"rule_id":  "custom/audit-log",
+					"cwe":      "CWE-117",
+					"severity": "note",
+					"calls":    []interface{}{map[string]interface{}{"function": "example.com/app.audit"}},
+				},
+			},
+		},
+	}
+	analyzer, err := newGoTaintAnalyzer(env, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := analyzer.Run(context.Background())
+	if result.Error != "" {
+		t.Fatal(result.Error)
+	}
+
+	got := make(map[string]Finding)
+	for _, f := range result.Findings {
+		got[f.RuleID+":"+strconv.Itoa(f.Line)] = f
+	}
+	want := []string{
+		"go/xss:19",
+		"go/sql-injection:28",
+		"go/open-redirect:33",
+		"go/path-traversal:37",
+		"go/command-injection:41",
+		"custom/audit-log:42",
+	}
+	for _, key := range want {
+		if _, ok := got[key]; !ok {
+			t.Errorf("missing finding %s", key)
+		}
+	}
+	if len(result.Findings) != len(want) {
+		for _, f := range result.Findings {
+			t.Logf("%s:%d %s", f.RuleID, f.Line, f.Message)
+		}
+		t.Fatalf("got %d findings, want %d", len(result.Findings), len(want))
+	}
+
+	xss := got["go/xss:19"]
+	if cwes := xss.CWEs(); len(cwes) != 1 || cwes[0] != "CWE-79" {
+		t.Errorf("xss CWEs = %v", cwes)
+	}
+	steps := xss.CodeFlows[0].Steps
+	if len(steps) < 3 || steps[0].Line != 14 || !strings.Contains(steps[0].Message, "http.Request.URL") {
+		t.Errorf("xss flow does not start at the request field: %+v", steps)
+	}
+	if last := steps[len(steps)-1]; last.Line != 19 || last.Message != "sink: html/template.HTML" {
+		t.Errorf("xss flow does not end at the sink: %+v", last)
+	}
+
+	// The audit flow crosses into audit through its parameter
+	var crossed bool
+	for _, step := range got["go/path-traversal:37"].CodeFlows[0].Steps {
+		crossed = crossed || (step.Line == 36 && step.Message == "passed to main.audit as path")
+	}
+	if !crossed {
+		t.Errorf("path traversal flow misses the call: %+v", got["go/path-traversal:37"].CodeFlows)
+	}
+}
+
+func TestGoTaintRejectsSinkWithoutTargets(t *testing.T) {
+	cfg := policy.AnalyzerConfig{
+		Enabled: true,
+		Options: map[string]interface{}{
+			"sinks": []interface{}{map[string]interface{}{"rule_id": "custom/empty"}},
+		},
+	}
+	if _, err := newGoTaintAnalyzer(Env{}, cfg); err == nil {
+		t.Error("accepted a sink without calls or types")
+	}
+}
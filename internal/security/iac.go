@@ -0,0 +1,197 @@
+package security
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Siddhant-K-code/sentinel-ai/internal/iac"
+	"github.com/Siddhant-K-code/sentinel-ai/internal/policy"
+)
+
+func init() {
+	Register("iac", newIaCAnalyzer, true)
+}
+
+// DefaultIaCExclude are globs of files that are not deployed
+var DefaultIaCExclude = []string{
+	"**/testdata/**",
+	"**/node_modules/**",
+	"**/vendor/**",
+}
+
+// IaCOptions configures the infrastructure-as-code analyzer
+type IaCOptions struct {
+	// Rules are added to iac.DefaultRules
+	Rules []iac.Rule `yaml:"rules"`
+	// Disable holds IDs of rules not to apply
+	Disable []string `yaml:"disable"`
+	// Exclude holds globs of files not to scan; DefaultIaCExclude is used
+	// when empty
+	Exclude []string `yaml:"exclude"`
+}
+
+// iacAnalyzer checks Dockerfiles, Kubernetes manifests and Compose files
+// against declarative rules
+type iacAnalyzer struct {
+	env   Env
+	opts  IaCOptions
+	rules []iac.Rule
+}
+
+// newIaCAnalyzer creates the analyzer and compiles its rules
+func newIaCAnalyzer(env Env, cfg policy.AnalyzerConfig) (Analyzer, error) {
+	var opts IaCOptions
+	if err := cfg.Decode(&opts); err != nil {
+		return nil, err
+	}
+	if len(opts.Exclude) == 0 {
+		opts.Exclude = DefaultIaCExclude
+	}
+
+	disabled := make(map[string]bool, len(opts.Disable))
+	for _, id := range opts.Disable {
+		disabled[id] = true
+	}
+	var rules []iac.Rule
+	for _, rule := range append(append([]iac.Rule{}, iac.DefaultRules...), opts.Rules...) {
+		if disabled[rule.ID] {
+			continue
+		}
+		if err := rule.Compile(); err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return &iacAnalyzer{env: env, opts: opts, rules: rules}, nil
+}
+
+// Name returns the analyzer name
+func (a *iacAnalyzer) Name() string {
+	return "iac"
+}
+
+// Languages returns nil; manifests sit alongside any language
+func (a *iacAnalyzer) Languages() []string {
+	return nil
+}
+
+// Available always succeeds; the rules run in process
+func (a *iacAnalyzer) Available() error {
+	return nil
+}
+
+// Run checks every Dockerfile and YAML file in the workspace
+func (a *iacAnalyzer) Run(ctx context.Context) *ScanResult {
+	start := time.Now()
+	result := &ScanResult{Tool: "iac", Findings: []Finding{}}
+
+	root, err := a.env.Runner.WorkDir("")
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	maxBytes := a.env.Policy.Limits.MaxFileBytes
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil || rel == "." {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		if d.IsDir() {
+			if d.Name() == ".git" || a.skip(rel+"/") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		ext := strings.ToLower(filepath.Ext(rel))
+		isYAML := ext == ".yaml" || ext == ".yml"
+		if !d.Type().IsRegular() || (!isYAML && !iac.IsDockerfile(rel)) || a.skip(rel) {
+			return nil
+		}
+		if info, err := d.Info(); err != nil || (maxBytes > 0 && info.Size() > int64(maxBytes)) {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+
+		var results []iac.Result
+		if isYAML {
+			targets, err := iac.ParseManifests(data)
+			if err != nil && len(targets) == 0 {
+				// Templated YAML (Helm, Jinja) is not valid YAML; skip it
+				return nil
+			}
+			results = iac.CheckTargets(a.rules, targets)
+		} else {
+			results = iac.CheckDockerfile(a.rules, iac.ParseDockerfile(data))
+		}
+		for _, r := range results {
+			result.Findings = append(result.Findings, iacFinding(filepath.FromSlash(rel), r))
+		}
+		return nil
+	})
+	if err != nil {
+		result.Error = err.Error()
+	}
+
+	result.Duration = time.Since(start)
+	return result
+}
+
+// skip reports whether a workspace path is denied or excluded
+func (a *iacAnalyzer) skip(rel string) bool {
+	for _, glob := range a.env.Policy.Security.DenyGlobs {
+		if policy.MatchGlob(glob, rel) {
+			return true
+		}
+	}
+	for _, glob := range a.opts.Exclude {
+		if policy.MatchGlob(glob, rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// iacFinding converts a rule match into a finding
+func iacFinding(file string, r iac.Result) Finding {
+	rule := &RuleMetadata{
+		Name:             strings.TrimPrefix(r.Rule.ID, "iac/"),
+		ShortDescription: r.Rule.Message,
+		Help:             r.Rule.Description,
+		Tags:             []string{"iac"},
+	}
+	if r.Rule.CWE != "" {
+		rule.Tags = append(rule.Tags, r.Rule.CWE)
+	}
+
+	return Finding{
+		RuleID:      r.Rule.ID,
+		Message:     fmt.Sprintf("%s (%s)", r.Rule.Message, r.Subject),
+		Severity:    r.Rule.Severity,
+		File:        file,
+		Line:        r.Line,
+		Column:      r.Column,
+		Description: r.Rule.Description,
+		Confidence:  "high",
+		Tool:        "iac",
+		Rule:        rule,
+	}
+}
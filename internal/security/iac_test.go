@@ -0,0 +1,83 @@
+package security
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/Siddhant-K-code/sentinel-ai/internal/policy"
+	"github.com/Siddhant-K-code/sentinel-ai/internal/tools"
+)
+
+func TestIaCAnalyzer(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"Dockerfile":     "FROM alpine:latest\nRUN apk add curl\n",
+		"deploy/pod.yml": "apiVersion: v1\nkind: Pod\nmetadata:\n  name: app\nspec:\n  containers:\n    - name: app\n      image: app:1.0\n      resources:\n        limits: {memory: 64Mi}\n      securityContext:\n        capabilities:\n          add: [NET_ADMIN]\n",
+		// Helm templates are not valid YAML and are skipped
+		"chart/templates/deploy.yaml": "spec:\n  {{- if .Values.x }}\n  replicas: 1\n",
+		"testdata/Dockerfile":         "FROM alpine\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	env := Env{Runner: tools.NewRunner(root, nil, time.Second), Workspace: root, Policy: policy.DefaultPolicy()}
+	analyzer, err := newIaCAnalyzer(env, policy.AnalyzerConfig{Enabled: true, Options: map[string]interface{}{
+		"disable": []interface{}{"iac/dockerfile-root-user"},
+		"rules": []interface{}{map[string]interface{}{
+			"id":       "custom/net-admin",
+			"kind":     "container",
+			"message":  "Container adds NET_ADMIN",
+			"severity": "error",
+			"path":     "securityContext.capabilities.add[]",
+			"match":    "^NET_ADMIN$",
+		}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := analyzer.Run(context.Background())
+	if result.Error != "" {
+		t.Fatal(result.Error)
+	}
+
+	var got []string
+	for _, f := range result.Findings {
+		got = append(got, filepath.ToSlash(f.File)+":"+f.RuleID)
+	}
+	sort.Strings(got)
+	want := []string{"Dockerfile:iac/dockerfile-latest-tag", "deploy/pod.yml:custom/net-admin"}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Fatalf("Expected %v, got %v", want, got)
+	}
+
+	for _, f := range result.Findings {
+		if f.RuleID == "custom/net-admin" && (f.Line != 13 || f.Severity != "error" || f.Message != "Container adds NET_ADMIN (Pod/app container app)") {
+			t.Errorf("Unexpected custom finding %+v", f)
+		}
+		if f.RuleID == "iac/dockerfile-latest-tag" && (f.Line != 1 || f.Rule == nil || len(f.Rule.Tags) != 2 || f.Rule.Tags[1] != "CWE-1357") {
+			t.Errorf("Unexpected Dockerfile finding %+v", f)
+		}
+	}
+}
+
+func TestIaCAnalyzerRejectsInvalidRule(t *testing.T) {
+	env := Env{Policy: policy.DefaultPolicy()}
+	_, err := newIaCAnalyzer(env, policy.AnalyzerConfig{Enabled: true, Options: map[string]interface{}{
+		"rules": []interface{}{map[string]interface{}{"id": "bad", "kind": "container"}},
+	}})
+	if err == nil {
+		t.Fatal("Expected error for a rule without a path")
+	}
+}
@@ -0,0 +1,146 @@
+package security
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Siddhant-K-code/sentinel-ai/internal/policy"
+)
+
+func init() {
+	Register("sarif", newSARIFAnalyzer, false)
+}
+
+// SARIFSource is one third-party tool whose SARIF output is ingested.
+// With Command set the command is run (it must be allowlisted) and its
+// stdout is parsed, unless File is also set, in which case File is read
+// after the command finishes. With only File set the file is read as is.
+type SARIFSource struct {
+	Name    string   `yaml:"name"`
+	Command []string `yaml:"command"`
+	File    string   `yaml:"file"`
+}
+
+// SARIFOptions configures the generic SARIF analyzer
+type SARIFOptions struct {
+	Sources []SARIFSource `yaml:"sources"`
+}
+
+// sarifAnalyzer ingests SARIF from tools such as gosec, trivy, eslint or bandit
+type sarifAnalyzer struct {
+	env  Env
+	opts SARIFOptions
+}
+
+// newSARIFAnalyzer creates the SARIF analyzer from its policy options
+func newSARIFAnalyzer(env Env, cfg policy.AnalyzerConfig) (Analyzer, error) {
+	var opts SARIFOptions
+	if err := cfg.Decode(&opts); err != nil {
+		return nil, err
+	}
+	for i, src := range opts.Sources {
+		if len(src.Command) == 0 && src.File == "" {
+			return nil, fmt.Errorf("sarif source %d: command or file is required", i)
+		}
+	}
+	return &sarifAnalyzer{env: env, opts: opts}, nil
+}
+
+// Name returns the analyzer name
+func (a *sarifAnalyzer) Name() string {
+	return "sarif"
+}
+
+// Languages returns nil; the wrapped tools decide what they understand
+func (a *sarifAnalyzer) Languages() []string {
+	return nil
+}
+
+// Available checks that at least one source is configured
+func (a *sarifAnalyzer) Available() error {
+	if len(a.opts.Sources) == 0 {
+		return errors.New("no SARIF sources configured")
+	}
+	return nil
+}
+
+// Run collects findings from every source. A failing source is reported
+// in the result error without discarding the others.
+func (a *sarifAnalyzer) Run(ctx context.Context) *ScanResult {
+	start := time.Now()
+
+	findings := []Finding{}
+	var errs []string
+	for _, src := range a.opts.Sources {
+		name := src.label()
+
+		data, err := a.read(ctx, src)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", name, err))
+			continue
+		}
+
+		root, _ := a.env.Runner.WorkDir("")
+		srcFindings, err := parseSARIF(data, root)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", name, err))
+			continue
+		}
+
+		for i := range srcFindings {
+			if srcFindings[i].Tool == "" {
+				srcFindings[i].Tool = name
+			}
+		}
+		findings = append(findings, srcFindings...)
+	}
+
+	return &ScanResult{
+		Tool:     "sarif",
+		Findings: findings,
+		Duration: time.Since(start),
+		Error:    strings.Join(errs, "; "),
+	}
+}
+
+// read returns the SARIF document for a source
+func (a *sarifAnalyzer) read(ctx context.Context, src SARIFSource) ([]byte, error) {
+	if len(src.Command) > 0 {
+		result := a.env.Runner.Run(ctx, src.Command[0], src.Command[1:]...)
+
+		// Scanners commonly exit non-zero when they report findings, so
+		// only failures to run the command at all are fatal
+		if result.Error != nil && !result.Exited {
+			return nil, result.Error
+		}
+		if src.File == "" {
+			if len(result.Stdout) == 0 && result.Error != nil {
+				return nil, fmt.Errorf("%v: %s", result.Error, tail(result.Stderr))
+			}
+			return result.Stdout, nil
+		}
+	}
+
+	dir, err := a.env.Runner.WorkDir(filepath.Dir(src.File))
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(filepath.Join(dir, filepath.Base(src.File)))
+}
+
+// label names a source for errors and findings without a driver name
+func (s SARIFSource) label() string {
+	switch {
+	case s.Name != "":
+		return s.Name
+	case len(s.Command) > 0:
+		return s.Command[0]
+	default:
+		return filepath.Base(s.File)
+	}
+}
@@ -0,0 +1,145 @@
+package security
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Siddhant-K-code/sentinel-ai/internal/cache"
+	"github.com/Siddhant-K-code/sentinel-ai/internal/policy"
+	"github.com/Siddhant-K-code/sentinel-ai/internal/tools"
+)
+
+func TestSARIFAnalyzerFile(t *testing.T) {
+	workspace := t.TempDir()
+	data, err := os.ReadFile("testdata/gosec.sarif")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(workspace, "reports"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(workspace, "reports", "gosec.sarif"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	runner := tools.NewRunner(workspace, nil, 10*time.Second)
+	analyzer, err := newSARIFAnalyzer(Env{Runner: runner, Workspace: workspace}, policy.AnalyzerConfig{
+		Enabled: true,
+		Options: map[string]interface{}{
+			"sources": []interface{}{
+				map[string]interface{}{"file": "reports/gosec.sarif"},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := analyzer.Run(context.Background())
+	if result.Error != "" {
+		t.Fatalf("Expected ingestion to succeed: %s", result.Error)
+	}
+	if len(result.Findings) != 1 {
+		t.Fatalf("Expected 1 finding, got %d", len(result.Findings))
+	}
+
+	finding := result.Findings[0]
+	if finding.Tool != "gosec" || finding.RuleID != "G203" {
+		t.Errorf("Expected gosec G203, got %s %s", finding.Tool, finding.RuleID)
+	}
+	if finding.File != "example/main.go" || finding.Line != 23 {
+		t.Errorf("Unexpected location %s:%d", finding.File, finding.Line)
+	}
+	if finding.Severity != "warning" {
+		t.Errorf("Expected default level warning, got %s", finding.Severity)
+	}
+	if finding.Rule == nil || finding.Rule.Name != "TemplateUnescaped" || len(finding.Rule.Tags) != 2 {
+		t.Errorf("Expected rule metadata, got %+v", finding.Rule)
+	}
+	if finding.Fingerprints["primaryLocationLineHash"] != "4d3f2a1b:1" {
+		t.Errorf("Expected partial fingerprint, got %v", finding.Fingerprints)
+	}
+}
+
+func TestSARIFAnalyzerCommand(t *testing.T) {
+	workspace := t.TempDir()
+	sarif, err := filepath.Abs("testdata/gosec.sarif")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	runner := tools.NewRunner(workspace, [][]string{{"cat", "*"}}, 10*time.Second)
+	analyzer, err := newSARIFAnalyzer(Env{Runner: runner, Workspace: workspace}, policy.AnalyzerConfig{
+		Enabled: true,
+		Options: map[string]interface{}{
+			"sources": []interface{}{
+				map[string]interface{}{"name": "gosec", "command": []interface{}{"cat", sarif}},
+				map[string]interface{}{"name": "trivy", "command": []interface{}{"trivy", "fs", "."}},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := analyzer.Run(context.Background())
+	if len(result.Findings) != 1 {
+		t.Errorf("Expected gosec finding to survive trivy failure, got %d", len(result.Findings))
+	}
+	if result.Error == "" {
+		t.Error("Expected non-allowlisted trivy command to be reported")
+	}
+}
+
+func TestSARIFAnalyzerCommandExitCached(t *testing.T) {
+	workspace := t.TempDir()
+	sarif, err := filepath.Abs("testdata/gosec.sarif")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := cache.Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Like gosec, the command exits 1 because it found something
+	script := "cat '" + sarif + "'; exit 1"
+	runner := tools.NewRunner(workspace, [][]string{{"sh", "-c", "*"}}, 10*time.Second)
+	runner.EnableCache(c)
+	analyzer, err := newSARIFAnalyzer(Env{Runner: runner, Workspace: workspace}, policy.AnalyzerConfig{
+		Enabled: true,
+		Options: map[string]interface{}{
+			"sources": []interface{}{
+				map[string]interface{}{"name": "gosec", "command": []interface{}{"sh", "-c", script}},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, run := range []string{"first", "cached"} {
+		result := analyzer.Run(context.Background())
+		if result.Error != "" || len(result.Findings) != 1 {
+			t.Errorf("%s run: expected 1 finding, got %d (error %q)", run, len(result.Findings), result.Error)
+		}
+	}
+	if cached := runner.Run(context.Background(), "sh", "-c", script); !cached.Cached || !cached.Exited || cached.ExitCode != 1 {
+		t.Errorf("Expected a cached exit 1, got %+v", cached)
+	}
+}
+
+func TestSARIFAnalyzerRequiresInput(t *testing.T) {
+	_, err := newSARIFAnalyzer(Env{}, policy.AnalyzerConfig{
+		Enabled: true,
+		Options: map[string]interface{}{
+			"sources": []interface{}{map[string]interface{}{"name": "empty"}},
+		},
+	})
+	if err == nil {
+		t.Error("Expected source without command or file to be rejected")
+	}
+}
@@ -0,0 +1,183 @@
+package security
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Siddhant-K-code/sentinel-ai/internal/policy"
+	"github.com/Siddhant-K-code/sentinel-ai/internal/vulndb"
+)
+
+func init() {
+	Register("osv", newOSVAnalyzer, false)
+}
+
+// advisoryKey is the fingerprint identifying an advisory for a package,
+// so each advisory stays a separate finding on a shared go.mod line
+const advisoryKey = "advisory/v1"
+
+// OSVOptions configures the dependency vulnerability analyzer
+type OSVOptions struct {
+	// DBDir is the database written by 'sentinel-ai vulndb import'
+	// (default: vulndb under the user cache directory)
+	DBDir string `yaml:"db_dir"`
+	// Reachability is "callgraph" (default) to check whether Go code calls
+	// the affected symbols, or "off"
+	Reachability string `yaml:"reachability"`
+}
+
+// osvAnalyzer matches dependency versions against a local OSV database
+type osvAnalyzer struct {
+	env  Env
+	opts OSVOptions
+	db   *vulndb.DB
+	err  error
+}
+
+// newOSVAnalyzer creates the dependency analyzer from its policy options
+func newOSVAnalyzer(env Env, cfg policy.AnalyzerConfig) (Analyzer, error) {
+	var opts OSVOptions
+	if err := cfg.Decode(&opts); err != nil {
+		return nil, err
+	}
+	switch opts.Reachability {
+	case "":
+		opts.Reachability = "callgraph"
+	case "callgraph", "off":
+	default:
+		return nil, fmt.Errorf("reachability: unknown mode %q", opts.Reachability)
+	}
+	a := &osvAnalyzer{env: env, opts: opts}
+	a.db, a.err = vulndb.Open(opts.DBDir)
+	return a, nil
+}
+
+// Name returns the analyzer name
+func (a *osvAnalyzer) Name() string {
+	return "osv"
+}
+
+// Languages returns nil; the analyzer reads manifests, not source
+func (a *osvAnalyzer) Languages() []string {
+	return nil
+}
+
+// Available reports whether a database has been imported
+func (a *osvAnalyzer) Available() error {
+	return a.err
+}
+
+// Run reports every dependency version affected by an advisory
+func (a *osvAnalyzer) Run(ctx context.Context) *ScanResult {
+	start := time.Now()
+	result := &ScanResult{Tool: "osv", Findings: []Finding{}}
+
+	root, err := a.env.Runner.WorkDir("")
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	deps, err := vulndb.Dependencies(root)
+	if err != nil {
+		result.Error = err.Error()
+	}
+	var symbolic []symbolFinding
+	for _, dep := range deps {
+		if err := ctx.Err(); err != nil {
+			result.Error = err.Error()
+			break
+		}
+		matches, err := a.db.Lookup(dep.Ecosystem, dep.Name, dep.Version)
+		if err != nil {
+			result.Error = err.Error()
+			break
+		}
+		for _, m := range matches {
+			if dep.Ecosystem == vulndb.EcosystemGo && len(m.Imports) > 0 {
+				symbolic = append(symbolic, symbolFinding{index: len(result.Findings), imports: m.Imports})
+			}
+			result.Findings = append(result.Findings, advisoryFinding(dep, m))
+		}
+	}
+
+	if len(symbolic) > 0 && a.opts.Reachability != "off" && result.Error == "" {
+		a.checkReachability(ctx, result.Findings, symbolic)
+	}
+
+	result.Duration = time.Since(start)
+	return result
+}
+
+// advisoryFinding describes a dependency affected by an advisory
+func advisoryFinding(dep vulndb.Dependency, m vulndb.Match) Finding {
+	entry := m.Entry
+	ids := entry.IDs()
+
+	message := fmt.Sprintf("%s@%s is affected by %s", dep.Name, dep.Version, strings.Join(ids, ", "))
+	if entry.Summary != "" {
+		message += ": " + entry.Summary
+	}
+	if m.Fixed != "" {
+		message += fmt.Sprintf(" (fixed in %s)", m.Fixed)
+	} else {
+		message += " (no fixed version)"
+	}
+	confidence := "high"
+	if dep.Inferred {
+		// go.sum also hashes versions the build does not use
+		confidence = "low"
+		message += "; the version is inferred from " + dep.File + " and may not be the one built"
+	}
+
+	severity := entry.SeverityLevel()
+	if severity == "" {
+		severity = "warning"
+	}
+
+	rule := &RuleMetadata{
+		Name:             entry.ID,
+		ShortDescription: entry.Summary,
+		Help:             entry.Details,
+		Tags:             append(append([]string{}, ids[1:]...), entry.DatabaseSpecific.CWEIDs...),
+		Properties: map[string]interface{}{
+			"ecosystem": dep.Ecosystem,
+			"package":   dep.Name,
+			"version":   dep.Version,
+		},
+	}
+	if m.Fixed != "" {
+		rule.Properties["fixed_version"] = m.Fixed
+	}
+	for _, ref := range entry.References {
+		if rule.HelpURI == "" || ref.Type == "ADVISORY" {
+			rule.HelpURI = ref.URL
+			if ref.Type == "ADVISORY" {
+				break
+			}
+		}
+	}
+
+	var class *Classification
+	if score := entry.CVSSScore(); score > 0 {
+		class = &Classification{CVSS: score}
+	}
+
+	return Finding{
+		RuleID:         entry.ID,
+		Message:        message,
+		Severity:       severity,
+		File:           filepath.FromSlash(dep.File),
+		Line:           dep.Line,
+		Column:         1,
+		Description:    entry.Details,
+		Confidence:     confidence,
+		Tool:           "osv",
+		Rule:           rule,
+		Fingerprints:   map[string]string{advisoryKey: entry.ID + " " + dep.Ecosystem + "/" + dep.Name},
+		Classification: class,
+	}
+}
@@ -0,0 +1,125 @@
+package security
+
+import (
+	"context"
+	"fmt"
+
+	"golang.org/x/tools/go/ssa"
+
+	"github.com/Siddhant-K-code/sentinel-ai/internal/goanalysis"
+	"github.com/Siddhant-K-code/sentinel-ai/internal/vulndb"
+)
+
+// unreachableSeverity replaces the severity of advisories whose affected
+// symbols the workspace never calls
+const unreachableSeverity = "info"
+
+// symbolFinding is an advisory finding that lists affected Go symbols
+type symbolFinding struct {
+	index   int
+	imports []vulndb.AffectedImport
+}
+
+// checkReachability builds the workspace call graph and records, for each
+// finding, whether an affected symbol is called and a sample call path.
+// Unreachable findings are downgraded; when the code cannot be loaded the
+// findings are left as they are and marked unknown.
+func (a *osvAnalyzer) checkReachability(ctx context.Context, findings []Finding, pending []symbolFinding) {
+	opts := goanalysis.Options{Network: a.env.Policy.Modes["default"].Network}
+	prog, err := a.env.LoadGo(ctx, opts)
+	if err == nil && len(prog.Errors()) > 0 {
+		err = fmt.Errorf("type errors: %v", prog.Errors()[0])
+	}
+	if err != nil {
+		for _, p := range pending {
+			props := findings[p.index].Rule.Properties
+			props["reachable"] = "unknown"
+			props["reachability_error"] = err.Error()
+		}
+		return
+	}
+
+	reach := goanalysis.Reach(prog.CallGraph(), prog.EntryPoints())
+
+	// First reached function for each symbol and for each package
+	reached := make(map[string]*ssa.Function)
+	for _, fn := range reach.Functions() {
+		if prog.InWorkspace(fn) {
+			continue
+		}
+		pkg, name := goanalysis.SymbolName(fn)
+		for _, key := range []string{pkg + "." + name, pkg} {
+			if _, ok := reached[key]; !ok {
+				reached[key] = fn
+			}
+		}
+	}
+
+	for _, p := range pending {
+		f := &findings[p.index]
+		fn, symbol := reachedSymbol(reached, p.imports)
+		if fn == nil {
+			f.Rule.Properties["reachable"] = false
+			f.Rule.Properties["original_severity"] = f.Severity
+			f.Severity = unreachableSeverity
+			f.Message += "; affected symbols are not called"
+			continue
+		}
+
+		f.Rule.Properties["reachable"] = true
+		f.Rule.Properties["reachable_symbol"] = symbol
+		f.Message += fmt.Sprintf("; %s is called", symbol)
+		if flow := callFlow(prog, reach, fn); len(flow.Steps) > 0 {
+			f.CodeFlows = []CodeFlow{flow}
+		}
+	}
+}
+
+// reachedSymbol returns the first affected symbol that is called and its
+// qualified name. An import without symbols matches any function of its
+// package.
+func reachedSymbol(reached map[string]*ssa.Function, imports []vulndb.AffectedImport) (*ssa.Function, string) {
+	for _, imp := range imports {
+		if len(imp.Symbols) == 0 {
+			if fn := reached[imp.Path]; fn != nil {
+				_, name := goanalysis.SymbolName(fn)
+				return fn, imp.Path + "." + name
+			}
+			continue
+		}
+		for _, symbol := range imp.Symbols {
+			if fn := reached[imp.Path+"."+symbol]; fn != nil {
+				return fn, imp.Path + "." + symbol
+			}
+		}
+	}
+	return nil, ""
+}
+
+// callFlow renders the call path to fn as a code flow with one step per
+// call site, starting in workspace code
+func callFlow(prog *goanalysis.Program, reach *goanalysis.Reachability, fn *ssa.Function) CodeFlow {
+	var flow CodeFlow
+	for _, edge := range reach.Path(fn) {
+		pos := prog.Fset.Position(edge.Pos())
+		if !pos.IsValid() {
+			continue
+		}
+		flow.Steps = append(flow.Steps, FlowStep{
+			File:    prog.RelPath(pos.Filename),
+			Line:    pos.Line,
+			Column:  pos.Column,
+			Message: fmt.Sprintf("%s calls %s", shortFuncName(edge.Caller.Func), shortFuncName(edge.Callee.Func)),
+		})
+	}
+	return flow
+}
+
+// shortFuncName names a function by its package name rather than path
+func shortFuncName(fn *ssa.Function) string {
+	_, name := goanalysis.SymbolName(fn)
+	if fn.Pkg == nil {
+		return name
+	}
+	return fn.Pkg.Pkg.Name() + "." + name
+}
@@ -0,0 +1,197 @@
+package security
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Siddhant-K-code/sentinel-ai/internal/goanalysis"
+	"github.com/Siddhant-K-code/sentinel-ai/internal/policy"
+	"github.com/Siddhant-K-code/sentinel-ai/internal/tools"
+	"github.com/Siddhant-K-code/sentinel-ai/internal/vulndb"
+)
+
+func TestOSVAnalyzer(t *testing.T) {
+	src := t.TempDir()
+	advisory := `{
+  "id": "GO-2024-0001",
+  "aliases": ["CVE-2024-1234", "GHSA-wxyz-1234-abcd"],
+  "summary": "Denial of service in example.com/web",
+  "modified": "2024-01-01T00:00:00Z",
+  "affected": [{
+    "package": {"ecosystem": "Go", "name": "example.com/web"},
+    "ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}, {"fixed": "1.3.0"}]}]
+  }],
+  "references": [{"type": "WEB", "url": "https://example.com/issue"}, {"type": "ADVISORY", "url": "https://example.com/advisory"}],
+  "database_specific": {"severity": "MODERATE", "cwe_ids": ["CWE-400"]}
+}`
+	if err := os.WriteFile(filepath.Join(src, "GO-2024-0001.json"), []byte(advisory), 0644); err != nil {
+		t.Fatal(err)
+	}
+	dbDir := filepath.Join(t.TempDir(), "vulndb")
+	if _, err := vulndb.Import(src, dbDir); err != nil {
+		t.Fatal(err)
+	}
+
+	root := t.TempDir()
+	gomod := "module example.com/app\n\ngo 1.21\n\nrequire example.com/web v1.2.0\n"
+	if err := os.WriteFile(filepath.Join(root, "go.mod"), []byte(gomod), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	env := Env{Runner: tools.NewRunner(root, nil, time.Second), Workspace: root, Policy: policy.DefaultPolicy()}
+	cfg := policy.AnalyzerConfig{
+		Enabled: true,
+		Options: map[string]interface{}{"db_dir": dbDir},
+	}
+	analyzer, err := newOSVAnalyzer(env, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := analyzer.Available(); err != nil {
+		t.Fatal(err)
+	}
+
+	result := analyzer.Run(context.Background())
+	if result.Error != "" {
+		t.Fatal(result.Error)
+	}
+	if len(result.Findings) != 1 {
+		t.Fatalf("got %d findings, want 1: %+v", len(result.Findings), result.Findings)
+	}
+
+	f := result.Findings[0]
+	if f.RuleID != "GO-2024-0001" || f.File != "go.mod" || f.Line != 5 || f.Severity != "medium" {
+		t.Errorf("unexpected finding %+v", f)
+	}
+	for _, want := range []string{"example.com/web@v1.2.0", "CVE-2024-1234", "GHSA-wxyz-1234-abcd", "fixed in 1.3.0"} {
+		if !strings.Contains(f.Message, want) {
+			t.Errorf("message %q does not mention %s", f.Message, want)
+		}
+	}
+	if cwes := f.CWEs(); len(cwes) != 1 || cwes[0] != "CWE-400" {
+		t.Errorf("CWEs = %v", cwes)
+	}
+	if f.Rule.HelpURI != "https://example.com/advisory" || f.Rule.Properties["fixed_version"] != "1.3.0" {
+		t.Errorf("unexpected rule %+v", f.Rule)
+	}
+
+	// A version only guessed from go.sum is reported with low confidence
+	gomod = "module example.com/app\n\ngo 1.16\n"
+	gosum := "example.com/web v1.2.0 h1:aaa=\nexample.com/web v1.2.0/go.mod h1:bbb=\n"
+	if err := os.WriteFile(filepath.Join(root, "go.mod"), []byte(gomod), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "go.sum"), []byte(gosum), 0644); err != nil {
+		t.Fatal(err)
+	}
+	result = analyzer.Run(context.Background())
+	if len(result.Findings) != 1 {
+		t.Fatalf("got %d findings, want 1: %+v", len(result.Findings), result.Findings)
+	}
+	if f := result.Findings[0]; f.File != "go.sum" || f.Line != 1 || f.Confidence != "low" {
+		t.Errorf("unexpected go.sum finding %+v", f)
+	}
+}
+
+func TestOSVAnalyzerWithoutDatabase(t *testing.T) {
+	root := t.TempDir()
+	env := Env{Runner: tools.NewRunner(root, nil, time.Second), Workspace: root, Policy: policy.DefaultPolicy()}
+	cfg := policy.AnalyzerConfig{
+		Enabled: true,
+		Options: map[string]interface{}{"db_dir": filepath.Join(root, "missing")},
+	}
+	analyzer, err := newOSVAnalyzer(env, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := analyzer.Available(); err == nil || !strings.Contains(err.Error(), "vulndb import") {
+		t.Errorf("Available() = %v, want a hint to import the database", err)
+	}
+}
+
+func TestOSVReachability(t *testing.T) {
+	src := t.TempDir()
+	advisories := map[string]string{
+		"GO-2024-0002": "Parse",
+		"GO-2024-0003": "Render",
+	}
+	for id, symbol := range advisories {
+		advisory := `{
+  "id": "` + id + `",
+  "modified": "2024-01-01T00:00:00Z",
+  "affected": [{
+    "package": {"ecosystem": "Go", "name": "example.com/web"},
+    "ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}, {"fixed": "1.3.0"}]}],
+    "ecosystem_specific": {"imports": [{"path": "example.com/web", "symbols": ["` + symbol + `"]}]}
+  }],
+  "database_specific": {"severity": "HIGH"}
+}`
+		if err := os.WriteFile(filepath.Join(src, id+".json"), []byte(advisory), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	dbDir := filepath.Join(t.TempDir(), "vulndb")
+	if _, err := vulndb.Import(src, dbDir); err != nil {
+		t.Fatal(err)
+	}
+
+	// The dependency is vendored so that loading needs no network
+	t.Setenv("GOFLAGS", "-mod=vendor")
+	root := t.TempDir()
+	files := map[string]string{
+		"go.mod":                        "module example.com/app\n\ngo 1.22\n\nrequire example.com/web v1.2.0\n",
+		"vendor/modules.txt":            "# example.com/web v1.2.0\n## explicit\nexample.com/web\n",
+		"vendor/example.com/web/web.go": "package web\n\nfunc Parse(s string) string { return s }\n\nfunc Render(s string) string { return s }\n",
+		"main.go":                       "package main\n\nimport \"example.com/web\"\n\nfunc handle(s string) string {\n\treturn web.Parse(s)\n}\n\nfunc main() { println(handle(\"x\")) }\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	runner := tools.NewRunner(root, [][]string{goanalysis.ListCommand}, time.Minute)
+	env := Env{Runner: runner, Workspace: root, Policy: policy.DefaultPolicy()}
+	cfg := policy.AnalyzerConfig{
+		Enabled: true,
+		Options: map[string]interface{}{"db_dir": dbDir},
+	}
+	analyzer, err := newOSVAnalyzer(env, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := analyzer.Run(context.Background())
+	if result.Error != "" {
+		t.Fatal(result.Error)
+	}
+	got := make(map[string]Finding)
+	for _, f := range result.Findings {
+		got[f.RuleID] = f
+	}
+
+	called := got["GO-2024-0002"]
+	if called.Severity != "high" || called.Rule.Properties["reachable"] != true {
+		t.Errorf("called advisory: severity %q, properties %v", called.Severity, called.Rule.Properties)
+	}
+	if len(called.CodeFlows) != 1 {
+		t.Fatalf("called advisory has %d code flows, want 1", len(called.CodeFlows))
+	}
+	steps := called.CodeFlows[0].Steps
+	if len(steps) != 2 || steps[0].File != "main.go" || steps[1].Line != 6 || steps[0].Message != "main.main calls main.handle" || steps[1].Message != "main.handle calls web.Parse" {
+		t.Errorf("unexpected call path %+v", steps)
+	}
+
+	uncalled := got["GO-2024-0003"]
+	if uncalled.Severity != unreachableSeverity || uncalled.Rule.Properties["reachable"] != false {
+		t.Errorf("uncalled advisory: severity %q, properties %v", uncalled.Severity, uncalled.Rule.Properties)
+	}
+}
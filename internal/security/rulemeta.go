@@ -0,0 +1,215 @@
+package security
+
+import (
+	"path"
+	"regexp"
+	"strings"
+)
+
+// RuleInfo is offline metadata about a rule, used to classify findings
+// whose tool does not report it
+type RuleInfo struct {
+	CWE   []string
+	OWASP []string
+	// CVSS is a representative base score for the rule's findings
+	CVSS float64
+}
+
+// OWASP Top 10 2021 categories
+const (
+	owaspAccessControl        = "A01:2021-Broken Access Control"
+	owaspCryptographic        = "A02:2021-Cryptographic Failures"
+	owaspInjection            = "A03:2021-Injection"
+	owaspInsecureDesign       = "A04:2021-Insecure Design"
+	owaspMisconfiguration     = "A05:2021-Security Misconfiguration"
+	owaspVulnerableComponents = "A06:2021-Vulnerable and Outdated Components"
+	owaspAuthentication       = "A07:2021-Identification and Authentication Failures"
+	owaspIntegrity            = "A08:2021-Software and Data Integrity Failures"
+	owaspLogging              = "A09:2021-Security Logging and Monitoring Failures"
+	owaspSSRF                 = "A10:2021-Server-Side Request Forgery"
+)
+
+// owasp2021 indexes the 2021 categories by their "A03" code
+var owasp2021 = map[string]string{
+	"A01": owaspAccessControl,
+	"A02": owaspCryptographic,
+	"A03": owaspInjection,
+	"A04": owaspInsecureDesign,
+	"A05": owaspMisconfiguration,
+	"A06": owaspVulnerableComponents,
+	"A07": owaspAuthentication,
+	"A08": owaspIntegrity,
+	"A09": owaspLogging,
+	"A10": owaspSSRF,
+}
+
+// ruleInfo is the bundled metadata for the built-in analyzers' rules and
+// common CodeQL queries, keyed by rule ID or a path.Match pattern. Scores
+// follow the security-severity CodeQL publishes for the same weakness.
+var ruleInfo = map[string]RuleInfo{
+	// gotaint, and the CodeQL queries for the same flows
+	"go/xss":                         {CWE: []string{"CWE-79"}, CVSS: 6.1},
+	"go/reflected-xss":               {CWE: []string{"CWE-79"}, CVSS: 6.1},
+	"go/command-injection":           {CWE: []string{"CWE-78"}, CVSS: 9.8},
+	"go/sql-injection":               {CWE: []string{"CWE-89"}, CVSS: 8.8},
+	"go/path-traversal":              {CWE: []string{"CWE-22"}, CVSS: 7.5},
+	"go/path-injection":              {CWE: []string{"CWE-22"}, CVSS: 7.5},
+	"go/open-redirect":               {CWE: []string{"CWE-601"}, CVSS: 6.1},
+	"go/unvalidated-url-redirection": {CWE: []string{"CWE-601"}, CVSS: 6.1},
+	"go/request-forgery":             {CWE: []string{"CWE-918"}, CVSS: 9.1},
+	"go/unclosed-rows":               {CWE: []string{"CWE-404"}},
+
+	// gocrypto
+	"go/weak-hash":                    {CWE: []string{"CWE-328"}, CVSS: 7.5},
+	"go/insecure-random":              {CWE: []string{"CWE-338"}, CVSS: 7.8},
+	"go/tls-insecure-skip-verify":     {CWE: []string{"CWE-295"}, CVSS: 7.5},
+	"go/disabled-certificate-check":   {CWE: []string{"CWE-295"}, CVSS: 7.5},
+	"go/tls-min-version":              {CWE: []string{"CWE-327"}, CVSS: 7.5},
+	"go/insecure-tls":                 {CWE: []string{"CWE-327"}, CVSS: 7.5},
+	"go/hardcoded-key":                {CWE: []string{"CWE-321"}, CVSS: 9.8},
+	"go/hardcoded-iv":                 {CWE: []string{"CWE-329"}, CVSS: 7.5},
+	"go/weak-rsa-key":                 {CWE: []string{"CWE-326"}, CVSS: 7.5},
+	"go/weak-crypto-key":              {CWE: []string{"CWE-326"}, CVSS: 7.5},
+	"go/weak-cryptographic-algorithm": {CWE: []string{"CWE-327"}, CVSS: 7.5},
+	"go/hardcoded-credentials":        {CWE: []string{"CWE-798"}, CVSS: 9.8},
+	"go/clear-text-logging":           {CWE: []string{"CWE-312"}, CVSS: 7.5},
+
+	// secrets
+	"secrets/*": {CWE: []string{"CWE-798"}, CVSS: 9.8},
+
+	// iac rules are configuration weaknesses whatever their CWE
+	"iac/*": {OWASP: []string{owaspMisconfiguration}},
+
+	// Common CodeQL queries for other languages
+	"js/xss":                      {CWE: []string{"CWE-79"}, CVSS: 6.1},
+	"js/reflected-xss":            {CWE: []string{"CWE-79"}, CVSS: 6.1},
+	"js/sql-injection":            {CWE: []string{"CWE-89"}, CVSS: 8.8},
+	"js/command-line-injection":   {CWE: []string{"CWE-78"}, CVSS: 9.8},
+	"js/path-injection":           {CWE: []string{"CWE-22"}, CVSS: 7.5},
+	"js/request-forgery":          {CWE: []string{"CWE-918"}, CVSS: 9.1},
+	"js/prototype-pollution":      {CWE: []string{"CWE-1321"}, CVSS: 6.1},
+	"py/sql-injection":            {CWE: []string{"CWE-89"}, CVSS: 8.8},
+	"py/command-line-injection":   {CWE: []string{"CWE-78"}, CVSS: 9.8},
+	"py/path-injection":           {CWE: []string{"CWE-22"}, CVSS: 7.5},
+	"py/reflective-xss":           {CWE: []string{"CWE-79"}, CVSS: 6.1},
+	"py/unsafe-deserialization":   {CWE: []string{"CWE-502"}, CVSS: 9.8},
+	"java/sql-injection":          {CWE: []string{"CWE-89"}, CVSS: 8.8},
+	"java/command-line-injection": {CWE: []string{"CWE-78"}, CVSS: 9.8},
+	"java/xss":                    {CWE: []string{"CWE-79"}, CVSS: 6.1},
+	"java/path-injection":         {CWE: []string{"CWE-22"}, CVSS: 7.5},
+	"java/unsafe-deserialization": {CWE: []string{"CWE-502"}, CVSS: 9.8},
+}
+
+// cweOWASP maps CWEs to the OWASP Top 10 2021 category that lists them
+var cweOWASP = map[string]string{
+	"CWE-22":   owaspAccessControl,
+	"CWE-23":   owaspAccessControl,
+	"CWE-59":   owaspAccessControl,
+	"CWE-200":  owaspAccessControl,
+	"CWE-276":  owaspAccessControl,
+	"CWE-284":  owaspAccessControl,
+	"CWE-285":  owaspAccessControl,
+	"CWE-352":  owaspAccessControl,
+	"CWE-601":  owaspAccessControl,
+	"CWE-639":  owaspAccessControl,
+	"CWE-668":  owaspAccessControl,
+	"CWE-862":  owaspAccessControl,
+	"CWE-863":  owaspAccessControl,
+	"CWE-261":  owaspCryptographic,
+	"CWE-310":  owaspCryptographic,
+	"CWE-319":  owaspCryptographic,
+	"CWE-321":  owaspCryptographic,
+	"CWE-326":  owaspCryptographic,
+	"CWE-327":  owaspCryptographic,
+	"CWE-328":  owaspCryptographic,
+	"CWE-329":  owaspCryptographic,
+	"CWE-330":  owaspCryptographic,
+	"CWE-331":  owaspCryptographic,
+	"CWE-338":  owaspCryptographic,
+	"CWE-347":  owaspCryptographic,
+	"CWE-759":  owaspCryptographic,
+	"CWE-760":  owaspCryptographic,
+	"CWE-916":  owaspCryptographic,
+	"CWE-20":   owaspInjection,
+	"CWE-74":   owaspInjection,
+	"CWE-77":   owaspInjection,
+	"CWE-78":   owaspInjection,
+	"CWE-79":   owaspInjection,
+	"CWE-88":   owaspInjection,
+	"CWE-89":   owaspInjection,
+	"CWE-90":   owaspInjection,
+	"CWE-91":   owaspInjection,
+	"CWE-94":   owaspInjection,
+	"CWE-95":   owaspInjection,
+	"CWE-113":  owaspInjection,
+	"CWE-116":  owaspInjection,
+	"CWE-643":  owaspInjection,
+	"CWE-917":  owaspInjection,
+	"CWE-209":  owaspInsecureDesign,
+	"CWE-256":  owaspInsecureDesign,
+	"CWE-269":  owaspInsecureDesign,
+	"CWE-312":  owaspInsecureDesign,
+	"CWE-434":  owaspInsecureDesign,
+	"CWE-522":  owaspInsecureDesign,
+	"CWE-16":   owaspMisconfiguration,
+	"CWE-611":  owaspMisconfiguration,
+	"CWE-614":  owaspMisconfiguration,
+	"CWE-942":  owaspMisconfiguration,
+	"CWE-1004": owaspMisconfiguration,
+	"CWE-1104": owaspVulnerableComponents,
+	"CWE-259":  owaspAuthentication,
+	"CWE-287":  owaspAuthentication,
+	"CWE-295":  owaspAuthentication,
+	"CWE-306":  owaspAuthentication,
+	"CWE-307":  owaspAuthentication,
+	"CWE-384":  owaspAuthentication,
+	"CWE-521":  owaspAuthentication,
+	"CWE-613":  owaspAuthentication,
+	"CWE-798":  owaspAuthentication,
+	"CWE-345":  owaspIntegrity,
+	"CWE-494":  owaspIntegrity,
+	"CWE-502":  owaspIntegrity,
+	"CWE-829":  owaspIntegrity,
+	"CWE-915":  owaspIntegrity,
+	"CWE-117":  owaspLogging,
+	"CWE-532":  owaspLogging,
+	"CWE-778":  owaspLogging,
+	"CWE-918":  owaspSSRF,
+}
+
+// owaspPattern matches OWASP Top 10 references such as semgrep's
+// "A03:2021 - Injection"
+var owaspPattern = regexp.MustCompile(`\bA(0[1-9]|10):(20\d\d)\b`)
+
+// lookupRuleInfo returns the bundled metadata for a rule. An exact rule ID
+// wins over patterns.
+func lookupRuleInfo(ruleID string) RuleInfo {
+	if info, ok := ruleInfo[ruleID]; ok {
+		return info
+	}
+	for pattern, info := range ruleInfo {
+		if !strings.Contains(pattern, "*") {
+			continue
+		}
+		if ok, _ := path.Match(pattern, ruleID); ok {
+			return info
+		}
+	}
+	return RuleInfo{}
+}
+
+// owaspTags returns the OWASP Top 10 categories referenced by tags, with
+// 2021 categories spelled out
+func owaspTags(tags []string) []string {
+	var categories []string
+	for _, tag := range tags {
+		for _, m := range owaspPattern.FindAllStringSubmatch(tag, -1) {
+			category := "A" + m[1] + ":" + m[2]
+			if m[2] == "2021" {
+				category = owasp2021["A"+m[1]]
+			}
+			categories = mergeStrings(categories, []string{category})
+		}
+	}
+	return categories
+}
@@ -0,0 +1,706 @@
+package security
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/Siddhant-K-code/sentinel-ai/internal/suppress"
+)
+
+// SARIFSchema is the URI of the SARIF 2.1.0 JSON schema
+const SARIFSchema = "https://docs.oasis-open.org/sarif/sarif/v2.1.0/errata01/os/schemas/sarif-schema-2.1.0.json"
+
+// srcRoot is the uriBaseId every artifact location is relative to
+const srcRoot = "%SRCROOT%"
+
+// The types below model the parts of SARIF 2.1.0 that sentinel-ai reads
+// from other tools and writes itself. Optional objects are pointers so
+// that empty values are omitted rather than emitted as invalid objects.
+
+type sarifLog struct {
+	Schema  string     `json:"$schema,omitempty"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool               sarifTool                        `json:"tool"`
+	Invocations        []sarifInvocation                `json:"invocations,omitempty"`
+	OriginalURIBaseIDs map[string]sarifArtifactLocation `json:"originalUriBaseIds,omitempty"`
+	Results            []sarifResult                    `json:"results"`
+}
+
+type sarifTool struct {
+	Driver     sarifDriver   `json:"driver"`
+	Extensions []sarifDriver `json:"extensions,omitempty"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri,omitempty"`
+	Rules          []sarifRule `json:"rules,omitempty"`
+}
+
+type sarifRule struct {
+	ID                   string              `json:"id"`
+	Name                 string              `json:"name,omitempty"`
+	ShortDescription     *sarifMessage       `json:"shortDescription,omitempty"`
+	FullDescription      *sarifMessage       `json:"fullDescription,omitempty"`
+	Help                 *sarifMessage       `json:"help,omitempty"`
+	HelpURI              string              `json:"helpUri,omitempty"`
+	DefaultConfiguration *sarifConfiguration `json:"defaultConfiguration,omitempty"`
+	Properties           map[string]any      `json:"properties,omitempty"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level,omitempty"`
+}
+
+type sarifMessage struct {
+	Text     string `json:"text"`
+	Markdown string `json:"markdown,omitempty"`
+}
+
+type sarifRuleReference struct {
+	ID    string `json:"id,omitempty"`
+	Index *int   `json:"index,omitempty"`
+}
+
+type sarifResult struct {
+	RuleID              string              `json:"ruleId,omitempty"`
+	RuleIndex           *int                `json:"ruleIndex,omitempty"`
+	Rule                *sarifRuleReference `json:"rule,omitempty"`
+	Level               string              `json:"level,omitempty"`
+	Message             sarifMessage        `json:"message"`
+	Locations           []sarifLocation     `json:"locations,omitempty"`
+	CodeFlows           []sarifCodeFlow     `json:"codeFlows,omitempty"`
+	Fingerprints        map[string]string   `json:"fingerprints,omitempty"`
+	PartialFingerprints map[string]string   `json:"partialFingerprints,omitempty"`
+	Fixes               []sarifFix          `json:"fixes,omitempty"`
+	Suppressions        []sarifSuppression  `json:"suppressions,omitempty"`
+	BaselineState       string              `json:"baselineState,omitempty"`
+	Properties          map[string]any      `json:"properties,omitempty"`
+}
+
+type sarifSuppression struct {
+	Kind          string         `json:"kind"`
+	Status        string         `json:"status,omitempty"`
+	Justification string         `json:"justification,omitempty"`
+	Location      *sarifLocation `json:"location,omitempty"`
+	Properties    map[string]any `json:"properties,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation,omitempty"`
+	Message          *sarifMessage          `json:"message,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI       string `json:"uri,omitempty"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine,omitempty"`
+	StartColumn int `json:"startColumn,omitempty"`
+	EndLine     int `json:"endLine,omitempty"`
+	EndColumn   int `json:"endColumn,omitempty"`
+}
+
+type sarifCodeFlow struct {
+	ThreadFlows []sarifThreadFlow `json:"threadFlows"`
+}
+
+type sarifThreadFlow struct {
+	Locations []sarifThreadFlowLocation `json:"locations"`
+}
+
+type sarifThreadFlowLocation struct {
+	Location *sarifLocation `json:"location,omitempty"`
+}
+
+type sarifInvocation struct {
+	ExecutionSuccessful        bool                `json:"executionSuccessful"`
+	ExitCode                   *int                `json:"exitCode,omitempty"`
+	ToolExecutionNotifications []sarifNotification `json:"toolExecutionNotifications,omitempty"`
+}
+
+type sarifNotification struct {
+	Level   string       `json:"level,omitempty"`
+	Message sarifMessage `json:"message"`
+}
+
+type sarifFix struct {
+	Description     *sarifMessage         `json:"description,omitempty"`
+	ArtifactChanges []sarifArtifactChange `json:"artifactChanges"`
+}
+
+type sarifArtifactChange struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Replacements     []sarifReplacement    `json:"replacements"`
+}
+
+type sarifReplacement struct {
+	DeletedRegion   sarifRegion           `json:"deletedRegion"`
+	InsertedContent *sarifArtifactContent `json:"insertedContent,omitempty"`
+}
+
+type sarifArtifactContent struct {
+	Text string `json:"text"`
+}
+
+// text returns a message's text, or its markdown when there is no text
+func (m *sarifMessage) text() string {
+	if m == nil {
+		return ""
+	}
+	if m.Text != "" {
+		return m.Text
+	}
+	return m.Markdown
+}
+
+// position returns the path, line and column of a location
+func (l *sarifLocation) position(workspace string) (string, int, int) {
+	if l == nil || l.PhysicalLocation == nil {
+		return "", 0, 0
+	}
+	path := sarifPath(l.PhysicalLocation.ArtifactLocation.URI, workspace)
+	if r := l.PhysicalLocation.Region; r != nil {
+		return path, r.StartLine, r.StartColumn
+	}
+	return path, 0, 0
+}
+
+// parseSARIF converts every result in a SARIF log into Findings, keeping
+// the producing tool, rule metadata and fingerprints. Paths are made
+// relative to workspace when they point inside it.
+func parseSARIF(data []byte, workspace string) ([]Finding, error) {
+	var log sarifLog
+	if err := json.Unmarshal(data, &log); err != nil {
+		return nil, fmt.Errorf("failed to parse SARIF: %w", err)
+	}
+	if log.Version != "" && log.Version != "2.1.0" {
+		return nil, fmt.Errorf("unsupported SARIF version %q", log.Version)
+	}
+
+	var findings []Finding
+	for _, run := range log.Runs {
+		// Rules may live on the driver or, e.g. for CodeQL packs, on extensions
+		rules := make(map[string]sarifRule)
+		for _, component := range append([]sarifDriver{run.Tool.Driver}, run.Tool.Extensions...) {
+			for _, rule := range component.Rules {
+				rules[rule.ID] = rule
+			}
+		}
+
+		for _, result := range run.Results {
+			rule := lookupRule(result, run.Tool.Driver, rules)
+
+			ruleID := result.RuleID
+			if ruleID == "" {
+				ruleID = rule.ID
+			}
+
+			finding := Finding{
+				RuleID:       ruleID,
+				Message:      result.Message.text(),
+				Severity:     sarifSeverity(result, rule),
+				Description:  rule.FullDescription.text(),
+				Confidence:   propertyString(rule.Properties, "precision"),
+				Tool:         run.Tool.Driver.Name,
+				Rule:         ruleMetadata(rule),
+				Fingerprints: mergeFingerprints(result.Fingerprints, result.PartialFingerprints),
+			}
+			if finding.Description == "" {
+				finding.Description = rule.ShortDescription.text()
+			}
+
+			if len(result.Locations) > 0 {
+				finding.File, finding.Line, finding.Column = result.Locations[0].position(workspace)
+			}
+
+			for _, flow := range result.CodeFlows {
+				for _, thread := range flow.ThreadFlows {
+					var codeFlow CodeFlow
+					for _, step := range thread.Locations {
+						if step.Location == nil {
+							continue
+						}
+						file, line, column := step.Location.position(workspace)
+						codeFlow.Steps = append(codeFlow.Steps, FlowStep{
+							File:    file,
+							Line:    line,
+							Column:  column,
+							Message: step.Location.Message.text(),
+						})
+					}
+					if len(codeFlow.Steps) > 0 {
+						finding.CodeFlows = append(finding.CodeFlows, codeFlow)
+					}
+				}
+			}
+
+			findings = append(findings, finding)
+		}
+	}
+
+	return findings, nil
+}
+
+// lookupRule resolves a result's rule by ID, then by index into the driver
+func lookupRule(result sarifResult, driver sarifDriver, rules map[string]sarifRule) sarifRule {
+	id, index := result.RuleID, result.RuleIndex
+	if result.Rule != nil {
+		if id == "" {
+			id = result.Rule.ID
+		}
+		if index == nil {
+			index = result.Rule.Index
+		}
+	}
+
+	if rule, ok := rules[id]; ok {
+		return rule
+	}
+	if index != nil && *index >= 0 && *index < len(driver.Rules) {
+		return driver.Rules[*index]
+	}
+	return sarifRule{ID: id}
+}
+
+// ruleMetadata keeps the descriptive parts of a SARIF rule
+func ruleMetadata(rule sarifRule) *RuleMetadata {
+	if rule.Name == "" && rule.ShortDescription == nil && rule.Help == nil &&
+		rule.HelpURI == "" && len(rule.Properties) == 0 {
+		return nil
+	}
+
+	meta := &RuleMetadata{
+		Name:             rule.Name,
+		ShortDescription: rule.ShortDescription.text(),
+		Help:             rule.Help.text(),
+		HelpURI:          rule.HelpURI,
+		Properties:       rule.Properties,
+	}
+	if tags, ok := rule.Properties["tags"].([]any); ok {
+		for _, tag := range tags {
+			if s, ok := tag.(string); ok {
+				meta.Tags = append(meta.Tags, s)
+			}
+		}
+	}
+
+	return meta
+}
+
+// mergeFingerprints combines stable and partial fingerprints; stable ones
+// win on key collisions
+func mergeFingerprints(full, partial map[string]string) map[string]string {
+	if len(full) == 0 && len(partial) == 0 {
+		return nil
+	}
+	merged := make(map[string]string, len(full)+len(partial))
+	for k, v := range partial {
+		merged[k] = v
+	}
+	for k, v := range full {
+		merged[k] = v
+	}
+	return merged
+}
+
+// sarifSeverity prefers the result level and falls back to the rule's
+// problem.severity property and then its default level
+func sarifSeverity(result sarifResult, rule sarifRule) string {
+	if result.Level != "" {
+		return strings.ToLower(result.Level)
+	}
+	if severity := propertyString(rule.Properties, "problem.severity"); severity != "" {
+		return severity
+	}
+	if rule.DefaultConfiguration != nil && rule.DefaultConfiguration.Level != "" {
+		return strings.ToLower(rule.DefaultConfiguration.Level)
+	}
+	return "warning"
+}
+
+// propertyString returns a lowercased string property, or ""
+func propertyString(props map[string]any, key string) string {
+	if v, ok := props[key].(string); ok {
+		return strings.ToLower(v)
+	}
+	return ""
+}
+
+// sarifPath turns an artifact URI into a workspace-relative path
+func sarifPath(uri, workspace string) string {
+	if u, err := url.Parse(uri); err == nil && (u.Scheme == "file" || u.Scheme == "") {
+		uri = u.Path
+	}
+
+	path := filepath.FromSlash(uri)
+	if filepath.IsAbs(path) {
+		if abs, err := filepath.Abs(workspace); err == nil {
+			if rel, err := filepath.Rel(abs, path); err == nil && !strings.HasPrefix(rel, "..") {
+				return rel
+			}
+		}
+	}
+
+	return filepath.Clean(path)
+}
+
+// GenerateSARIF converts scan results to a SARIF 2.1.0 log. Each scan
+// result becomes one run per producing tool, and a result whose tool
+// failed still gets a run whose invocation records the error.
+func (s *Scanner) GenerateSARIF(results []ScanResult) ([]byte, error) {
+	log := sarifLog{
+		Schema:  SARIFSchema,
+		Version: "2.1.0",
+		Runs:    []sarifRun{},
+	}
+
+	var baseIDs map[string]sarifArtifactLocation
+	if root, err := s.runner.WorkDir(""); err == nil {
+		baseIDs = map[string]sarifArtifactLocation{
+			srcRoot: {URI: (&url.URL{Scheme: "file", Path: filepath.ToSlash(root) + "/"}).String()},
+		}
+	}
+
+	for _, result := range results {
+		invocation := sarifInvocation{ExecutionSuccessful: result.Error == ""}
+		exitCode := result.ExitCode
+		invocation.ExitCode = &exitCode
+		if result.Error != "" {
+			invocation.ToolExecutionNotifications = []sarifNotification{{
+				Level:   "error",
+				Message: sarifMessage{Text: result.Error},
+			}}
+		}
+
+		for _, group := range groupByTool(result) {
+			run := sarifRun{
+				Tool:               sarifTool{Driver: sarifDriver{Name: group.tool}},
+				Invocations:        []sarifInvocation{invocation},
+				OriginalURIBaseIDs: baseIDs,
+				Results:            []sarifResult{},
+			}
+			if group.tool == result.Tool {
+				run.Tool.Driver.Version = result.ToolVersion
+			}
+
+			ruleIndex := make(map[string]int)
+			for _, finding := range group.findings {
+				index, ok := ruleIndex[finding.RuleID]
+				if !ok {
+					index = len(run.Tool.Driver.Rules)
+					ruleIndex[finding.RuleID] = index
+					run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, s.ruleToSARIF(finding))
+				}
+				run.Results = append(run.Results, s.findingToSARIF(finding, index))
+			}
+
+			log.Runs = append(log.Runs, run)
+		}
+	}
+
+	return json.MarshalIndent(log, "", "  ")
+}
+
+// toolGroup is the findings of one tool within a scan result
+type toolGroup struct {
+	tool     string
+	findings []Finding
+}
+
+// groupByTool splits a scan result by producing tool, in order of first
+// appearance. A result without findings yields one empty group.
+func groupByTool(result ScanResult) []toolGroup {
+	var groups []toolGroup
+	index := make(map[string]int)
+	for _, finding := range result.Findings {
+		tool := finding.Tool
+		if tool == "" {
+			tool = result.Tool
+		}
+		i, ok := index[tool]
+		if !ok {
+			i = len(groups)
+			index[tool] = i
+			groups = append(groups, toolGroup{tool: tool})
+		}
+		groups[i].findings = append(groups[i].findings, finding)
+	}
+	if len(groups) == 0 {
+		groups = append(groups, toolGroup{tool: result.Tool})
+	}
+	return groups
+}
+
+// ruleToSARIF describes the rule behind a finding, including its help
+// text and tags such as CWE identifiers
+func (s *Scanner) ruleToSARIF(finding Finding) sarifRule {
+	rule := sarifRule{
+		ID:                   finding.RuleID,
+		DefaultConfiguration: &sarifConfiguration{Level: s.severityToLevel(finding.Severity)},
+	}
+	if finding.Description != "" {
+		rule.FullDescription = &sarifMessage{Text: finding.Description}
+	}
+
+	tags := []string{"security"}
+	props := make(map[string]any)
+	if meta := finding.Rule; meta != nil {
+		rule.Name = meta.Name
+		if meta.ShortDescription != "" {
+			rule.ShortDescription = &sarifMessage{Text: meta.ShortDescription}
+		}
+		if meta.Help != "" {
+			rule.Help = &sarifMessage{Text: meta.Help}
+		}
+		if u, err := url.Parse(meta.HelpURI); err == nil && u.IsAbs() {
+			rule.HelpURI = meta.HelpURI
+		}
+		for k, v := range meta.Properties {
+			props[k] = v
+		}
+		for _, tag := range meta.Tags {
+			if tag != "security" {
+				tags = append(tags, tag)
+			}
+		}
+	}
+	if c := finding.Classification; c != nil {
+		tags = mergeStrings(tags, c.CWE, c.OWASP)
+		if _, ok := props[securitySeverityKey]; !ok && c.CVSS > 0 {
+			props[securitySeverityKey] = strconv.FormatFloat(c.CVSS, 'f', 1, 64)
+		}
+	}
+	if rule.ShortDescription == nil && finding.Message != "" {
+		rule.ShortDescription = &sarifMessage{Text: finding.Message}
+	}
+	if rule.Help == nil && finding.Description != "" {
+		rule.Help = &sarifMessage{Text: finding.Description}
+	}
+
+	props["tags"] = tags
+	if finding.Confidence != "" {
+		props["precision"] = finding.Confidence
+	}
+	rule.Properties = props
+
+	return rule
+}
+
+// findingToSARIF converts a finding into a SARIF result
+func (s *Scanner) findingToSARIF(finding Finding, ruleIndex int) sarifResult {
+	text := finding.Message
+	if text == "" {
+		text = finding.Description
+	}
+	if text == "" {
+		text = finding.RuleID
+	}
+
+	result := sarifResult{
+		RuleID:        finding.RuleID,
+		RuleIndex:     &ruleIndex,
+		Level:         s.severityToLevel(finding.Severity),
+		Message:       sarifMessage{Text: text},
+		Locations:     []sarifLocation{artifactLocation(finding.File, finding.Line, finding.Column, "")},
+		BaselineState: finding.BaselineState,
+	}
+
+	if finding.Fingerprint != "" || len(finding.Fingerprints) > 0 {
+		result.PartialFingerprints = make(map[string]string, len(finding.Fingerprints)+1)
+		for k, v := range finding.Fingerprints {
+			result.PartialFingerprints[k] = v
+		}
+		if finding.Fingerprint != "" {
+			result.PartialFingerprints[FingerprintKey] = finding.Fingerprint
+		}
+	}
+
+	for _, flow := range finding.CodeFlows {
+		var thread sarifThreadFlow
+		for _, step := range flow.Steps {
+			loc := artifactLocation(step.File, step.Line, step.Column, step.Message)
+			thread.Locations = append(thread.Locations, sarifThreadFlowLocation{Location: &loc})
+		}
+		result.CodeFlows = append(result.CodeFlows, sarifCodeFlow{ThreadFlows: []sarifThreadFlow{thread}})
+	}
+
+	if sup := finding.Suppression; sup != nil {
+		loc := artifactLocation(filepath.FromSlash(sup.File), sup.Line, 0, "")
+		result.Suppressions = []sarifSuppression{{
+			Kind:          "inSource",
+			Status:        "accepted",
+			Justification: sup.Reason,
+			Location:      &loc,
+			Properties:    map[string]any{"until": sup.Until.Format(suppress.DateLayout)},
+		}}
+	}
+
+	if finding.Fix != nil {
+		if changes := diffToArtifactChanges(finding.Fix.Diff); len(changes) > 0 {
+			fix := sarifFix{ArtifactChanges: changes}
+			if finding.Fix.Description != "" {
+				fix.Description = &sarifMessage{Text: finding.Fix.Description}
+			}
+			result.Fixes = []sarifFix{fix}
+		}
+	}
+
+	props := make(map[string]any)
+	if finding.Confidence != "" {
+		props["confidence"] = finding.Confidence
+	}
+	if finding.Triage != nil {
+		props["triage"] = finding.Triage
+	}
+	if len(finding.Tools) > 1 {
+		props["tools"] = finding.Tools
+	}
+	if finding.Commit != nil {
+		props["commit"] = finding.Commit.Hash
+		props["author"] = finding.Commit.Author
+		props["commitDate"] = finding.Commit.Date
+	}
+	if len(props) > 0 {
+		result.Properties = props
+	}
+
+	return result
+}
+
+// artifactLocation builds a location relative to %SRCROOT%
+func artifactLocation(file string, line, column int, message string) sarifLocation {
+	loc := sarifLocation{
+		PhysicalLocation: &sarifPhysicalLocation{
+			ArtifactLocation: sarifArtifactLocation{
+				URI:       (&url.URL{Path: filepath.ToSlash(file)}).String(),
+				URIBaseID: srcRoot,
+			},
+		},
+	}
+	if line > 0 {
+		loc.PhysicalLocation.Region = &sarifRegion{StartLine: line}
+		if column > 0 {
+			loc.PhysicalLocation.Region.StartColumn = column
+		}
+	}
+	if message != "" {
+		loc.Message = &sarifMessage{Text: message}
+	}
+	return loc
+}
+
+// diffToArtifactChanges converts a unified diff into SARIF artifact
+// changes. Each hunk replaces the old line range, expressed as a region
+// from the first old line up to (not including) the line after it, with
+// the hunk's new lines.
+func diffToArtifactChanges(diff string) []sarifArtifactChange {
+	var changes []sarifArtifactChange
+	var current *sarifArtifactChange
+	var replacement *sarifReplacement
+	var inserted strings.Builder
+
+	flush := func() {
+		if replacement != nil && current != nil {
+			if inserted.Len() > 0 {
+				replacement.InsertedContent = &sarifArtifactContent{Text: inserted.String()}
+			}
+			current.Replacements = append(current.Replacements, *replacement)
+		}
+		replacement = nil
+		inserted.Reset()
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(diff))
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "--- "):
+			flush()
+		case strings.HasPrefix(line, "+++ "):
+			path := strings.TrimSpace(strings.TrimPrefix(line, "+++ "))
+			if i := strings.IndexByte(path, '\t'); i >= 0 {
+				path = path[:i]
+			}
+			path = strings.TrimPrefix(path, "b/")
+			changes = append(changes, sarifArtifactChange{
+				ArtifactLocation: sarifArtifactLocation{
+					URI:       (&url.URL{Path: path}).String(),
+					URIBaseID: srcRoot,
+				},
+			})
+			current = &changes[len(changes)-1]
+		case strings.HasPrefix(line, "@@ "):
+			flush()
+			start, count, ok := parseHunkOld(line)
+			if !ok || current == nil {
+				continue
+			}
+			// A zero-length old range inserts after line start
+			if count == 0 {
+				start++
+			}
+			replacement = &sarifReplacement{DeletedRegion: sarifRegion{
+				StartLine:   start,
+				StartColumn: 1,
+				EndLine:     start + count,
+				EndColumn:   1,
+			}}
+		case replacement != nil && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "+")):
+			inserted.WriteString(line[1:])
+			inserted.WriteByte('\n')
+		}
+	}
+	flush()
+
+	var valid []sarifArtifactChange
+	for _, change := range changes {
+		if len(change.Replacements) > 0 {
+			valid = append(valid, change)
+		}
+	}
+	return valid
+}
+
+// parseHunkOld extracts the old start line and count from a hunk header
+// such as "@@ -12,3 +12,4 @@"
+func parseHunkOld(header string) (int, int, bool) {
+	fields := strings.Fields(header)
+	if len(fields) < 3 || !strings.HasPrefix(fields[1], "-") {
+		return 0, 0, false
+	}
+
+	old := strings.TrimPrefix(fields[1], "-")
+	count := 1
+	if i := strings.IndexByte(old, ','); i >= 0 {
+		n, err := strconv.Atoi(old[i+1:])
+		if err != nil {
+			return 0, 0, false
+		}
+		count = n
+		old = old[:i]
+	}
+
+	start, err := strconv.Atoi(old)
+	if err != nil {
+		return 0, 0, false
+	}
+	return start, count, true
+}
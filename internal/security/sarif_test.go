@@ -0,0 +1,198 @@
+package security
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+
+	"github.com/Siddhant-K-code/sentinel-ai/internal/tools"
+)
+
+// validateSARIF checks a log against the official SARIF 2.1.0 schema
+func validateSARIF(t *testing.T, data []byte) {
+	t.Helper()
+
+	schema, err := os.ReadFile("testdata/sarif-schema-2.1.0.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource(SARIFSchema, bytes.NewReader(schema)); err != nil {
+		t.Fatal(err)
+	}
+	validator, err := compiler.Compile(SARIFSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("SARIF is not valid JSON: %v", err)
+	}
+	if err := validator.Validate(doc); err != nil {
+		t.Fatalf("SARIF does not validate against the schema: %#v", err)
+	}
+}
+
+func sampleResults() []ScanResult {
+	return []ScanResult{
+		{
+			Tool:        "semgrep",
+			ToolVersion: "1.50.0",
+			Findings: []Finding{
+				{
+					RuleID:      "go.lang.security.audit.xss.template-html-does-not-escape",
+					Message:     "Unescaped user input in template",
+					Severity:    "error",
+					File:        "example/main.go",
+					Line:        21,
+					Column:      3,
+					Description: "Template data is rendered without escaping.",
+					Confidence:  "high",
+					Rule: &RuleMetadata{
+						Name:    "TemplateXSS",
+						Help:    "Escape user input with template.HTMLEscapeString.",
+						HelpURI: "https://cwe.mitre.org/data/definitions/79.html",
+						Tags:    []string{"CWE-79"},
+					},
+					Fingerprints: map[string]string{"primaryLocationLineHash": "abc:1"},
+					CodeFlows: []CodeFlow{{Steps: []FlowStep{
+						{File: "example/main.go", Line: 16, Column: 15, Message: "source"},
+						{File: "example/main.go", Line: 21, Column: 3, Message: "sink"},
+					}}},
+					Triage: &Triage{Verdict: "true_positive", Rationale: "user input reaches template", Source: "llm"},
+					Fix: &Fix{
+						Description: "Escape user input",
+						Diff: `--- a/example/main.go
++++ b/example/main.go
+@@ -20,3 +20,3 @@
+ 	t.Execute(w, map[string]interface{}{
+-		"Name": userInput, // Vulnerable: no escaping
++		"Name": template.HTMLEscapeString(userInput),
+ 	})
+`,
+					},
+				},
+				{
+					RuleID:   "go.lang.security.audit.xss.template-html-does-not-escape",
+					Message:  "Unescaped user input in template",
+					Severity: "error",
+					File:     "other dir/handler.go",
+					Line:     7,
+				},
+			},
+		},
+		{
+			Tool:     "codeql",
+			Findings: []Finding{},
+			Error:    "codeql not found in PATH",
+			ExitCode: 127,
+		},
+	}
+}
+
+func TestGenerateSARIFValidatesAgainstSchema(t *testing.T) {
+	scanner := NewScanner(tools.NewRunner(t.TempDir(), nil, time.Second), ".")
+
+	data, err := scanner.GenerateSARIF(sampleResults())
+	if err != nil {
+		t.Fatal(err)
+	}
+	validateSARIF(t, data)
+
+	empty, err := scanner.GenerateSARIF(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	validateSARIF(t, empty)
+}
+
+func TestGenerateSARIFContent(t *testing.T) {
+	scanner := NewScanner(tools.NewRunner(t.TempDir(), nil, time.Second), ".")
+
+	data, err := scanner.GenerateSARIF(sampleResults())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var log sarifLog
+	if err := json.Unmarshal(data, &log); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(log.Runs) != 2 {
+		t.Fatalf("Expected failed tool to keep its run, got %d runs", len(log.Runs))
+	}
+
+	semgrep := log.Runs[0]
+	if semgrep.Tool.Driver.Version != "1.50.0" {
+		t.Errorf("Expected real driver version, got %q", semgrep.Tool.Driver.Version)
+	}
+	if len(semgrep.Tool.Driver.Rules) != 1 {
+		t.Fatalf("Expected one deduplicated rule, got %d", len(semgrep.Tool.Driver.Rules))
+	}
+	rule := semgrep.Tool.Driver.Rules[0]
+	if rule.Help == nil || rule.Help.Text == "" {
+		t.Error("Expected rule help text")
+	}
+	if tags, _ := rule.Properties["tags"].([]interface{}); len(tags) != 2 || tags[1] != "CWE-79" {
+		t.Errorf("Expected security and CWE tags, got %v", rule.Properties["tags"])
+	}
+	if _, ok := semgrep.OriginalURIBaseIDs[srcRoot]; !ok {
+		t.Error("Expected %SRCROOT% base id")
+	}
+
+	result := semgrep.Results[0]
+	if result.Message.Text != "Unescaped user input in template" {
+		t.Errorf("Expected message text to be kept, got %q", result.Message.Text)
+	}
+	if result.PartialFingerprints["primaryLocationLineHash"] != "abc:1" {
+		t.Errorf("Expected partial fingerprints, got %v", result.PartialFingerprints)
+	}
+	if result.Properties["triage"] == nil {
+		t.Error("Expected triage verdict in properties")
+	}
+	if len(result.Fixes) != 1 || len(result.Fixes[0].ArtifactChanges) != 1 {
+		t.Fatalf("Expected one fix, got %+v", result.Fixes)
+	}
+	replacement := result.Fixes[0].ArtifactChanges[0].Replacements[0]
+	if replacement.DeletedRegion.StartLine != 20 || replacement.DeletedRegion.EndLine != 23 {
+		t.Errorf("Expected lines 20-22 replaced, got %+v", replacement.DeletedRegion)
+	}
+
+	codeql := log.Runs[1]
+	if len(codeql.Invocations) != 1 || codeql.Invocations[0].ExecutionSuccessful {
+		t.Fatalf("Expected failed invocation, got %+v", codeql.Invocations)
+	}
+	if *codeql.Invocations[0].ExitCode != 127 || len(codeql.Invocations[0].ToolExecutionNotifications) != 1 {
+		t.Errorf("Expected exit code and error notification, got %+v", codeql.Invocations[0])
+	}
+}
+
+func TestParseSARIFRoundTrip(t *testing.T) {
+	scanner := NewScanner(tools.NewRunner(t.TempDir(), nil, time.Second), ".")
+
+	data, err := scanner.GenerateSARIF(sampleResults())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	findings, err := parseSARIF(data, ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(findings) != 2 {
+		t.Fatalf("Expected 2 findings, got %d", len(findings))
+	}
+	if findings[1].File != "other dir/handler.go" {
+		t.Errorf("Expected escaped path to round-trip, got %q", findings[1].File)
+	}
+	if len(findings[0].CodeFlows) != 1 || findings[0].Rule == nil || findings[0].Rule.Help == "" {
+		t.Errorf("Expected code flows and rule metadata to round-trip, got %+v", findings[0])
+	}
+}
@@ -2,13 +2,13 @@ package security
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"os/exec"
-	"path/filepath"
 	"strings"
 	"time"
 
+	"github.com/Siddhant-K-code/sentinel-ai/internal/goanalysis"
+	"github.com/Siddhant-K-code/sentinel-ai/internal/parallel"
+	"github.com/Siddhant-K-code/sentinel-ai/internal/policy"
+	"github.com/Siddhant-K-code/sentinel-ai/internal/suppress"
 	"github.com/Siddhant-K-code/sentinel-ai/internal/tools"
 )
 
@@ -16,6 +16,11 @@ import (
 type Scanner struct {
 	runner *tools.Runner
 	workspace string
+	analyzers []Analyzer
+	// programs is passed to analyzers in their Env
+	programs *goanalysis.Loader
+	// severities maps rule IDs or patterns to overriding severities
+	severities map[string]string
 }
 
 // ScanResult represents the result of a security scan
@@ -24,6 +29,8 @@ type ScanResult struct {
 	Findings []Finding `json:"findings"`
 	Duration time.Duration `json:"duration"`
 	Error    string    `json:"error,omitempty"`
+	ExitCode    int    `json:"exit_code"`
+	ToolVersion string `json:"tool_version,omitempty"`
 }
 
 // Finding represents a security finding
@@ -36,205 +43,152 @@ type Finding struct {
 	Column      int    `json:"column"`
 	Description string `json:"description"`
 	Confidence  string `json:"confidence"`
+	CodeFlows   []CodeFlow `json:"code_flows,omitempty"`
+	Tool         string            `json:"tool,omitempty"`
+	Rule         *RuleMetadata     `json:"rule,omitempty"`
+	Fingerprints map[string]string `json:"fingerprints,omitempty"`
+	Fingerprint  string            `json:"fingerprint,omitempty"`
+	Tools        []string          `json:"tools,omitempty"`
+	// BaselineState is "new" or "absent" when compared with a baseline
+	BaselineState string `json:"baseline_state,omitempty"`
+	// Commit is where a finding from git history was introduced
+	Commit *Commit `json:"commit,omitempty"`
+	// Suppression is the inline comment accepting this finding, if any
+	Suppression *suppress.Suppression `json:"suppression,omitempty"`
+	Triage       *Triage           `json:"triage,omitempty"`
+	Fix          *Fix              `json:"fix,omitempty"`
+	// ToolSeverity is the severity as the tool reported it, when it
+	// differs from the canonical Severity
+	ToolSeverity string `json:"tool_severity,omitempty"`
+	// Classification holds the finding's CWEs, OWASP Top 10 categories
+	// and CVSS score
+	Classification *Classification `json:"classification,omitempty"`
 }
 
-// NewScanner creates a new security scanner
-func NewScanner(runner *tools.Runner, workspace string) *Scanner {
-	return &Scanner{
-		runner:    runner,
-		workspace: workspace,
-	}
+// Classification places a finding in common weakness taxonomies
+type Classification struct {
+	CWE   []string `json:"cwe,omitempty"`
+	OWASP []string `json:"owasp,omitempty"`
+	// CVSS is a base score from 0 to 10, or 0 when unknown
+	CVSS float64 `json:"cvss,omitempty"`
 }
 
-// Scan performs security scanning using available tools
-func (s *Scanner) Scan(ctx context.Context) ([]ScanResult, error) {
-	var results []ScanResult
-
-	// Try Semgrep first
-	if semgrepResult := s.runSemgrep(ctx); semgrepResult != nil {
-		results = append(results, *semgrepResult)
-	}
-
-	// Try CodeQL if available
-	if codeqlResult := s.runCodeQL(ctx); codeqlResult != nil {
-		results = append(results, *codeqlResult)
-	}
-
-	return results, nil
+// Triage is a verdict on whether a finding is real, e.g. from LLM review
+type Triage struct {
+	Verdict   string `json:"verdict"` // true_positive, false_positive, needs_review
+	Rationale string `json:"rationale,omitempty"`
+	Source    string `json:"source,omitempty"`
 }
 
-// runSemgrep runs Semgrep security scanning
-func (s *Scanner) runSemgrep(ctx context.Context) *ScanResult {
-	start := time.Now()
-
-	// Check if semgrep is available
-	if _, err := exec.LookPath("semgrep"); err != nil {
-		return &ScanResult{
-			Tool:     "semgrep",
-			Findings: []Finding{},
-			Duration: time.Since(start),
-			Error:    "semgrep not found in PATH",
-		}
-	}
-
-	// Run semgrep with auto config
-	result := s.runner.Run(ctx, "semgrep", "--config", "auto", "--json", s.workspace)
-	if result.Error != nil {
-		return &ScanResult{
-			Tool:     "semgrep",
-			Findings: []Finding{},
-			Duration: time.Since(start),
-			Error:    result.Error.Error(),
-		}
-	}
-
-	// Parse semgrep JSON output
-	var semgrepOutput struct {
-		Results []struct {
-			CheckID  string `json:"check_id"`
-			Path     string `json:"path"`
-			Start    struct {
-				Line   int `json:"line"`
-				Column int `json:"column"`
-			} `json:"start"`
-			End struct {
-				Line   int `json:"line"`
-				Column int `json:"column"`
-			} `json:"end"`
-			Extra struct {
-				Message     string `json:"message"`
-				Severity    string `json:"severity"`
-				Description string `json:"description"`
-				Confidence  string `json:"confidence"`
-			} `json:"extra"`
-		} `json:"results"`
-	}
+// Fix is a proposed patch for a finding
+type Fix struct {
+	Description string `json:"description,omitempty"`
+	Diff        string `json:"diff"` // unified diff relative to the workspace root
+}
 
-	if err := json.Unmarshal(result.Stdout, &semgrepOutput); err != nil {
-		return &ScanResult{
-			Tool:     "semgrep",
-			Findings: []Finding{},
-			Duration: time.Since(start),
-			Error:    fmt.Sprintf("failed to parse semgrep output: %v", err),
-		}
-	}
+// RuleMetadata describes the rule that produced a finding, as reported
+// by the tool
+type RuleMetadata struct {
+	Name             string                 `json:"name,omitempty"`
+	ShortDescription string                 `json:"short_description,omitempty"`
+	Help             string                 `json:"help,omitempty"`
+	HelpURI          string                 `json:"help_uri,omitempty"`
+	Tags             []string               `json:"tags,omitempty"`
+	Properties       map[string]interface{} `json:"properties,omitempty"`
+}
 
-	// Convert to our Finding format
-	var findings []Finding
-	for _, r := range semgrepOutput.Results {
-		// Make path relative to workspace
-		relPath, _ := filepath.Rel(s.workspace, r.Path)
-		if relPath == "." {
-			relPath = filepath.Base(r.Path)
-		}
+// CodeFlow is an ordered path through the code, e.g. from a taint source
+// to the sink where the finding is reported
+type CodeFlow struct {
+	Steps []FlowStep `json:"steps"`
+}
 
-		findings = append(findings, Finding{
-			RuleID:      r.CheckID,
-			Message:     r.Extra.Message,
-			Severity:    strings.ToLower(r.Extra.Severity),
-			File:        relPath,
-			Line:        r.Start.Line,
-			Column:      r.Start.Column,
-			Description: r.Extra.Description,
-			Confidence:  strings.ToLower(r.Extra.Confidence),
-		})
-	}
+// FlowStep is a single location along a CodeFlow
+type FlowStep struct {
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Message string `json:"message,omitempty"`
+}
 
-	return &ScanResult{
-		Tool:     "semgrep",
-		Findings: findings,
-		Duration: time.Since(start),
+// NewScanner creates a new security scanner running the default analyzers
+func NewScanner(runner *tools.Runner, workspace string) *Scanner {
+	s := &Scanner{
+		runner:    runner,
+		workspace: workspace,
 	}
+	// Default analyzers take no required options, so this cannot fail
+	_ = s.Configure(policy.DefaultPolicy(), nil)
+	return s
 }
 
-// runCodeQL runs CodeQL security scanning
-func (s *Scanner) runCodeQL(ctx context.Context) *ScanResult {
-	start := time.Now()
-
-	// Check if codeql is available
-	if _, err := exec.LookPath("codeql"); err != nil {
-		return &ScanResult{
-			Tool:     "codeql",
-			Findings: []Finding{},
-			Duration: time.Since(start),
-			Error:    "codeql not found in PATH",
+// Tasks returns one task per configured analyzer, so a caller can run
+// them in its own pool; Collect turns their results into scan results.
+// A task whose tool failed because ctx was done returns the context error
+// along with the partial result.
+func (s *Scanner) Tasks() []parallel.Task[*ScanResult] {
+	langs := make(map[string]bool)
+	if root, err := s.runner.WorkDir(""); err == nil {
+		detected, _ := detectLanguages(root)
+		for _, lang := range detected {
+			langs[lang] = true
 		}
 	}
 
-	// For now, return empty result as CodeQL requires database creation
-	// In a full implementation, this would:
-	// 1. Create a CodeQL database
-	// 2. Run analysis queries
-	// 3. Parse the SARIF output
-	return &ScanResult{
-		Tool:     "codeql",
-		Findings: []Finding{},
-		Duration: time.Since(start),
-		Error:    "CodeQL integration not fully implemented",
+	var tasks []parallel.Task[*ScanResult]
+	for _, a := range s.analyzers {
+		a := a
+		tasks = append(tasks, parallel.Task[*ScanResult]{Name: a.Name(), Run: func(ctx context.Context) (*ScanResult, error) {
+			result := s.runAnalyzer(ctx, a, langs)
+			if err := ctx.Err(); err != nil && result.Error != "" {
+				return result, err
+			}
+			return result, nil
+		}})
 	}
+	return tasks
 }
 
-// GenerateSARIF converts scan results to SARIF format
-func (s *Scanner) GenerateSARIF(results []ScanResult) ([]byte, error) {
-	sarif := map[string]interface{}{
-		"$schema": "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/master/Schemata/sarif-schema-2.1.0.json",
-		"version": "2.1.0",
-		"runs":    []map[string]interface{}{},
+// Collect merges the results of Tasks, classifies the findings and
+// applies suppressions. Analyzers that did not finish are reported with
+// an error.
+func (s *Scanner) Collect(runs []parallel.Result[*ScanResult]) []ScanResult {
+	var results []ScanResult
+	for _, r := range runs {
+		switch {
+		case !r.Done:
+			results = append(results, ScanResult{
+				Tool:     r.Name,
+				Findings: []Finding{},
+				Duration: r.Duration,
+				Error:    r.Err.Error(),
+			})
+		case r.Value != nil:
+			results = append(results, *r.Value)
+		}
 	}
 
-	var runs []map[string]interface{}
-	for _, result := range results {
-		if result.Error != "" {
-			continue // Skip failed scans
-		}
+	// The same issue reported by several tools is kept once
+	root, _ := s.runner.WorkDir("")
+	fingerprintAndMerge(results, root)
 
-		run := map[string]interface{}{
-			"tool": map[string]interface{}{
-				"driver": map[string]interface{}{
-					"name":    result.Tool,
-					"version": "1.0.0",
-				},
-			},
-			"results": []map[string]interface{}{},
+	// Put every finding on the canonical severity scale and classify it
+	for i := range results {
+		for j := range results[i].Findings {
+			classify(&results[i].Findings[j], s.severities)
 		}
+	}
 
-		var sarifResults []map[string]interface{}
-		for _, finding := range result.Findings {
-			sarifResult := map[string]interface{}{
-				"ruleId": finding.RuleID,
-				"message": map[string]interface{}{
-					"text": finding.Message,
-				},
-				"level": s.severityToLevel(finding.Severity),
-				"locations": []map[string]interface{}{
-					{
-						"physicalLocation": map[string]interface{}{
-							"artifactLocation": map[string]interface{}{
-								"uri": finding.File,
-							},
-							"region": map[string]interface{}{
-								"startLine":   finding.Line,
-								"startColumn": finding.Column,
-							},
-						},
-					},
-				},
-			}
-
-			if finding.Description != "" {
-				sarifResult["message"].(map[string]interface{})["text"] = finding.Description
-			}
-
-			sarifResults = append(sarifResults, sarifResult)
+	// Mark findings accepted inline and report unusable suppressions, which
+	// are classified like any other finding
+	if problems := applySuppressions(results, root); problems != nil {
+		for j := range problems.Findings {
+			classify(&problems.Findings[j], s.severities)
 		}
-
-		run["results"] = sarifResults
-		runs = append(runs, run)
+		results = append(results, *problems)
 	}
 
-	sarif["runs"] = runs
-
-	return json.MarshalIndent(sarif, "", "  ")
+	return results
 }
 
 // severityToLevel converts severity string to SARIF level
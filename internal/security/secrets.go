@@ -0,0 +1,443 @@
+package security
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Siddhant-K-code/sentinel-ai/internal/policy"
+)
+
+func init() {
+	Register("secrets", newSecretsAnalyzer, true)
+}
+
+// DefaultSecretAllowlist are globs of files that hold fake credentials
+var DefaultSecretAllowlist = []string{
+	"**/testdata/**",
+	"**/fixtures/**",
+	"**/__fixtures__/**",
+	"**/node_modules/**",
+}
+
+// secretHashKey is the fingerprint holding a keyed hash of the secret
+// value
+const secretHashKey = "secretHmac/v1"
+
+// SecretKeyEnv names the environment variable holding the key for secret
+// hashes. Without it a random key is used for the process, so secret
+// fingerprints change on every run.
+const SecretKeyEnv = "SENTINEL_SECRET_KEY"
+
+// processSecretKey is the random key used when SecretKeyEnv is unset
+var processSecretKey = sync.OnceValue(func() []byte {
+	key := make([]byte, 32)
+	rand.Read(key) // never fails since Go 1.24
+	return key
+})
+
+// defaultSecretValueAllowlist matches obvious placeholder values
+var defaultSecretValueAllowlist = regexp.MustCompile(`(?i)example|dummy|placeholder|changeme|redacted|fake|x{6,}|\*{4,}|\$\{|\{\{`)
+
+// SecretsOptions configures the secret scanner
+type SecretsOptions struct {
+	// Allowlist holds globs of files not to scan; the defaults cover test
+	// fixtures and are used when empty
+	Allowlist []string `yaml:"allowlist"`
+	// AllowValues holds regexes of values that are never reported
+	AllowValues []string `yaml:"allow_values"`
+	// MinEntropy is the Shannon entropy, in bits per character, a generic
+	// secret assignment must reach to be reported (default 3.5)
+	MinEntropy float64 `yaml:"min_entropy"`
+	// History also scans files committed to git, including deleted ones
+	History SecretHistoryOptions `yaml:"history"`
+}
+
+// secretRule detects one kind of credential. The first capture group, or
+// the whole match without one, is the secret.
+type secretRule struct {
+	id          string
+	description string
+	severity    string
+	pattern     *regexp.Regexp
+	// entropy requires the secret to reach the configured minimum entropy
+	entropy bool
+	// validate rejects matches that only look like the credential
+	validate func(secret string) bool
+}
+
+// secretRules are tried in order; later rules skip text already matched
+var secretRules = []secretRule{
+	{
+		id:          "secrets/private-key",
+		description: "Private key",
+		severity:    "error",
+		pattern:     regexp.MustCompile(`-----BEGIN ((?:RSA |DSA |EC |OPENSSH |ENCRYPTED |PGP )?PRIVATE KEY(?: BLOCK)?)-----`),
+	},
+	{
+		id:          "secrets/aws-access-key-id",
+		description: "AWS access key ID",
+		severity:    "error",
+		pattern:     regexp.MustCompile(`\b((?:AKIA|ASIA|ABIA|ACCA)[0-9A-Z]{16})\b`),
+	},
+	{
+		id:          "secrets/aws-secret-access-key",
+		description: "AWS secret access key",
+		severity:    "error",
+		pattern:     regexp.MustCompile(`(?i)aws_?(?:secret)?_?(?:access)?_?key["']?\s*[:=]\s*["']?([A-Za-z0-9/+]{40})\b`),
+		entropy:     true,
+	},
+	{
+		id:          "secrets/github-token",
+		description: "GitHub token",
+		severity:    "error",
+		pattern:     regexp.MustCompile(`\b((?:ghp|gho|ghu|ghs|ghr)_[A-Za-z0-9]{36,255}|github_pat_[A-Za-z0-9_]{82})\b`),
+	},
+	{
+		id:          "secrets/slack-token",
+		description: "Slack token",
+		severity:    "error",
+		pattern:     regexp.MustCompile(`\b(xox[baprs]-[0-9A-Za-z-]{10,250})\b`),
+	},
+	{
+		id:          "secrets/slack-webhook",
+		description: "Slack webhook URL",
+		severity:    "warning",
+		pattern:     regexp.MustCompile(`(https://hooks\.slack\.com/services/T[A-Za-z0-9_]+/B[A-Za-z0-9_]+/[A-Za-z0-9_]+)`),
+	},
+	{
+		id:          "secrets/jwt",
+		description: "JSON Web Token",
+		severity:    "warning",
+		pattern:     regexp.MustCompile(`\b(eyJ[A-Za-z0-9_-]{10,}\.eyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,})`),
+		validate:    isJWT,
+	},
+	{
+		id:          "secrets/generic",
+		description: "Hard-coded secret",
+		severity:    "warning",
+		pattern:     regexp.MustCompile(`(?i)(?:api[_-]?key|secret|token|passw(?:or)?d|credential)[\w-]*["']?\s*(?::=|[:=])\s*["']([^"'\s]{16,})["']`),
+		entropy:     true,
+	},
+}
+
+// secretMatch is a detected secret; the raw value never leaves this file
+type secretMatch struct {
+	rule   *secretRule
+	line   int
+	column int
+	masked string
+	hash   string
+}
+
+// secretDetector applies the rules with the configured allowlists
+type secretDetector struct {
+	allowValues []*regexp.Regexp
+	minEntropy  float64
+	// key keys the secret hashes, so published fingerprints cannot be used
+	// to confirm a guessed secret
+	key []byte
+}
+
+// secretsAnalyzer finds credentials in workspace files without running
+// any external tool
+type secretsAnalyzer struct {
+	env      Env
+	opts     SecretsOptions
+	detector *secretDetector
+}
+
+// newSecretsAnalyzer creates the secret scanner from its policy options
+func newSecretsAnalyzer(env Env, cfg policy.AnalyzerConfig) (Analyzer, error) {
+	var opts SecretsOptions
+	if err := cfg.Decode(&opts); err != nil {
+		return nil, err
+	}
+	if len(opts.Allowlist) == 0 {
+		opts.Allowlist = DefaultSecretAllowlist
+	}
+
+	detector, err := newSecretDetector(opts)
+	if err != nil {
+		return nil, err
+	}
+	detector.key = secretKey()
+	return &secretsAnalyzer{env: env, opts: opts, detector: detector}, nil
+}
+
+// newSecretDetector compiles the value allowlist
+func newSecretDetector(opts SecretsOptions) (*secretDetector, error) {
+	d := &secretDetector{
+		allowValues: []*regexp.Regexp{defaultSecretValueAllowlist},
+		minEntropy:  opts.MinEntropy,
+	}
+	if d.minEntropy <= 0 {
+		d.minEntropy = 3.5
+	}
+	for _, expr := range opts.AllowValues {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("allow_values: %w", err)
+		}
+		d.allowValues = append(d.allowValues, re)
+	}
+	return d, nil
+}
+
+// Name returns the analyzer name
+func (a *secretsAnalyzer) Name() string {
+	return "secrets"
+}
+
+// Languages returns nil; secrets can hide in any file
+func (a *secretsAnalyzer) Languages() []string {
+	return nil
+}
+
+// Available always succeeds; the scanner runs in process
+func (a *secretsAnalyzer) Available() error {
+	return nil
+}
+
+// Run scans every workspace file that is not denied by policy, allowlisted
+// or larger than limits.max_file_bytes, then git history when enabled
+func (a *secretsAnalyzer) Run(ctx context.Context) *ScanResult {
+	start := time.Now()
+	result := &ScanResult{Tool: "secrets", Findings: []Finding{}}
+
+	root, err := a.env.Runner.WorkDir("")
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	limits := a.env.Policy.Limits
+	files := 0
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil || rel == "." {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		if d.IsDir() {
+			if d.Name() == ".git" || a.skip(rel+"/") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() || a.skip(rel) {
+			return nil
+		}
+		if info, err := d.Info(); err != nil || (limits.MaxFileBytes > 0 && info.Size() > int64(limits.MaxFileBytes)) {
+			return nil
+		}
+
+		files++
+		if limits.MaxFiles > 0 && files > limits.MaxFiles {
+			return fmt.Errorf("stopped after max_files (%d) files", limits.MaxFiles)
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil || bytes.IndexByte(data, 0) >= 0 {
+			return nil
+		}
+		for _, m := range a.detector.scan(data) {
+			result.Findings = append(result.Findings, m.finding(filepath.FromSlash(rel)))
+		}
+		return nil
+	})
+	if err != nil {
+		result.Error = err.Error()
+	}
+
+	if a.opts.History.Enabled && result.Error == "" {
+		history, err := a.scanHistory(ctx)
+		result.Findings = append(result.Findings, history...)
+		if err != nil {
+			result.Error = err.Error()
+		}
+	}
+
+	result.Duration = time.Since(start)
+	return result
+}
+
+// skip reports whether a workspace path is denied or allowlisted
+func (a *secretsAnalyzer) skip(rel string) bool {
+	for _, glob := range a.env.Policy.Security.DenyGlobs {
+		if policy.MatchGlob(glob, rel) {
+			return true
+		}
+	}
+	for _, glob := range a.opts.Allowlist {
+		if policy.MatchGlob(glob, rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// scan returns the secrets in a file's content
+func (d *secretDetector) scan(data []byte) []secretMatch {
+	var matches []secretMatch
+	for i, line := range strings.Split(string(data), "\n") {
+		var taken [][2]int
+		for r := range secretRules {
+			rule := &secretRules[r]
+			for _, loc := range rule.pattern.FindAllStringSubmatchIndex(line, -1) {
+				start, end := loc[0], loc[1]
+				if len(loc) > 2 && loc[2] >= 0 {
+					start, end = loc[2], loc[3]
+				}
+				if overlaps(taken, start, end) {
+					continue
+				}
+
+				secret := line[start:end]
+				if !d.accept(rule, secret) {
+					continue
+				}
+
+				taken = append(taken, [2]int{start, end})
+				mac := hmac.New(sha256.New, d.key)
+				mac.Write([]byte(secret))
+				matches = append(matches, secretMatch{
+					rule:   rule,
+					line:   i + 1,
+					column: start + 1,
+					masked: maskSecret(secret),
+					hash:   hex.EncodeToString(mac.Sum(nil)),
+				})
+			}
+		}
+	}
+	return matches
+}
+
+// secretKey returns the key for secret hashes: SecretKeyEnv when set,
+// otherwise a random key that only lasts for this process
+func secretKey() []byte {
+	if key := os.Getenv(SecretKeyEnv); key != "" {
+		return []byte(key)
+	}
+	return processSecretKey()
+}
+
+// SecretKeySet reports whether SecretKeyEnv is set, which secret
+// fingerprints need to match across runs
+func SecretKeySet() bool {
+	return os.Getenv(SecretKeyEnv) != ""
+}
+
+// accept applies the value allowlist, entropy and validation checks
+func (d *secretDetector) accept(rule *secretRule, secret string) bool {
+	for _, re := range d.allowValues {
+		if re.MatchString(secret) {
+			return false
+		}
+	}
+	if rule.entropy && shannonEntropy(secret) < d.minEntropy {
+		return false
+	}
+	if rule.validate != nil && !rule.validate(secret) {
+		return false
+	}
+	return true
+}
+
+// finding converts a match to a finding that only carries the masked value
+func (m secretMatch) finding(file string) Finding {
+	return Finding{
+		RuleID:      m.rule.id,
+		Message:     fmt.Sprintf("%s detected: %s", m.rule.description, m.masked),
+		Severity:    m.rule.severity,
+		File:        file,
+		Line:        m.line,
+		Column:      m.column,
+		Description: m.rule.description + " committed to the repository. Revoke it and load it from the environment or a secret manager.",
+		Confidence:  "high",
+		Tool:        "secrets",
+		Rule: &RuleMetadata{
+			Name:             strings.TrimPrefix(m.rule.id, "secrets/"),
+			ShortDescription: m.rule.description,
+			Tags:             []string{"secret", "CWE-798"},
+		},
+		// Identifies the same secret across files and commits
+		Fingerprints: map[string]string{secretHashKey: m.hash[:32]},
+	}
+}
+
+// overlaps reports whether [start, end) intersects an existing span
+func overlaps(spans [][2]int, start, end int) bool {
+	for _, s := range spans {
+		if start < s[1] && s[0] < end {
+			return true
+		}
+	}
+	return false
+}
+
+// maskSecret keeps a short prefix so that the credential can be found and
+// revoked without revealing it
+func maskSecret(secret string) string {
+	keep := 4
+	if strings.Contains(secret, " KEY") {
+		return secret // the PEM header carries no secret material
+	}
+	if len(secret) <= 2*keep {
+		return strings.Repeat("*", len(secret))
+	}
+	return secret[:keep] + strings.Repeat("*", 8)
+}
+
+// shannonEntropy returns the entropy of s in bits per character
+func shannonEntropy(s string) float64 {
+	if s == "" {
+		return 0
+	}
+	counts := make(map[rune]int)
+	for _, r := range s {
+		counts[r]++
+	}
+	n := float64(len([]rune(s)))
+	var h float64
+	for _, c := range counts {
+		p := float64(c) / n
+		h -= p * math.Log2(p)
+	}
+	return h
+}
+
+// isJWT checks that the token header decodes to JSON naming an algorithm
+func isJWT(token string) bool {
+	header, err := base64.RawURLEncoding.DecodeString(strings.SplitN(token, ".", 2)[0])
+	if err != nil {
+		return false
+	}
+	var h struct {
+		Alg string `json:"alg"`
+	}
+	return json.Unmarshal(header, &h) == nil && h.Alg != ""
+}
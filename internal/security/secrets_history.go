@@ -0,0 +1,136 @@
+package security
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DefaultHistoryCommits bounds the history walk when no limit is set
+const DefaultHistoryCommits = 1000
+
+// historyFormat separates commit headers from the --raw file lines
+const historyFormat = "--format=%x00%H%x00%an%x00%aI"
+
+// SecretHistoryOptions enables scanning blobs reachable from git refs
+type SecretHistoryOptions struct {
+	Enabled bool `yaml:"enabled"`
+	// Range is a revision range such as "origin/main..HEAD"; all refs
+	// are walked when empty
+	Range string `yaml:"range"`
+	// MaxCommits limits the walk to the most recent commits (default 1000)
+	MaxCommits int `yaml:"max_commits"`
+}
+
+// Commit identifies the commit that introduced a finding
+type Commit struct {
+	Hash   string `json:"hash"`
+	Author string `json:"author"`
+	Date   string `json:"date"`
+}
+
+// historyBlob is a file version first seen in commit
+type historyBlob struct {
+	hash   string
+	path   string
+	commit Commit
+}
+
+// logArgs returns the git log argv; it has a fixed shape so that one
+// allowlist entry with wildcards admits every range and limit, and
+// another all refs
+func (o SecretHistoryOptions) logArgs() []string {
+	max := o.MaxCommits
+	if max <= 0 {
+		max = DefaultHistoryCommits
+	}
+	revs := o.Range
+	if revs == "" {
+		revs = "--all"
+	}
+	return []string{"log", "--reverse", "--no-renames", "--raw", "--no-abbrev", historyFormat, "-n", strconv.Itoa(max), revs}
+}
+
+// scanHistory reports secrets in blobs added or modified by the selected
+// commits. Each blob is read once, and each secret is reported once per
+// path with the oldest commit that contains it.
+func (a *secretsAnalyzer) scanHistory(ctx context.Context) ([]Finding, error) {
+	opts := a.opts.History
+	log := a.env.Runner.Run(ctx, "git", opts.logArgs()...)
+	if log.Error != nil {
+		return nil, fmt.Errorf("git log: %v: %s", log.Error, tail(log.Stderr))
+	}
+
+	limits := a.env.Policy.Limits
+	seen := make(map[string]bool)
+	var findings []Finding
+	for _, blob := range parseHistory(log.Stdout) {
+		if err := ctx.Err(); err != nil {
+			return findings, err
+		}
+		if a.skip(blob.path) {
+			continue
+		}
+
+		content := a.env.Runner.Run(ctx, "git", "cat-file", "blob", blob.hash)
+		if content.Error != nil {
+			return findings, fmt.Errorf("git cat-file %s: %v", blob.hash, content.Error)
+		}
+		data := content.Stdout
+		if (limits.MaxFileBytes > 0 && len(data) > limits.MaxFileBytes) || bytes.IndexByte(data, 0) >= 0 {
+			continue
+		}
+
+		for _, m := range a.detector.scan(data) {
+			key := blob.path + "\x00" + m.hash
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			f := m.finding(blob.path)
+			commit := blob.commit
+			f.Commit = &commit
+			f.Message = fmt.Sprintf("%s (introduced in %.12s by %s on %s)", f.Message, commit.Hash, commit.Author, commit.Date)
+			findings = append(findings, f)
+		}
+	}
+	return findings, nil
+}
+
+// parseHistory reads git log --raw output and returns every added or
+// modified blob the first time it appears, oldest commit first
+func parseHistory(out []byte) []historyBlob {
+	var blobs []historyBlob
+	seen := make(map[string]bool)
+	var commit Commit
+
+	for _, line := range strings.Split(string(out), "\n") {
+		if strings.HasPrefix(line, "\x00") {
+			parts := strings.SplitN(line[1:], "\x00", 3)
+			if len(parts) == 3 {
+				commit = Commit{Hash: parts[0], Author: parts[1], Date: parts[2]}
+			}
+			continue
+		}
+
+		// :100644 100644 <old> <new> M\tpath
+		if !strings.HasPrefix(line, ":") {
+			continue
+		}
+		meta, path, ok := strings.Cut(line, "\t")
+		fields := strings.Fields(meta)
+		if !ok || len(fields) < 5 {
+			continue
+		}
+		hash, status := fields[3], fields[4]
+		if status == "D" || strings.Trim(hash, "0") == "" || seen[hash] {
+			continue
+		}
+		seen[hash] = true
+		blobs = append(blobs, historyBlob{hash: hash, path: path, commit: commit})
+	}
+	return blobs
+}
@@ -0,0 +1,278 @@
+package security
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Siddhant-K-code/sentinel-ai/internal/policy"
+)
+
+func init() {
+	Register("semgrep", newSemgrepAnalyzer, true)
+}
+
+// semgrepNetworkConfig is used when no configs are given and the mode
+// allows network access
+const semgrepNetworkConfig = "auto"
+
+// SemgrepOptions configures the rules Semgrep runs. A config is a rule
+// file or directory, relative to the workspace or absolute, such as a
+// vendored registry pack, or a registry reference (auto, p/golang,
+// r/..., a URL), which needs network access.
+type SemgrepOptions struct {
+	// Configs run over the whole workspace
+	Configs []string `yaml:"configs"`
+	// RuleSets run configs over selected paths only
+	RuleSets []SemgrepRuleSet `yaml:"rule_sets"`
+}
+
+// SemgrepRuleSet applies configs to workspace files or directories
+type SemgrepRuleSet struct {
+	Paths   []string `yaml:"paths"`
+	Configs []string `yaml:"configs"`
+}
+
+// semgrepRun is one invocation: a config over a target path
+type semgrepRun struct {
+	config string
+	target string
+}
+
+// semgrepAnalyzer runs Semgrep rules over the workspace
+type semgrepAnalyzer struct {
+	env  Env
+	runs []semgrepRun
+	err  error
+}
+
+// newSemgrepAnalyzer creates the Semgrep analyzer from its policy options.
+// Registry configs are dropped when the mode has no network access;
+// if nothing else is configured the analyzer is unavailable.
+func newSemgrepAnalyzer(env Env, cfg policy.AnalyzerConfig) (Analyzer, error) {
+	var opts SemgrepOptions
+	if err := cfg.Decode(&opts); err != nil {
+		return nil, err
+	}
+
+	network := env.Policy.Modes["default"].Network
+	sets := append([]SemgrepRuleSet{{Paths: []string{"."}, Configs: opts.Configs}}, opts.RuleSets...)
+	if len(opts.Configs) == 0 && len(opts.RuleSets) == 0 {
+		sets[0].Configs = []string{semgrepNetworkConfig}
+	}
+
+	a := &semgrepAnalyzer{env: env}
+	var skipped []string
+	for _, set := range sets {
+		for _, target := range set.Paths {
+			target = filepath.ToSlash(filepath.Clean(target))
+			if filepath.IsAbs(target) || target == ".." || strings.HasPrefix(target, "../") {
+				return nil, fmt.Errorf("rule_sets: path %s is outside the workspace", target)
+			}
+			for _, config := range set.Configs {
+				if isSemgrepRegistryConfig(config) && !network {
+					skipped = append(skipped, config)
+					continue
+				}
+				a.runs = append(a.runs, semgrepRun{config: config, target: target})
+			}
+		}
+	}
+
+	if len(a.runs) == 0 && len(skipped) > 0 {
+		a.err = fmt.Errorf("semgrep rule configs %s need network access, which the default mode disables; "+
+			"configure local rule files or directories in security.analyzers.semgrep.options.configs",
+			strings.Join(dedupeStrings(skipped), ", "))
+	}
+	return a, nil
+}
+
+// isSemgrepRegistryConfig reports whether a config is fetched from the
+// Semgrep registry or a URL rather than read from disk
+func isSemgrepRegistryConfig(config string) bool {
+	for _, prefix := range []string{"p/", "r/", "s/", "http://", "https://"} {
+		if strings.HasPrefix(config, prefix) {
+			return true
+		}
+	}
+	return config == "auto"
+}
+
+// dedupeStrings returns values without repeats, in order
+func dedupeStrings(values []string) []string {
+	seen := make(map[string]bool)
+	var out []string
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// Name returns the analyzer name
+func (a *semgrepAnalyzer) Name() string {
+	return "semgrep"
+}
+
+// Languages returns nil; Semgrep rules cover most languages
+func (a *semgrepAnalyzer) Languages() []string {
+	return nil
+}
+
+// Available checks that semgrep is installed and has rules it can use
+func (a *semgrepAnalyzer) Available() error {
+	if a.err != nil {
+		return a.err
+	}
+	if _, err := exec.LookPath("semgrep"); err != nil {
+		return errors.New("semgrep not found in PATH")
+	}
+	root, err := a.env.Runner.WorkDir("")
+	if err != nil {
+		return err
+	}
+	for _, run := range a.runs {
+		if isSemgrepRegistryConfig(run.config) {
+			continue
+		}
+		config := run.config
+		if !filepath.IsAbs(config) {
+			config = filepath.Join(root, config)
+		}
+		if _, err := os.Stat(config); err != nil {
+			return fmt.Errorf("semgrep config %s not found", run.config)
+		}
+	}
+	return nil
+}
+
+// Run runs every configured config over its target with metrics off and
+// merges the findings
+func (a *semgrepAnalyzer) Run(ctx context.Context) *ScanResult {
+	start := time.Now()
+	scan := &ScanResult{Tool: "semgrep", Findings: []Finding{}}
+
+	seen := make(map[string]bool)
+	var errs []string
+	for _, run := range a.runs {
+		findings, exitCode, err := a.runSemgrep(ctx, run)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s on %s: %v", run.config, run.target, err))
+			scan.ExitCode = exitCode
+			continue
+		}
+		for _, f := range findings {
+			// Overlapping rule sets report the same match once
+			key := fmt.Sprintf("%s\x00%s\x00%d\x00%d", f.RuleID, f.File, f.Line, f.Column)
+			if !seen[key] {
+				seen[key] = true
+				scan.Findings = append(scan.Findings, f)
+			}
+		}
+	}
+
+	scan.Error = strings.Join(errs, "; ")
+	scan.Duration = time.Since(start)
+	return scan
+}
+
+// runSemgrep runs one config; the runner executes in the workspace root
+func (a *semgrepAnalyzer) runSemgrep(ctx context.Context, run semgrepRun) ([]Finding, int, error) {
+	result := a.env.Runner.Run(ctx, "semgrep", "--metrics", "off", "--disable-version-check",
+		"--config", run.config, "--json", run.target)
+	if result.Error != nil {
+		return nil, result.ExitCode, result.Error
+	}
+
+	// Parse semgrep JSON output
+	var semgrepOutput struct {
+		Results []struct {
+			CheckID  string `json:"check_id"`
+			Path     string `json:"path"`
+			Start    struct {
+				Line   int `json:"line"`
+				Column int `json:"column"`
+			} `json:"start"`
+			End struct {
+				Line   int `json:"line"`
+				Column int `json:"column"`
+			} `json:"end"`
+			Extra struct {
+				Message     string `json:"message"`
+				Severity    string `json:"severity"`
+				Description string `json:"description"`
+				Confidence  string `json:"confidence"`
+				Metadata    struct {
+					CWE        semgrepStrings `json:"cwe"`
+					OWASP      semgrepStrings `json:"owasp"`
+					References semgrepStrings `json:"references"`
+					Confidence string         `json:"confidence"`
+				} `json:"metadata"`
+			} `json:"extra"`
+		} `json:"results"`
+	}
+
+	if err := json.Unmarshal(result.Stdout, &semgrepOutput); err != nil {
+		return nil, result.ExitCode, fmt.Errorf("failed to parse semgrep output: %v", err)
+	}
+
+	// Convert to our Finding format
+	var findings []Finding
+	for _, r := range semgrepOutput.Results {
+		// Paths are already relative to the workspace root
+		relPath := filepath.Clean(r.Path)
+
+		finding := Finding{
+			RuleID:      r.CheckID,
+			Message:     r.Extra.Message,
+			Severity:    strings.ToLower(r.Extra.Severity),
+			File:        relPath,
+			Line:        r.Start.Line,
+			Column:      r.Start.Column,
+			Description: r.Extra.Description,
+			Confidence:  strings.ToLower(r.Extra.Confidence),
+		}
+		if finding.Confidence == "" {
+			finding.Confidence = strings.ToLower(r.Extra.Metadata.Confidence)
+		}
+
+		// Keep CWE and OWASP tags so findings can be matched across tools
+		meta := r.Extra.Metadata
+		if len(meta.CWE) > 0 || len(meta.OWASP) > 0 || len(meta.References) > 0 {
+			finding.Rule = &RuleMetadata{Tags: append(append([]string{}, meta.CWE...), meta.OWASP...)}
+			if len(meta.References) > 0 {
+				finding.Rule.HelpURI = meta.References[0]
+			}
+		}
+
+		findings = append(findings, finding)
+	}
+
+	return findings, result.ExitCode, nil
+}
+
+// semgrepStrings accepts rule metadata given either as a string or a list
+type semgrepStrings []string
+
+// UnmarshalJSON implements json.Unmarshaler
+func (s *semgrepStrings) UnmarshalJSON(data []byte) error {
+	var list []string
+	if err := json.Unmarshal(data, &list); err == nil {
+		*s = list
+		return nil
+	}
+	var single string
+	if err := json.Unmarshal(data, &single); err != nil {
+		return err
+	}
+	*s = []string{single}
+	return nil
+}
@@ -0,0 +1,135 @@
+package security
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Siddhant-K-code/sentinel-ai/internal/policy"
+	"github.com/Siddhant-K-code/sentinel-ai/internal/tools"
+)
+
+// semgrepAllow is the default allowlist entry for semgrep
+var semgrepAllow = []string{"semgrep", "--metrics", "off", "--disable-version-check", "--config", "*", "--json", "*"}
+
+// fakeSemgrep installs a semgrep script on PATH that logs its arguments
+// and reports one finding named after the config in the target
+func fakeSemgrep(t *testing.T) (argsFile string) {
+	t.Helper()
+	bin := t.TempDir()
+	argsFile = filepath.Join(bin, "args")
+	script := `#!/bin/sh
+echo "$@" >> "` + argsFile + `"
+printf '{"results":[{"check_id":"%s","path":"%s/app.go","start":{"line":3,"column":2},"extra":{"message":"m","severity":"ERROR","metadata":{"cwe":"CWE-89"}}},` +
+		`{"check_id":"shared","path":"app.go","start":{"line":1,"column":1},"extra":{"message":"m","severity":"INFO"}}]}' "$5" "$7"
+`
+	if err := os.WriteFile(filepath.Join(bin, "semgrep"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return argsFile
+}
+
+func TestSemgrepLocalRuleSets(t *testing.T) {
+	argsFile := fakeSemgrep(t)
+
+	root := t.TempDir()
+	for _, dir := range []string{".semgrep", "rules/api", "services/api"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	env := Env{
+		Runner:    tools.NewRunner(root, [][]string{semgrepAllow}, 10*time.Second),
+		Workspace: root,
+		Policy:    policy.DefaultPolicy(),
+	}
+	analyzer, err := newSemgrepAnalyzer(env, policy.AnalyzerConfig{Enabled: true, Options: map[string]interface{}{
+		"configs": []interface{}{".semgrep", "p/golang"},
+		"rule_sets": []interface{}{map[string]interface{}{
+			"paths":   []interface{}{"services/api"},
+			"configs": []interface{}{"rules/api"},
+		}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := analyzer.Available(); err != nil {
+		t.Fatal(err)
+	}
+
+	result := analyzer.Run(context.Background())
+	if result.Error != "" {
+		t.Fatal(result.Error)
+	}
+
+	// The registry config is skipped without network access
+	data, err := os.ReadFile(argsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "--metrics off --disable-version-check --config .semgrep --json .\n" +
+		"--metrics off --disable-version-check --config rules/api --json services/api\n"
+	if string(data) != want {
+		t.Errorf("Expected semgrep runs:\n%s\ngot:\n%s", want, data)
+	}
+
+	var got []string
+	for _, f := range result.Findings {
+		got = append(got, f.RuleID+":"+filepath.ToSlash(f.File))
+	}
+	wantFindings := []string{".semgrep:app.go", "shared:app.go", "rules/api:services/api/app.go"}
+	if strings.Join(got, " ") != strings.Join(wantFindings, " ") {
+		t.Errorf("Expected findings %v, got %v", wantFindings, got)
+	}
+}
+
+func TestSemgrepNetworkConfigs(t *testing.T) {
+	fakeSemgrep(t)
+	root := t.TempDir()
+
+	pol := policy.DefaultPolicy()
+	env := Env{Runner: tools.NewRunner(root, [][]string{semgrepAllow}, 10*time.Second), Workspace: root, Policy: pol}
+
+	// Without configs semgrep falls back to auto, which needs network
+	analyzer, err := newSemgrepAnalyzer(env, policy.AnalyzerConfig{Enabled: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := analyzer.Available(); err == nil || !strings.Contains(err.Error(), "auto need network access") {
+		t.Errorf("Expected network error, got %v", err)
+	}
+
+	mode := pol.Modes["default"]
+	mode.Network = true
+	pol.Modes = map[string]policy.Mode{"default": mode}
+	env.Policy = pol
+	analyzer, err = newSemgrepAnalyzer(env, policy.AnalyzerConfig{Enabled: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := analyzer.Available(); err != nil {
+		t.Errorf("Expected auto to be usable with network, got %v", err)
+	}
+
+	// Missing local configs and targets outside the workspace are errors
+	analyzer, err = newSemgrepAnalyzer(env, policy.AnalyzerConfig{Enabled: true, Options: map[string]interface{}{
+		"configs": []interface{}{"missing-rules"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := analyzer.Available(); err == nil || !strings.Contains(err.Error(), "missing-rules not found") {
+		t.Errorf("Expected missing config error, got %v", err)
+	}
+	_, err = newSemgrepAnalyzer(env, policy.AnalyzerConfig{Enabled: true, Options: map[string]interface{}{
+		"rule_sets": []interface{}{map[string]interface{}{"paths": []interface{}{"../other"}, "configs": []interface{}{"r"}}},
+	}})
+	if err == nil {
+		t.Error("Expected error for a rule set outside the workspace")
+	}
+}
@@ -0,0 +1,204 @@
+package security
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Siddhant-K-code/sentinel-ai/internal/policy"
+	"github.com/Siddhant-K-code/sentinel-ai/internal/vulndb"
+)
+
+// Canonical severities, from most to least severe
+const (
+	SeverityCritical = "critical"
+	SeverityHigh     = "high"
+	SeverityMedium   = "medium"
+	SeverityLow      = "low"
+	SeverityInfo     = "info"
+)
+
+// Severities lists the canonical severities, most severe first
+var Severities = policy.Severities
+
+// securitySeverityKey is the rule property CodeQL and GitHub use for a
+// numeric, CVSS-like severity
+const securitySeverityKey = "security-severity"
+
+// NormalizeSeverity maps a severity as reported by a tool onto the
+// canonical scale: SARIF levels, semgrep's ERROR/WARNING/INFO, advisory
+// ratings such as MODERATE, and numeric scores from 0 to 10. Unknown
+// values are treated as medium.
+func NormalizeSeverity(severity string) string {
+	s := strings.ToLower(strings.TrimSpace(severity))
+	switch s {
+	case SeverityCritical, SeverityHigh, SeverityMedium, SeverityLow, SeverityInfo:
+		return s
+	case "error":
+		return SeverityHigh
+	case "warning", "moderate":
+		return SeverityMedium
+	case "note", "recommendation":
+		return SeverityLow
+	case "none", "informational":
+		return SeverityInfo
+	}
+	if score, err := strconv.ParseFloat(s, 64); err == nil && score >= 0 && score <= 10 {
+		return scoreSeverity(score)
+	}
+	return SeverityMedium
+}
+
+// IsSeverity reports whether severity is on the canonical scale
+func IsSeverity(severity string) bool {
+	for _, s := range Severities {
+		if severity == s {
+			return true
+		}
+	}
+	return false
+}
+
+// scoreSeverity rates a CVSS score, with 0 as info
+func scoreSeverity(score float64) string {
+	if level := vulndb.CVSSLevel(score); level != "" {
+		return level
+	}
+	return SeverityInfo
+}
+
+// validateSeverityOverrides checks that overrides use the canonical scale
+// and valid rule ID patterns
+func validateSeverityOverrides(overrides map[string]string) error {
+	for rule, severity := range overrides {
+		if _, err := path.Match(rule, ""); err != nil {
+			return fmt.Errorf("severity override %q: %w", rule, err)
+		}
+		if !IsSeverity(severity) {
+			return fmt.Errorf("severity override %q: %q is not one of %s", rule, severity, strings.Join(Severities, ", "))
+		}
+	}
+	return nil
+}
+
+// severityOverride returns the policy severity for a rule. An exact rule
+// ID wins over patterns, and longer patterns over shorter ones.
+func severityOverride(overrides map[string]string, ruleID string) (string, bool) {
+	if severity, ok := overrides[ruleID]; ok {
+		return severity, true
+	}
+	patterns := make([]string, 0, len(overrides))
+	for pattern := range overrides {
+		patterns = append(patterns, pattern)
+	}
+	sort.Slice(patterns, func(i, j int) bool {
+		if len(patterns[i]) != len(patterns[j]) {
+			return len(patterns[i]) > len(patterns[j])
+		}
+		return patterns[i] < patterns[j]
+	})
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, ruleID); ok {
+			return overrides[pattern], true
+		}
+	}
+	return "", false
+}
+
+// classify sets a finding's canonical severity and its classification.
+// The severity comes from a policy override, else from the rule's
+// security-severity score, else from the tool's severity.
+func classify(f *Finding, overrides map[string]string) {
+	c := f.Classification
+	if c == nil {
+		c = &Classification{}
+	}
+	info := lookupRuleInfo(f.RuleID)
+
+	c.CWE = mergeStrings(c.CWE, f.CWEs(), info.CWE)
+
+	var tags []string
+	if f.Rule != nil {
+		tags = f.Rule.Tags
+	}
+	c.OWASP = mergeStrings(c.OWASP, owaspTags(tags), info.OWASP)
+	if f.Tool == "osv" {
+		c.OWASP = mergeStrings(c.OWASP, []string{owaspVulnerableComponents})
+	}
+	for _, cwe := range c.CWE {
+		if category, ok := cweOWASP[cwe]; ok {
+			c.OWASP = mergeStrings(c.OWASP, []string{category})
+		}
+	}
+
+	score, scored := securitySeverity(f.Rule)
+	if c.CVSS == 0 {
+		if scored {
+			c.CVSS = score
+		} else {
+			c.CVSS = info.CVSS
+		}
+	}
+
+	if len(c.CWE) > 0 || len(c.OWASP) > 0 || c.CVSS > 0 {
+		f.Classification = c
+	}
+
+	severity := NormalizeSeverity(f.Severity)
+	if scored && !IsSeverity(strings.ToLower(f.Severity)) {
+		severity = scoreSeverity(score)
+	}
+	if override, ok := severityOverride(overrides, f.RuleID); ok {
+		severity = override
+	}
+	if severity != f.Severity {
+		if f.ToolSeverity == "" {
+			f.ToolSeverity = f.Severity
+		}
+		f.Severity = severity
+	}
+}
+
+// securitySeverity returns the rule's numeric security-severity property
+func securitySeverity(rule *RuleMetadata) (float64, bool) {
+	if rule == nil {
+		return 0, false
+	}
+	var score float64
+	switch v := rule.Properties[securitySeverityKey].(type) {
+	case string:
+		s, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return 0, false
+		}
+		score = s
+	case float64:
+		score = v
+	case int:
+		score = float64(v)
+	default:
+		return 0, false
+	}
+	return score, score >= 0 && score <= 10
+}
+
+// mergeStrings appends the values of more that are not in list yet
+func mergeStrings(list []string, more ...[]string) []string {
+	for _, values := range more {
+		for _, v := range values {
+			found := false
+			for _, have := range list {
+				if have == v {
+					found = true
+					break
+				}
+			}
+			if !found {
+				list = append(list, v)
+			}
+		}
+	}
+	return list
+}
@@ -0,0 +1,153 @@
+package security
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Siddhant-K-code/sentinel-ai/internal/policy"
+	"github.com/Siddhant-K-code/sentinel-ai/internal/tools"
+)
+
+func TestNormalizeSeverity(t *testing.T) {
+	tests := map[string]string{
+		"ERROR":          "high",
+		"warning":        "medium",
+		"INFO":           "info",
+		"note":           "low",
+		"recommendation": "low",
+		"none":           "info",
+		"MODERATE":       "medium",
+		"critical":       "critical",
+		"9.8":            "critical",
+		"7.5":            "high",
+		"4.0":            "medium",
+		"2.1":            "low",
+		"0":              "info",
+		"bogus":          "medium",
+	}
+	for in, want := range tests {
+		if got := NormalizeSeverity(in); got != want {
+			t.Errorf("NormalizeSeverity(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name     string
+		finding  Finding
+		severity string
+		want     Classification
+	}{
+		{
+			name: "codeql security-severity",
+			finding: Finding{RuleID: "go/command-injection", Severity: "error", Rule: &RuleMetadata{
+				Tags:       []string{"security", "external/cwe/cwe-078"},
+				Properties: map[string]interface{}{"security-severity": "9.8"},
+			}},
+			severity: "critical",
+			want:     Classification{CWE: []string{"CWE-78"}, OWASP: []string{owaspInjection}, CVSS: 9.8},
+		},
+		{
+			name: "semgrep metadata",
+			finding: Finding{RuleID: "python.flask.xss", Severity: "warning", Rule: &RuleMetadata{
+				Tags: []string{"CWE-79: Improper Neutralization", "A03:2021 - Injection", "A07:2017 - Cross-Site Scripting (XSS)"},
+			}},
+			severity: "medium",
+			want:     Classification{CWE: []string{"CWE-79"}, OWASP: []string{owaspInjection, "A07:2017"}},
+		},
+		{
+			name:     "bundled table",
+			finding:  Finding{RuleID: "secrets/github-token", Severity: "error"},
+			severity: "high",
+			want:     Classification{CWE: []string{"CWE-798"}, OWASP: []string{owaspAuthentication}, CVSS: 9.8},
+		},
+		{
+			name:     "advisory",
+			finding:  Finding{RuleID: "GO-2024-0001", Tool: "osv", Severity: "high", Classification: &Classification{CVSS: 7.2}},
+			severity: "high",
+			want:     Classification{OWASP: []string{owaspVulnerableComponents}, CVSS: 7.2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := tt.finding
+			classify(&f, nil)
+			if f.Severity != tt.severity {
+				t.Errorf("Expected severity %q, got %q", tt.severity, f.Severity)
+			}
+			if f.Classification == nil || !reflect.DeepEqual(*f.Classification, tt.want) {
+				t.Errorf("Expected classification %+v, got %+v", tt.want, f.Classification)
+			}
+		})
+	}
+
+	// Findings nothing is known about are only normalized
+	f := Finding{RuleID: "custom", Severity: "note"}
+	classify(&f, nil)
+	if f.Classification != nil || f.Severity != "low" || f.ToolSeverity != "note" {
+		t.Errorf("Unexpected classification of an unknown rule: %+v", f)
+	}
+}
+
+func TestSeverityOverrides(t *testing.T) {
+	overrides := map[string]string{
+		"iac/*":                  "low",
+		"iac/k8s-privileged":     "critical",
+		"go/sql-injection":       "critical",
+		"go.lang.security.*.xss": "info",
+	}
+	tests := map[string]string{
+		"iac/k8s-privileged":         "critical",
+		"iac/k8s-latest-tag":         "low",
+		"go/sql-injection":           "critical",
+		"go.lang.security.audit.xss": "info",
+		"go/xss":                     "high",
+	}
+	for rule, want := range tests {
+		f := Finding{RuleID: rule, Severity: "error"}
+		classify(&f, overrides)
+		if f.Severity != want {
+			t.Errorf("Expected %s to be %s, got %s", rule, want, f.Severity)
+		}
+	}
+
+	pol := policy.DefaultPolicy()
+	pol.Security.SeverityOverrides = map[string]string{"go/xss": "urgent"}
+	s := &Scanner{runner: tools.NewRunner(t.TempDir(), nil, time.Second)}
+	if err := s.Configure(pol, nil); err == nil || !strings.Contains(err.Error(), `"urgent" is not one of`) {
+		t.Errorf("Expected invalid severity error, got %v", err)
+	}
+
+	pol.Security.SeverityOverrides = map[string]string{"go/xss": "info"}
+	if err := s.Configure(pol, map[string]policy.AnalyzerConfig{}); err != nil {
+		t.Fatal(err)
+	}
+	s.analyzers = nil
+	s.AddAnalyzer(&fakeAnalyzer{name: "gotaint", findings: []Finding{{RuleID: "go/xss", File: "main.go", Line: 1, Severity: "error"}}})
+	results := scanAll(context.Background(), s)
+	if got := results[0].Findings[0]; got.Severity != "info" || got.ToolSeverity != "error" {
+		t.Errorf("Expected policy override to info, got %q from %q", got.Severity, got.ToolSeverity)
+	}
+}
+
+func TestClassificationSARIF(t *testing.T) {
+	f := Finding{RuleID: "go/sql-injection", File: "main.go", Line: 3, Severity: "error", Rule: &RuleMetadata{Tags: []string{"CWE-89"}}}
+	classify(&f, nil)
+
+	s := NewScanner(tools.NewRunner(t.TempDir(), nil, time.Second), ".")
+	rule := s.ruleToSARIF(f)
+	if rule.Properties["security-severity"] != "8.8" {
+		t.Errorf("Expected security-severity from the bundled CVSS score, got %v", rule.Properties["security-severity"])
+	}
+	want := []string{"security", "CWE-89", owaspInjection}
+	if tags := rule.Properties["tags"]; !reflect.DeepEqual(tags, want) {
+		t.Errorf("Expected tags %v, got %v", want, tags)
+	}
+	if rule.DefaultConfiguration.Level != "error" {
+		t.Errorf("Expected high to be a SARIF error, got %s", rule.DefaultConfiguration.Level)
+	}
+}
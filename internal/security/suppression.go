@@ -0,0 +1,73 @@
+package security
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/Siddhant-K-code/sentinel-ai/internal/suppress"
+)
+
+// SuppressionTool names the result that reports unusable suppressions
+const SuppressionTool = "sentinel-suppressions"
+
+// Actionable reports whether a finding should fail a scan: it is neither
+// suppressed inline nor a baseline entry that is gone
+func (f Finding) Actionable() bool {
+	return f.Suppression == nil && f.BaselineState != "absent"
+}
+
+// applySuppressions attaches inline suppressions to the findings they
+// cover. A suppression may name the rule ID or one of the finding's CWEs.
+// Suppressions that are expired or lack a reason or expiry are returned
+// as findings so that they cannot silently hide anything.
+func applySuppressions(results []ScanResult, root string) *ScanResult {
+	start := time.Now()
+	ix, err := suppress.Load(root, time.Now())
+	if err != nil {
+		return &ScanResult{
+			Tool:     SuppressionTool,
+			Findings: []Finding{},
+			Duration: time.Since(start),
+			Error:    err.Error(),
+		}
+	}
+
+	for ri := range results {
+		for fi := range results[ri].Findings {
+			f := &results[ri].Findings[fi]
+			rules := append([]string{f.RuleID}, f.CWEs()...)
+			f.Suppression = ix.Lookup(f.File, f.Line, rules...)
+		}
+	}
+
+	problems := ix.Problems()
+	if len(problems) == 0 {
+		return nil
+	}
+
+	fp := newFingerprinter(root)
+	findings := make([]Finding, 0, len(problems))
+	for _, p := range problems {
+		subject := "Suppression"
+		if p.Rule != "" {
+			subject += " of " + p.Rule
+		}
+		finding := Finding{
+			RuleID:      "sentinel/invalid-suppression",
+			Message:     subject + " is not honored: " + p.Issue,
+			Severity:    "warning",
+			File:        filepath.FromSlash(p.File),
+			Line:        p.Line,
+			Description: "Inline suppressions need a rule ID, a reason and an until date that has not passed.",
+			Tool:        SuppressionTool,
+		}
+		finding.Fingerprint = fp.Fingerprint(finding)
+		finding.Tools = []string{SuppressionTool}
+		findings = append(findings, finding)
+	}
+	return &ScanResult{
+		Tool:     SuppressionTool,
+		Findings: findings,
+		Duration: time.Since(start),
+	}
+}
@@ -0,0 +1,70 @@
+package security
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Siddhant-K-code/sentinel-ai/internal/tools"
+)
+
+func TestScanAppliesSuppressions(t *testing.T) {
+	workspace := t.TempDir()
+	src := `package main
+
+func run(cmd string) {
+	exec(cmd) // sentinel:ignore go/command-injection reason="cmd comes from the allowlist" until=2999-01-01
+	exec(cmd) // sentinel:ignore go/command-injection until=2999-01-01
+}
+`
+	if err := os.WriteFile(filepath.Join(workspace, "main.go"), []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	runner := tools.NewRunner(workspace, nil, time.Second)
+	scanner := &Scanner{runner: runner, workspace: workspace}
+	scanner.AddAnalyzer(&fakeAnalyzer{
+		name: "codeql",
+		findings: []Finding{
+			{RuleID: "go/command-injection", File: "main.go", Line: 4, Severity: "error"},
+			{RuleID: "go/command-injection", File: "main.go", Line: 5, Severity: "error"},
+		},
+	})
+
+	results := scanAll(context.Background(), scanner)
+	if len(results) != 2 || results[1].Tool != SuppressionTool {
+		t.Fatalf("Expected codeql and suppression results, got %+v", results)
+	}
+
+	findings := results[0].Findings
+	if findings[0].Suppression == nil || findings[0].Actionable() {
+		t.Errorf("Expected first finding to be suppressed, got %+v", findings[0])
+	}
+	if findings[1].Suppression != nil || !findings[1].Actionable() {
+		t.Errorf("Expected reason-less suppression to be ignored, got %+v", findings[1])
+	}
+	if problems := results[1].Findings; len(problems) != 1 || problems[0].Line != 5 || !problems[0].Actionable() {
+		t.Errorf("Expected the reason-less suppression to be reported, got %+v", problems)
+	}
+
+	data, err := scanner.GenerateSARIF(results)
+	if err != nil {
+		t.Fatal(err)
+	}
+	validateSARIF(t, data)
+
+	var log sarifLog
+	if err := json.Unmarshal(data, &log); err != nil {
+		t.Fatal(err)
+	}
+	suppressions := log.Runs[0].Results[0].Suppressions
+	if len(suppressions) != 1 || suppressions[0].Kind != "inSource" || suppressions[0].Justification != "cmd comes from the allowlist" {
+		t.Errorf("Expected inSource suppression in SARIF, got %+v", suppressions)
+	}
+	if len(log.Runs[0].Results[1].Suppressions) != 0 {
+		t.Error("Expected no suppression on the unsuppressed result")
+	}
+}
@@ -0,0 +1,234 @@
+package suppress
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Marker starts a suppression comment, e.g.
+//
+//	// sentinel:ignore go/sql-injection reason="query is constant" until=2027-01-01
+//
+// A comment on its own line applies to the next line that is not a
+// comment; a trailing comment applies to its own line.
+const Marker = "sentinel:ignore"
+
+// DateLayout is the format of the until attribute
+const DateLayout = "2006-01-02"
+
+// maxFileBytes skips large files, which are rarely hand-written
+const maxFileBytes = 1 << 20
+
+// skipDirs are never searched for suppressions
+var skipDirs = map[string]bool{
+	".git":         true,
+	".sentinel":    true,
+	"node_modules": true,
+	"vendor":       true,
+}
+
+var (
+	directive = regexp.MustCompile(Marker + `(?:\s+([^\s"=]+))?(.*)$`)
+	attribute = regexp.MustCompile(`(\w+)=(?:"((?:[^"\\]|\\.)*)"|(\S+))`)
+)
+
+// commentPrefixes are the tokens that may precede a suppression
+var commentPrefixes = []string{"//", "#", "/*", "--", "<!--", "*"}
+
+// Suppression is a valid, unexpired suppression comment
+type Suppression struct {
+	Rule   string    `json:"rule"`
+	Reason string    `json:"reason"`
+	Until  time.Time `json:"until"`
+	File   string    `json:"file"`   // slash-separated, relative to the root
+	Line   int       `json:"line"`   // line of the comment
+	Target int       `json:"target"` // line the suppression applies to
+}
+
+// Problem is a suppression comment that is not honored
+type Problem struct {
+	Suppression
+	Issue string `json:"issue"`
+}
+
+// Index holds the suppressions of a tree
+type Index struct {
+	byFile   map[string][]Suppression
+	problems []Problem
+}
+
+// Load finds suppression comments under root. Suppressions without a
+// reason or expiry date, or that expired before now, are not honored
+// and are returned by Problems instead.
+func Load(root string, now time.Time) (*Index, error) {
+	ix := &Index{byFile: make(map[string][]Suppression)}
+	today := now.Truncate(24 * time.Hour)
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != root && skipDirs[d.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		if info, err := d.Info(); err != nil || info.Size() > maxFileBytes {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil || !bytes.Contains(data, []byte(Marker)) || bytes.IndexByte(data, 0) >= 0 {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		ix.parse(filepath.ToSlash(rel), string(data), today)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ix, nil
+}
+
+// parse records the suppressions of one file
+func (ix *Index) parse(file, content string, today time.Time) {
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		at := strings.Index(line, Marker)
+		if at < 0 {
+			continue
+		}
+		prefix := strings.TrimSpace(line[:at])
+		ownLine, ok := commentOnly(prefix)
+		if !ok {
+			continue
+		}
+
+		s := Suppression{File: file, Line: i + 1, Target: i + 1}
+		if ownLine {
+			s.Target = nextCodeLine(lines, i)
+		}
+
+		m := directive.FindStringSubmatch(line[at:])
+		s.Rule = m[1]
+		var issues []string
+		if s.Rule == "" {
+			issues = append(issues, "missing rule id")
+		}
+
+		for _, attr := range attribute.FindAllStringSubmatch(m[2], -1) {
+			value := attr[3]
+			if attr[2] != "" || value == "" {
+				value = strings.ReplaceAll(attr[2], `\"`, `"`)
+			}
+			switch attr[1] {
+			case "reason":
+				s.Reason = strings.TrimSpace(value)
+			case "until":
+				until, err := time.Parse(DateLayout, value)
+				if err != nil {
+					issues = append(issues, fmt.Sprintf("invalid until date %q", value))
+					continue
+				}
+				s.Until = until
+			}
+		}
+
+		if s.Reason == "" {
+			issues = append(issues, "missing reason")
+		}
+		switch {
+		case s.Until.IsZero() && !strings.Contains(m[2], "until="):
+			issues = append(issues, "missing until date")
+		case !s.Until.IsZero() && s.Until.Before(today):
+			issues = append(issues, "expired on "+s.Until.Format(DateLayout))
+		}
+
+		if len(issues) > 0 {
+			ix.problems = append(ix.problems, Problem{Suppression: s, Issue: strings.Join(issues, "; ")})
+			continue
+		}
+		ix.byFile[file] = append(ix.byFile[file], s)
+	}
+}
+
+// commentOnly reports whether the text before the marker is a comment
+// opener, and whether that comment starts the line
+func commentOnly(prefix string) (ownLine, ok bool) {
+	for _, p := range commentPrefixes {
+		if prefix == p {
+			return true, true
+		}
+	}
+	for _, p := range commentPrefixes[:len(commentPrefixes)-1] {
+		if strings.HasSuffix(prefix, p) {
+			return false, true
+		}
+	}
+	return false, false
+}
+
+// nextCodeLine returns the 1-based number of the first line after i that
+// is neither blank nor a comment
+func nextCodeLine(lines []string, i int) int {
+	for j := i + 1; j < len(lines); j++ {
+		text := strings.TrimSpace(lines[j])
+		if text == "" {
+			continue
+		}
+		comment := false
+		for _, p := range commentPrefixes {
+			if strings.HasPrefix(text, p) {
+				comment = true
+				break
+			}
+		}
+		if !comment {
+			return j + 1
+		}
+	}
+	return i + 1
+}
+
+// Lookup returns the suppression covering line of file for any of the
+// given rule identifiers, or nil
+func (ix *Index) Lookup(file string, line int, rules ...string) *Suppression {
+	if ix == nil {
+		return nil
+	}
+	file = strings.TrimPrefix(filepath.ToSlash(filepath.Clean(file)), "./")
+	for i, s := range ix.byFile[file] {
+		if s.Target != line && s.Line != line {
+			continue
+		}
+		for _, rule := range rules {
+			if strings.EqualFold(s.Rule, rule) {
+				return &ix.byFile[file][i]
+			}
+		}
+	}
+	return nil
+}
+
+// Problems returns the suppressions that are not honored
+func (ix *Index) Problems() []Problem {
+	if ix == nil {
+		return nil
+	}
+	return ix.problems
+}
@@ -0,0 +1,86 @@
+package suppress
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const source = `package main
+
+// sentinel:ignore go/sql-injection reason="query is built from constants" until=2027-01-01
+// runQuery executes the report query
+func runQuery() {}
+
+func handler() {
+	exec(cmd) // sentinel:ignore CWE-78 reason="command is allowlisted" until=2027-01-01
+	a() // sentinel:ignore go/xss until=2027-01-01
+	b() // sentinel:ignore go/xss reason="legacy" until=2026-01-01
+	c() // sentinel:ignore go/xss reason="no expiry"
+	d() // sentinel:ignore go/xss reason="bad date" until=soon
+	s := "sentinel:ignore go/xss reason=\"not a comment\" until=2027-01-01"
+}
+`
+
+func load(t *testing.T) *Index {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "cmd"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "cmd", "main.go"), []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	ix, err := Load(root, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ix
+}
+
+func TestLookup(t *testing.T) {
+	ix := load(t)
+
+	s := ix.Lookup("cmd/main.go", 5, "go/sql-injection")
+	if s == nil {
+		t.Fatal("Expected own-line suppression to cover the next code line")
+	}
+	if s.Reason != "query is built from constants" || s.Until.Format(DateLayout) != "2027-01-01" || s.Line != 3 {
+		t.Errorf("Unexpected suppression %+v", s)
+	}
+
+	if ix.Lookup("./cmd/main.go", 8, "tainted-exec", "CWE-78") == nil {
+		t.Error("Expected trailing suppression to match by CWE")
+	}
+	if ix.Lookup("cmd/main.go", 8, "go/xss") != nil {
+		t.Error("Expected suppression not to match another rule")
+	}
+	if ix.Lookup("cmd/main.go", 13, "go/xss") != nil {
+		t.Error("Expected marker inside a string literal to be ignored")
+	}
+}
+
+func TestProblems(t *testing.T) {
+	ix := load(t)
+
+	want := map[int]string{
+		9:  "missing reason",
+		10: "expired on 2026-01-01",
+		11: "missing until date",
+		12: `invalid until date "soon"`,
+	}
+	problems := ix.Problems()
+	if len(problems) != len(want) {
+		t.Fatalf("Expected %d problems, got %+v", len(want), problems)
+	}
+	for _, p := range problems {
+		if !strings.Contains(p.Issue, want[p.Line]) {
+			t.Errorf("Line %d: expected issue %q, got %q", p.Line, want[p.Line], p.Issue)
+		}
+		if ix.Lookup(p.File, p.Line, p.Rule) != nil {
+			t.Errorf("Line %d: invalid suppression must not be honored", p.Line)
+		}
+	}
+}
@@ -0,0 +1,336 @@
+package tools
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Siddhant-K-code/sentinel-ai/internal/cache"
+)
+
+// cacheFormat is bumped whenever the cached entry layout changes
+const cacheFormat = "run-v3"
+
+// cacheEnv lists, per command, the environment variables that change
+// what it builds or how, such as the target platform and build tags in
+// GOFLAGS. Every workspace file is hashed as well, since tests also read
+// testdata, embedded files and fixtures.
+var cacheEnv = map[string][]string{
+	"go":    {"GOFLAGS", "GOOS", "GOARCH", "GOAMD64", "GOARM", "GOARM64", "CGO_ENABLED", "GOEXPERIMENT", "GOTOOLCHAIN", "GOWORK"},
+	"cargo": {"RUSTFLAGS", "CARGO_BUILD_TARGET", "CARGO_ENCODED_RUSTFLAGS"},
+}
+
+// uncachedCommands manage their own persistent state outside the
+// workspace, or in .git which is not hashed, so replaying their output
+// alone would be wrong
+var uncachedCommands = map[string]bool{
+	"codeql": true,
+	"git":    true,
+}
+
+// cacheSkipDirs are never part of the input hash: version control, our
+// own state, and the build output and tool caches that commands write
+var cacheSkipDirs = map[string]bool{
+	".git":          true,
+	".sentinel":     true,
+	"target":        true,
+	"node_modules":  true,
+	"__pycache__":   true,
+	".pytest_cache": true,
+	".mypy_cache":   true,
+}
+
+// cachedRun is the stored form of a RunResult. Errors are kept as text,
+// so a cached result never carries an *exec.ExitError; Exited records a
+// non-zero exit instead.
+type cachedRun struct {
+	Stdout   []byte            `json:"stdout"`
+	Stderr   []byte            `json:"stderr"`
+	ExitCode int               `json:"exit_code"`
+	Exited   bool              `json:"exited"`
+	Duration time.Duration     `json:"duration"`
+	Error    string            `json:"error,omitempty"`
+	Outputs  map[string][]byte `json:"outputs,omitempty"`
+}
+
+// EnableCache makes Run reuse results for unchanged inputs
+func (r *Runner) EnableCache(c *cache.Cache) {
+	r.Cache = c
+}
+
+// cacheKey hashes the argv, working directory, tool binary, environment
+// and inputs, including files the arguments name outside the workspace
+func (r *Runner) cacheKey(root, workDir, cmd string, args []string) (string, error) {
+	if uncachedCommands[cmd] {
+		return "", fmt.Errorf("%s results are not cached", cmd)
+	}
+
+	version, err := toolVersion(cmd)
+	if err != nil {
+		return "", err
+	}
+
+	inputs, err := hashInputs(workDir, nil, outputFiles(args))
+	if err != nil {
+		return "", err
+	}
+
+	external, err := hashArgFiles(workDir, args)
+	if err != nil {
+		return "", err
+	}
+
+	rel, err := filepath.Rel(root, workDir)
+	if err != nil {
+		return "", err
+	}
+
+	var env []string
+	for _, name := range cacheEnv[cmd] {
+		env = append(env, name+"="+os.Getenv(name))
+	}
+
+	argv := strings.Join(append([]string{cmd}, args...), "\x00")
+	return cache.Key(cacheFormat, argv, filepath.ToSlash(rel), version, strings.Join(env, "\x00"), inputs, external), nil
+}
+
+// loadCached returns the cached result for key and restores its outputs
+func (r *Runner) loadCached(key, workDir string) (*RunResult, bool) {
+	data, ok := r.Cache.Get(key)
+	if !ok {
+		return nil, false
+	}
+
+	var entry cachedRun
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, false
+	}
+
+	for name, content := range entry.Outputs {
+		path := filepath.Join(workDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return nil, false
+		}
+		if err := os.WriteFile(path, content, 0644); err != nil {
+			return nil, false
+		}
+	}
+
+	result := &RunResult{
+		Stdout:   entry.Stdout,
+		Stderr:   entry.Stderr,
+		ExitCode: entry.ExitCode,
+		Duration: entry.Duration,
+		Exited:   entry.Exited,
+		Cached:   true,
+	}
+	if entry.Error != "" {
+		result.Error = errors.New(entry.Error)
+	}
+
+	return result, true
+}
+
+// storeCached saves a completed result together with its output files
+func (r *Runner) storeCached(key, workDir string, args []string, result *RunResult) error {
+	entry := cachedRun{
+		Stdout:   result.Stdout,
+		Stderr:   result.Stderr,
+		ExitCode: result.ExitCode,
+		Exited:   result.Exited,
+		Duration: result.Duration,
+	}
+	if result.Error != nil {
+		entry.Error = result.Error.Error()
+	}
+
+	for _, name := range outputFiles(args) {
+		content, err := os.ReadFile(filepath.Join(workDir, name))
+		if err != nil {
+			continue
+		}
+		if entry.Outputs == nil {
+			entry.Outputs = make(map[string][]byte)
+		}
+		entry.Outputs[name] = content
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	return r.Cache.Put(key, data)
+}
+
+// cacheable reports whether a result is deterministic enough to reuse:
+// the process ran to completion and was not cut short by the context
+func cacheable(ctx context.Context, result *RunResult) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if result.Error == nil {
+		return true
+	}
+	var exitErr *exec.ExitError
+	return errors.As(result.Error, &exitErr)
+}
+
+// toolVersion identifies the installed binary by path, size and mtime,
+// which changes on upgrade without having to execute the tool
+func toolVersion(cmd string) (string, error) {
+	path, err := exec.LookPath(cmd)
+	if err != nil {
+		return "", err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s:%d:%d", path, info.Size(), info.ModTime().UnixNano()), nil
+}
+
+// outputFiles returns the files a command writes that must be restored
+// on a cache hit
+func outputFiles(args []string) []string {
+	var outputs []string
+	for _, arg := range args {
+		if name, ok := strings.CutPrefix(arg, "-coverprofile="); ok {
+			outputs = append(outputs, filepath.Clean(name))
+		}
+	}
+	return outputs
+}
+
+// hashArgFiles hashes the files and directories that args name outside
+// workDir, such as a semgrep --config rule file; files inside it are
+// already part of the input hash
+func hashArgFiles(workDir string, args []string) (string, error) {
+	h := sha256.New()
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "-") {
+			_, arg, _ = strings.Cut(arg, "=")
+		}
+		if arg == "" {
+			continue
+		}
+		path := arg
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(workDir, path)
+		}
+		if rel, err := filepath.Rel(workDir, path); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+
+		var sum string
+		if info.IsDir() {
+			sum, err = hashInputs(path, nil, nil)
+		} else {
+			sum, err = hashFile(path)
+		}
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(h, "%s\x00%s\x00", filepath.Clean(path), sum)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// hashFile hashes the content of one file
+func hashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// HashFiles hashes the relative path and content of every file under dir
+// whose name matches one of patterns, or every file when none are given
+func HashFiles(dir string, patterns []string) (string, error) {
+	return hashInputs(dir, patterns, nil)
+}
+
+// hashInputs hashes the relative path and content of every file under dir
+// matching patterns (all files when empty), skipping the command's outputs
+func hashInputs(dir string, patterns, outputs []string) (string, error) {
+	skip := make(map[string]bool)
+	for _, name := range outputs {
+		skip[name] = true
+	}
+
+	h := sha256.New()
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != dir && cacheSkipDirs[d.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() || !matchesAny(d.Name(), patterns) {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil || skip[rel] {
+			return err
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		fmt.Fprintf(h, "%s\x00", filepath.ToSlash(rel))
+		if _, err := io.Copy(h, f); err != nil {
+			return err
+		}
+		h.Write([]byte{0})
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// matchesAny reports whether name matches one of patterns; no patterns
+// matches everything
+func matchesAny(name string, patterns []string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if matched, _ := filepath.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,147 @@
+package tools
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+// Toolchain describes how to build and test one ecosystem in a workspace.
+// A nil command means the ecosystem has no such step.
+type Toolchain struct {
+	Name     string   `json:"name"`
+	Marker   string   `json:"marker"`
+	Build    []string `json:"build,omitempty"`
+	Test     []string `json:"test,omitempty"`
+	Coverage []string `json:"coverage,omitempty"`
+}
+
+// GoCoverProfile is where the Go coverage command writes its profile,
+// relative to the workspace. It lives with sentinel's own state rather
+// than in the user's tree.
+const GoCoverProfile = ".sentinel/coverage.out"
+
+// makeTarget matches a rule line in a Makefile
+var makeTarget = regexp.MustCompile(`^([A-Za-z0-9_.-]+)\s*:([^=]|$)`)
+
+// DetectToolchains inspects the marker files in root and returns the
+// toolchains for every ecosystem found. A Makefile is only used when no
+// language toolchain is detected, since it usually wraps one of them.
+func DetectToolchains(root string) []Toolchain {
+	var toolchains []Toolchain
+
+	if fileExists(filepath.Join(root, "go.mod")) {
+		toolchains = append(toolchains, Toolchain{
+			Name:     "go",
+			Marker:   "go.mod",
+			Build:    []string{"go", "build", "./..."},
+			Test:     []string{"go", "test", "-cover", "./..."},
+			Coverage: []string{"go", "test", "-coverprofile=" + GoCoverProfile, "./..."},
+		})
+	}
+
+	if fileExists(filepath.Join(root, "Cargo.toml")) {
+		toolchains = append(toolchains, Toolchain{
+			Name:     "cargo",
+			Marker:   "Cargo.toml",
+			Build:    []string{"cargo", "build"},
+			Test:     []string{"cargo", "test"},
+			Coverage: []string{"cargo", "llvm-cov"},
+		})
+	}
+
+	if fileExists(filepath.Join(root, "package.json")) {
+		toolchains = append(toolchains, nodeToolchain(root))
+	}
+
+	if fileExists(filepath.Join(root, "pyproject.toml")) {
+		toolchains = append(toolchains, Toolchain{
+			Name:     "python",
+			Marker:   "pyproject.toml",
+			Test:     []string{"pytest"},
+			Coverage: []string{"pytest", "--cov"},
+		})
+	}
+
+	if len(toolchains) == 0 && fileExists(filepath.Join(root, "Makefile")) {
+		toolchains = append(toolchains, makeToolchain(root))
+	}
+
+	return toolchains
+}
+
+// nodeToolchain picks the package manager from the lockfile and only
+// uses scripts that package.json actually declares
+func nodeToolchain(root string) Toolchain {
+	manager := "npm"
+	switch {
+	case fileExists(filepath.Join(root, "pnpm-lock.yaml")):
+		manager = "pnpm"
+	case fileExists(filepath.Join(root, "yarn.lock")):
+		manager = "yarn"
+	}
+
+	var pkg struct {
+		Scripts map[string]string `json:"scripts"`
+	}
+	if data, err := os.ReadFile(filepath.Join(root, "package.json")); err == nil {
+		_ = json.Unmarshal(data, &pkg)
+	}
+
+	tc := Toolchain{Name: manager, Marker: "package.json"}
+	if _, ok := pkg.Scripts["build"]; ok {
+		if manager == "npm" {
+			tc.Build = []string{"npm", "run", "build"}
+		} else {
+			tc.Build = []string{manager, "build"}
+		}
+	}
+	if _, ok := pkg.Scripts["test"]; ok {
+		tc.Test = []string{manager, "test"}
+	}
+	if _, ok := pkg.Scripts["coverage"]; ok {
+		if manager == "npm" {
+			tc.Coverage = []string{"npm", "run", "coverage"}
+		} else {
+			tc.Coverage = []string{manager, "coverage"}
+		}
+	}
+
+	return tc
+}
+
+// makeToolchain maps the build, test and coverage targets of a Makefile
+func makeToolchain(root string) Toolchain {
+	tc := Toolchain{Name: "make", Marker: "Makefile"}
+
+	targets := make(map[string]bool)
+	if f, err := os.Open(filepath.Join(root, "Makefile")); err == nil {
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			if m := makeTarget.FindStringSubmatch(scanner.Text()); m != nil {
+				targets[m[1]] = true
+			}
+		}
+		f.Close()
+	}
+
+	if targets["build"] {
+		tc.Build = []string{"make", "build"}
+	}
+	if targets["test"] {
+		tc.Test = []string{"make", "test"}
+	}
+	if targets["coverage"] {
+		tc.Coverage = []string{"make", "coverage"}
+	}
+
+	return tc
+}
+
+// fileExists reports whether path exists and is a regular file
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular()
+}
@@ -0,0 +1,80 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDetectToolchains(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "go.mod", "module example.com/x\n")
+	writeFile(t, root, "Cargo.toml", "[package]\nname = \"x\"\n")
+	writeFile(t, root, "package.json", `{"scripts":{"test":"jest"}}`)
+	writeFile(t, root, "yarn.lock", "")
+	writeFile(t, root, "Makefile", "build:\n\tgo build\n")
+
+	toolchains := DetectToolchains(root)
+
+	names := make(map[string]Toolchain)
+	for _, tc := range toolchains {
+		names[tc.Name] = tc
+	}
+
+	if len(toolchains) != 3 {
+		t.Fatalf("Expected go, cargo and yarn toolchains, got %v", toolchains)
+	}
+	if _, ok := names["go"]; !ok {
+		t.Error("Expected go toolchain")
+	}
+	if _, ok := names["cargo"]; !ok {
+		t.Error("Expected cargo toolchain")
+	}
+
+	yarn, ok := names["yarn"]
+	if !ok {
+		t.Fatal("Expected yarn toolchain from yarn.lock")
+	}
+	if yarn.Build != nil {
+		t.Errorf("Expected no build step without a build script, got %v", yarn.Build)
+	}
+	if len(yarn.Test) != 2 || yarn.Test[0] != "yarn" {
+		t.Errorf("Expected yarn test, got %v", yarn.Test)
+	}
+
+	// Makefile is ignored when a language toolchain exists
+	if _, ok := names["make"]; ok {
+		t.Error("Makefile should not be used alongside language toolchains")
+	}
+}
+
+func TestDetectMakefileOnly(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "Makefile", "CC := gcc\nbuild: deps\n\t$(CC) main.c\ntest:\n\t./run-tests\n")
+
+	toolchains := DetectToolchains(root)
+	if len(toolchains) != 1 || toolchains[0].Name != "make" {
+		t.Fatalf("Expected make toolchain, got %v", toolchains)
+	}
+	if toolchains[0].Build == nil || toolchains[0].Test == nil {
+		t.Errorf("Expected build and test targets, got %+v", toolchains[0])
+	}
+	if toolchains[0].Coverage != nil {
+		t.Errorf("Expected no coverage target, got %v", toolchains[0].Coverage)
+	}
+}
+
+func TestBuildNoToolchain(t *testing.T) {
+	runner := NewRunner(t.TempDir(), [][]string{{"go", "build", "./..."}}, 0)
+	if result := runner.Build(context.Background(), ""); result.Error == nil {
+		t.Error("Expected error when no build system is detected")
+	}
+}
@@ -1,16 +1,25 @@
 package tools
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
+	"strings"
 	"time"
+
+	"github.com/Siddhant-K-code/sentinel-ai/internal/cache"
 )
 
-// Runner executes allowlisted commands
+// Runner executes allowlisted commands inside a workspace
 type Runner struct {
-	Allow   [][]string
-	Timeout time.Duration
+	Workspace string
+	Allow     [][]string
+	Timeout   time.Duration
+	Cache     *cache.Cache
 }
 
 // RunResult represents the result of running a command
@@ -20,18 +29,34 @@ type RunResult struct {
 	ExitCode int
 	Duration time.Duration
 	Error    error
+	// Exited reports that the process ran and exited on its own, so a
+	// non-zero ExitCode is the command's answer rather than a failure to
+	// run it. It survives cache hits, whose Error is plain text.
+	Exited bool
+	Cached bool
 }
 
-// NewRunner creates a new command runner
-func NewRunner(allowlist [][]string, timeout time.Duration) *Runner {
+// NewRunner creates a new command runner bound to a workspace root.
+// An empty workspace means the current directory.
+func NewRunner(workspace string, allowlist [][]string, timeout time.Duration) *Runner {
+	if workspace == "" {
+		workspace = "."
+	}
 	return &Runner{
-		Allow:   allowlist,
-		Timeout: timeout,
+		Workspace: workspace,
+		Allow:     allowlist,
+		Timeout:   timeout,
 	}
 }
 
-// Run executes a command if it's in the allowlist
+// Run executes a command in the workspace root if it's in the allowlist
 func (r *Runner) Run(ctx context.Context, cmd string, args ...string) *RunResult {
+	return r.RunIn(ctx, "", cmd, args...)
+}
+
+// RunIn executes a command in dir if it's in the allowlist. A relative dir
+// is resolved against the workspace root and must stay inside it.
+func (r *Runner) RunIn(ctx context.Context, dir string, cmd string, args ...string) *RunResult {
 	start := time.Now()
 
 	// Check if command is allowed
@@ -42,35 +67,120 @@ func (r *Runner) Run(ctx context.Context, cmd string, args ...string) *RunResult
 		}
 	}
 
+	// Resolve the working directory
+	workDir, err := r.WorkDir(dir)
+	if err != nil {
+		return &RunResult{
+			Error:    err,
+			Duration: time.Since(start),
+		}
+	}
+
+	// Reuse a cached result when the inputs are unchanged
+	var cacheKey string
+	if r.Cache != nil {
+		root, _ := r.WorkDir("")
+		if key, err := r.cacheKey(root, workDir, cmd, args); err == nil {
+			if cached, ok := r.loadCached(key, workDir); ok {
+				return cached
+			}
+			cacheKey = key
+		}
+	}
+
+	// Output files may go to directories that do not exist yet
+	for _, name := range outputFiles(args) {
+		if err := os.MkdirAll(filepath.Join(workDir, filepath.Dir(name)), 0755); err != nil {
+			return &RunResult{
+				Error:    err,
+				Duration: time.Since(start),
+			}
+		}
+	}
+
 	// Create context with timeout
 	runCtx, cancel := context.WithTimeout(ctx, r.Timeout)
 	defer cancel()
 
 	// Execute command
 	execCmd := exec.CommandContext(runCtx, cmd, args...)
-	output, err := execCmd.CombinedOutput()
+	execCmd.Dir = workDir
+	var stdout, stderr bytes.Buffer
+	execCmd.Stdout = &stdout
+	execCmd.Stderr = &stderr
+	err = execCmd.Run()
 
 	result := &RunResult{
-		Stdout:   output,
-		Stderr:   nil, // CombinedOutput puts everything in stdout
+		Stdout:   stdout.Bytes(),
+		Stderr:   stderr.Bytes(),
 		Duration: time.Since(start),
 		Error:    err,
 	}
 
 	if execCmd.ProcessState != nil {
 		result.ExitCode = execCmd.ProcessState.ExitCode()
+		result.Exited = execCmd.ProcessState.Exited()
+	}
+
+	if cacheKey != "" && cacheable(runCtx, result) {
+		_ = r.storeCached(cacheKey, workDir, args, result)
 	}
 
 	return result
 }
 
-// allowed checks if a command and its arguments are in the allowlist
+// WorkDir resolves dir against the workspace root and verifies that the
+// result, after following symlinks, does not escape the workspace.
+func (r *Runner) WorkDir(dir string) (string, error) {
+	root, err := resolvePath(r.Workspace)
+	if err != nil {
+		return "", fmt.Errorf("resolve workspace: %w", err)
+	}
+
+	if dir == "" {
+		return root, nil
+	}
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(root, dir)
+	}
+
+	resolved, err := resolvePath(dir)
+	if err != nil {
+		return "", fmt.Errorf("resolve working directory: %w", err)
+	}
+
+	rel, err := filepath.Rel(root, resolved)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("working directory %s is outside workspace %s", dir, root)
+	}
+
+	return resolved, nil
+}
+
+// resolvePath returns the absolute, symlink-free form of path
+func resolvePath(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	return filepath.EvalSymlinks(abs)
+}
+
+// Allowed reports whether a command line is in the allowlist, for callers
+// such as package loaders that start the command themselves
+func (r *Runner) Allowed(cmd string, args ...string) bool {
+	return r.allowed(cmd, args)
+}
+
+// allowed checks if a command and its arguments are in the allowlist.
+// An allowlist element of "*" matches any single argument that is not a
+// flag, so a wildcard for a name or path never admits an option.
 func (r *Runner) allowed(cmd string, args []string) bool {
 	for _, allowed := range r.Allow {
 		if len(allowed) == len(args)+1 && allowed[0] == cmd {
 			matches := true
 			for i := 1; i < len(allowed); i++ {
-				if allowed[i] != args[i-1] {
+				if !argMatches(allowed[i], args[i-1]) {
 					matches = false
 					break
 				}
@@ -83,34 +193,127 @@ func (r *Runner) allowed(cmd string, args []string) bool {
 	return false
 }
 
-// Build runs build commands
+// argMatches reports whether arg matches one allowlist element
+func argMatches(pattern, arg string) bool {
+	if pattern == "*" {
+		return !strings.HasPrefix(arg, "-")
+	}
+	return pattern == arg
+}
+
+// Toolchains returns the toolchains detected in the workspace root
+func (r *Runner) Toolchains() []Toolchain {
+	root, err := r.WorkDir("")
+	if err != nil {
+		return nil
+	}
+	return DetectToolchains(root)
+}
+
+// Build runs the build command of every detected toolchain. A non-empty
+// target replaces the Go package pattern.
 func (r *Runner) Build(ctx context.Context, target string) *RunResult {
-	if target == "" {
-		// Try to detect build system
-		if _, err := exec.LookPath("go"); err == nil {
-			return r.Run(ctx, "go", "build", "./...")
+	var cmds [][]string
+	for _, tc := range r.Toolchains() {
+		if tc.Build == nil {
+			continue
 		}
-		if _, err := exec.LookPath("cargo"); err == nil {
-			return r.Run(ctx, "cargo", "build")
+		argv := tc.Build
+		if target != "" && tc.Name == "go" {
+			argv = []string{"go", "build", target}
 		}
+		cmds = append(cmds, argv)
+	}
+	if len(cmds) == 0 {
 		return &RunResult{
 			Error: errors.New("no supported build system found"),
 		}
 	}
-	return r.Run(ctx, "go", "build", target)
+	return r.runAll(ctx, cmds)
 }
 
-// Test runs test commands
+// Test runs the test command of every detected toolchain. A non-empty
+// pattern filters Go and Cargo tests.
 func (r *Runner) Test(ctx context.Context, pattern string) *RunResult {
-	args := []string{"test"}
-	if pattern != "" {
-		args = append(args, "-run", pattern)
+	cmds := r.testCommands(pattern)
+	if len(cmds) == 0 {
+		return &RunResult{
+			Error: errors.New("no supported test system found"),
+		}
+	}
+	return r.runAll(ctx, cmds)
+}
+
+// testCommands returns the test command of every detected toolchain
+func (r *Runner) testCommands(pattern string) [][]string {
+	var cmds [][]string
+	for _, tc := range r.Toolchains() {
+		if tc.Test == nil {
+			continue
+		}
+		argv := tc.Test
+		if pattern != "" {
+			switch tc.Name {
+			case "go":
+				argv = []string{"go", "test", "-run", pattern, "-cover", "./..."}
+			case "cargo":
+				argv = []string{"cargo", "test", pattern}
+			}
+		}
+		cmds = append(cmds, argv)
 	}
-	args = append(args, "-cover", "./...")
-	return r.Run(ctx, "go", args...)
+	return cmds
 }
 
-// Coverage runs coverage analysis
+// Coverage runs coverage analysis for every detected toolchain
 func (r *Runner) Coverage(ctx context.Context) *RunResult {
-	return r.Run(ctx, "go", "test", "-coverprofile=coverage.out", "./...")
+	cmds := r.coverageCommands()
+	if len(cmds) == 0 {
+		return &RunResult{
+			Error: errors.New("no supported coverage system found"),
+		}
+	}
+	return r.runAll(ctx, cmds)
+}
+
+// coverageCommands returns the coverage command of every detected
+// toolchain
+func (r *Runner) coverageCommands() [][]string {
+	var cmds [][]string
+	for _, tc := range r.Toolchains() {
+		if tc.Coverage != nil {
+			cmds = append(cmds, tc.Coverage)
+		}
+	}
+	return cmds
+}
+
+// runAll runs each command in turn and merges the results. Output is
+// prefixed per command, the first non-zero exit code wins and errors are
+// joined so a failing toolchain does not hide the others.
+func (r *Runner) runAll(ctx context.Context, cmds [][]string) *RunResult {
+	merged := &RunResult{}
+	var errs []string
+
+	for _, argv := range cmds {
+		result := r.Run(ctx, argv[0], argv[1:]...)
+
+		merged.Stdout = append(merged.Stdout, fmt.Sprintf("==> %s\n", strings.Join(argv, " "))...)
+		merged.Stdout = append(merged.Stdout, result.Stdout...)
+		merged.Stderr = append(merged.Stderr, result.Stderr...)
+		merged.Duration += result.Duration
+
+		if merged.ExitCode == 0 {
+			merged.ExitCode = result.ExitCode
+		}
+		if result.Error != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", strings.Join(argv, " "), result.Error))
+		}
+	}
+
+	if len(errs) > 0 {
+		merged.Error = errors.New(strings.Join(errs, "; "))
+	}
+
+	return merged
 }
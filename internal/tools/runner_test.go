@@ -2,8 +2,14 @@ package tools
 
 import (
 	"context"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/Siddhant-K-code/sentinel-ai/internal/cache"
+	"github.com/Siddhant-K-code/sentinel-ai/internal/policy"
 )
 
 func TestRunner(t *testing.T) {
@@ -13,7 +19,7 @@ func TestRunner(t *testing.T) {
 		{"echo", "hello"},
 	}
 
-	runner := NewRunner(allowlist, 5*time.Second)
+	runner := NewRunner(".", allowlist, 5*time.Second)
 
 	// Test allowed command
 	ctx := context.Background()
@@ -41,7 +47,7 @@ func TestAllowed(t *testing.T) {
 		{"go", "test", "-cover"},
 	}
 
-	runner := NewRunner(allowlist, 5*time.Second)
+	runner := NewRunner(".", allowlist, 5*time.Second)
 
 	// Test exact match
 	if !runner.allowed("go", []string{"build"}) {
@@ -62,4 +68,206 @@ func TestAllowed(t *testing.T) {
 	if runner.allowed("go", []string{"build", "--unsafe"}) {
 		t.Error("go build --unsafe should not be allowed")
 	}
+
+	// Test wildcards, which never match flags
+	runner = NewRunner(".", [][]string{{"cat", "*"}}, 5*time.Second)
+	if !runner.allowed("cat", []string{"report.sarif"}) {
+		t.Error("cat report.sarif should be allowed")
+	}
+	if runner.allowed("cat", []string{"--version"}) || runner.allowed("cat", []string{"-"}) {
+		t.Error("A wildcard should not match a flag")
+	}
+}
+
+func TestDefaultPolicyAllowsToolchainCommands(t *testing.T) {
+	pkg := `{"scripts": {"build": "tsc", "test": "jest", "coverage": "jest --coverage"}}`
+	workspaces := []map[string]string{
+		{"go.mod": "module x\n", "Cargo.toml": "", "pyproject.toml": "", "package.json": pkg},
+		{"package.json": pkg, "yarn.lock": ""},
+		{"package.json": pkg, "pnpm-lock.yaml": ""},
+		{"Makefile": "build:\n\tcc\ntest:\n\t./test\ncoverage:\n\t./cover\n"},
+	}
+	allowlist := policy.DefaultPolicy().Allowlist.Commands
+	for _, files := range workspaces {
+		root := t.TempDir()
+		for name, content := range files {
+			if err := os.WriteFile(filepath.Join(root, name), []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		runner := NewRunner(root, allowlist, time.Second)
+
+		cmds := append(runner.testCommands(""), runner.testCommands("TestLogin")...)
+		cmds = append(cmds, runner.coverageCommands()...)
+		if len(cmds) == 0 {
+			t.Fatalf("Expected commands for %v", files)
+		}
+		for _, argv := range cmds {
+			if !runner.allowed(argv[0], argv[1:]) {
+				t.Errorf("Expected the default allowlist to admit %q", argv)
+			}
+		}
+	}
+
+	// The wildcard for the test name does not admit a flag
+	runner := NewRunner(t.TempDir(), allowlist, time.Second)
+	if runner.allowed("go", []string{"test", "-run", "-exec=sh", "-cover", "./..."}) {
+		t.Error("Expected a test pattern starting with '-' to be rejected")
+	}
+}
+
+func TestWorkDir(t *testing.T) {
+	root := t.TempDir()
+	if err := os.Mkdir(filepath.Join(root, "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	runner := NewRunner(root, [][]string{{"pwd"}}, 5*time.Second)
+
+	// Test relative directory inside the workspace
+	dir, err := runner.WorkDir("sub")
+	if err != nil {
+		t.Fatalf("sub should be inside the workspace: %v", err)
+	}
+	if filepath.Base(dir) != "sub" {
+		t.Errorf("Expected sub, got %s", dir)
+	}
+
+	// Test escaping the workspace
+	if _, err := runner.WorkDir(".."); err == nil {
+		t.Error("Expected .. to be rejected")
+	}
+	if _, err := runner.WorkDir(os.TempDir()); err == nil {
+		t.Error("Expected directory outside workspace to be rejected")
+	}
+
+	// Test symlink escaping the workspace
+	if err := os.Symlink(os.TempDir(), filepath.Join(root, "link")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runner.WorkDir("link"); err == nil {
+		t.Error("Expected symlink outside workspace to be rejected")
+	}
+
+	// Test commands run in the workspace root
+	result := runner.Run(context.Background(), "pwd")
+	if result.Error != nil {
+		t.Fatalf("Expected pwd to succeed: %v", result.Error)
+	}
+	want, _ := filepath.EvalSymlinks(root)
+	if got := strings.TrimSpace(string(result.Stdout)); got != want {
+		t.Errorf("Expected pwd %s, got %s", want, got)
+	}
+
+	result = runner.RunIn(context.Background(), "..", "pwd")
+	if result.Error == nil {
+		t.Error("Expected RunIn outside workspace to fail")
+	}
+}
+
+func TestRunCache(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "input.txt", "one")
+
+	c, err := cache.Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	runner := NewRunner(root, [][]string{{"cat", "input.txt"}}, 5*time.Second)
+	runner.EnableCache(c)
+	ctx := context.Background()
+
+	first := runner.Run(ctx, "cat", "input.txt")
+	if first.Error != nil || first.Cached {
+		t.Fatalf("Expected uncached success, got %+v", first)
+	}
+
+	second := runner.Run(ctx, "cat", "input.txt")
+	if !second.Cached || string(second.Stdout) != "one" {
+		t.Errorf("Expected cached result, got %+v", second)
+	}
+
+	// Changing an input invalidates the entry
+	writeFile(t, root, "input.txt", "two")
+	third := runner.Run(ctx, "cat", "input.txt")
+	if third.Cached || string(third.Stdout) != "two" {
+		t.Errorf("Expected fresh result after input change, got %+v", third)
+	}
+}
+
+func TestCacheKeyGoInputs(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "go.mod", "module x\n")
+	writeFile(t, root, "x_test.go", "package x\n")
+	if err := os.Mkdir(filepath.Join(root, "testdata"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, root, "testdata/golden.txt", "one")
+
+	runner := NewRunner(root, nil, time.Second)
+	args := []string{"test", "-coverprofile=coverage.out", "./..."}
+	key := func() string {
+		t.Helper()
+		k, err := runner.cacheKey(root, root, "go", args)
+		if err != nil {
+			t.Skip(err)
+		}
+		return k
+	}
+
+	first := key()
+	// The profile the command writes is not an input
+	writeFile(t, root, "coverage.out", "mode: set\n")
+	if key() != first {
+		t.Error("Expected the coverage profile not to change the key")
+	}
+
+	// Test data changes what go test does
+	writeFile(t, root, "testdata/golden.txt", "two")
+	second := key()
+	if second == first {
+		t.Error("Expected a testdata change to change the key")
+	}
+
+	// Build output is not an input either
+	if err := os.MkdirAll(filepath.Join(root, "node_modules", "dep"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, root, "node_modules/dep/index.js", "module.exports = 1\n")
+	if key() != second {
+		t.Error("Expected node_modules not to change the key")
+	}
+
+	// So do build tags and the target platform
+	t.Setenv("GOFLAGS", "-tags=integration")
+	if key() == second {
+		t.Error("Expected GOFLAGS to change the key")
+	}
+}
+
+func TestCacheKeyConfigOutsideWorkspace(t *testing.T) {
+	root := t.TempDir()
+	rules := filepath.Join(t.TempDir(), "rules.yml")
+	if err := os.WriteFile(rules, []byte("rules: []\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	runner := NewRunner(root, nil, time.Second)
+	key := func() string {
+		t.Helper()
+		k, err := runner.cacheKey(root, root, "sh", []string{"--config", rules, "--rules=" + rules, "."})
+		if err != nil {
+			t.Skip(err)
+		}
+		return k
+	}
+
+	first := key()
+	if err := os.WriteFile(rules, []byte("rules: [x]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if key() == first {
+		t.Error("Expected a changed config outside the workspace to change the key")
+	}
 }
@@ -0,0 +1,192 @@
+package vulndb
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxEntryBytes bounds the size of a single advisory read from a source
+const maxEntryBytes = 10 << 20
+
+// ImportStats summarizes an import
+type ImportStats struct {
+	Entries int
+	Skipped int
+}
+
+// Import replaces the database in dir with the OSV advisories found in
+// src, a directory tree or a zip archive (such as the per-ecosystem
+// all.zip exports of osv.dev) of JSON files. JSON files that are not
+// advisories are skipped. The new database is built next to dir and
+// swapped in only once complete.
+func Import(src, dir string) (ImportStats, error) {
+	var stats ImportStats
+	if dir == "" {
+		var err error
+		if dir, err = DefaultDir(); err != nil {
+			return stats, err
+		}
+	}
+
+	parent := filepath.Dir(dir)
+	if err := os.MkdirAll(parent, 0755); err != nil {
+		return stats, err
+	}
+	tmp, err := os.MkdirTemp(parent, ".vulndb-*")
+	if err != nil {
+		return stats, err
+	}
+	defer os.RemoveAll(tmp)
+
+	if err := os.Mkdir(filepath.Join(tmp, entriesDir), 0755); err != nil {
+		return stats, err
+	}
+
+	index := Index{
+		Source:   src,
+		Imported: time.Now().UTC(),
+		Packages: make(map[string]map[string][]string),
+	}
+
+	add := func(name string, r io.Reader) error {
+		data, err := io.ReadAll(io.LimitReader(r, maxEntryBytes+1))
+		if err != nil {
+			return fmt.Errorf("read %s: %w", name, err)
+		}
+		var entry Entry
+		if len(data) > maxEntryBytes || json.Unmarshal(data, &entry) != nil || entry.ID == "" || len(entry.Affected) == 0 {
+			stats.Skipped++
+			return nil
+		}
+
+		if err := os.WriteFile(filepath.Join(tmp, entriesDir, entryFile(entry.ID)), data, 0644); err != nil {
+			return err
+		}
+		stats.Entries++
+
+		for _, aff := range entry.Affected {
+			eco, pkg := aff.Package.Ecosystem, aff.Package.Name
+			if eco == "" || pkg == "" {
+				continue
+			}
+			if index.Packages[eco] == nil {
+				index.Packages[eco] = make(map[string][]string)
+			}
+			ids := index.Packages[eco][pkg]
+			if len(ids) == 0 || ids[len(ids)-1] != entry.ID {
+				index.Packages[eco][pkg] = append(ids, entry.ID)
+			}
+		}
+		return nil
+	}
+
+	info, err := os.Stat(src)
+	if err != nil {
+		return stats, err
+	}
+	if info.IsDir() {
+		err = importDir(src, add)
+	} else {
+		err = importZip(src, add)
+	}
+	if err != nil {
+		return stats, err
+	}
+	if stats.Entries == 0 {
+		return stats, fmt.Errorf("no OSV advisories found in %s", src)
+	}
+
+	for _, pkgs := range index.Packages {
+		for name, ids := range pkgs {
+			sort.Strings(ids)
+			pkgs[name] = dedupe(ids)
+		}
+	}
+	index.Entries = stats.Entries
+
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return stats, err
+	}
+	if err := os.WriteFile(filepath.Join(tmp, indexFile), data, 0644); err != nil {
+		return stats, err
+	}
+
+	// Swap the new database in, keeping the old one until the rename
+	// succeeds
+	old := dir + ".old"
+	_ = os.RemoveAll(old)
+	if _, err := os.Stat(dir); err == nil {
+		if err := os.Rename(dir, old); err != nil {
+			return stats, err
+		}
+	}
+	if err := os.Rename(tmp, dir); err != nil {
+		_ = os.Rename(old, dir)
+		return stats, err
+	}
+	_ = os.RemoveAll(old)
+	return stats, nil
+}
+
+// importDir feeds every JSON file below root to add
+func importDir(root string, add func(string, io.Reader) error) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(d.Name(), ".json") {
+			return nil
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		return add(path, f)
+	})
+}
+
+// importZip feeds every JSON file in a zip archive to add
+func importZip(path string, add func(string, io.Reader) error) error {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return fmt.Errorf("open %s: %w", path, err)
+	}
+	defer zr.Close()
+
+	for _, file := range zr.File {
+		if file.FileInfo().IsDir() || !strings.HasSuffix(file.Name, ".json") {
+			continue
+		}
+		r, err := file.Open()
+		if err != nil {
+			return fmt.Errorf("open %s: %w", file.Name, err)
+		}
+		err = add(file.Name, r)
+		r.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dedupe removes adjacent duplicates from a sorted slice
+func dedupe(ids []string) []string {
+	out := ids[:0]
+	for i, id := range ids {
+		if i == 0 || id != ids[i-1] {
+			out = append(out, id)
+		}
+	}
+	return out
+}
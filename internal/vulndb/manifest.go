@@ -0,0 +1,160 @@
+package vulndb
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// EcosystemGo is the OSV ecosystem of Go modules
+const EcosystemGo = "Go"
+
+// Dependency is a package version pinned by a manifest or lock file
+type Dependency struct {
+	Ecosystem string
+	Name      string
+	Version   string
+	// File and Line locate the requirement, relative to the workspace
+	File string
+	Line int
+	// Inferred is set when the version is a guess from a lock file, such
+	// as the highest version in go.sum, and may not be the one built
+	Inferred bool
+}
+
+// manifestParsers read the dependencies of one ecosystem; each returns no
+// dependencies when its files are absent
+var manifestParsers = []func(root string) ([]Dependency, error){
+	goModules,
+}
+
+// Dependencies returns the dependencies pinned by the manifests in root
+func Dependencies(root string) ([]Dependency, error) {
+	var deps []Dependency
+	for _, parse := range manifestParsers {
+		found, err := parse(root)
+		if err != nil {
+			return deps, err
+		}
+		deps = append(deps, found...)
+	}
+	return deps, nil
+}
+
+// goModules reads go.mod, applying replace directives. Since Go 1.17
+// go.mod lists every module that provides a package to the build, so
+// go.sum is only consulted for older modules: modules it alone lists are
+// added, inferred, at the highest version it has a content hash for.
+func goModules(root string) ([]Dependency, error) {
+	data, err := os.ReadFile(filepath.Join(root, "go.mod"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	mf, err := modfile.Parse("go.mod", data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("parse go.mod: %w", err)
+	}
+
+	replaced := make(map[module.Version]module.Version)
+	for _, r := range mf.Replace {
+		replaced[r.Old] = r.New
+	}
+	replacement := func(m module.Version) (module.Version, bool) {
+		if r, ok := replaced[m]; ok {
+			return r, true
+		}
+		r, ok := replaced[module.Version{Path: m.Path}]
+		return r, ok
+	}
+
+	var deps []Dependency
+	seen := make(map[string]bool)
+	for _, req := range mf.Require {
+		seen[req.Mod.Path] = true
+		mod := req.Mod
+		if r, ok := replacement(mod); ok {
+			// A filesystem replacement has no version to check
+			if r.Version == "" {
+				continue
+			}
+			mod = r
+		}
+		dep := Dependency{Ecosystem: EcosystemGo, Name: mod.Path, Version: mod.Version, File: "go.mod"}
+		if req.Syntax != nil {
+			dep.Line = req.Syntax.Start.Line
+		}
+		deps = append(deps, dep)
+	}
+
+	if mf.Go != nil && semver.Compare("v"+mf.Go.Version, "v1.17") >= 0 {
+		return deps, nil
+	}
+	sum, err := goSumVersions(filepath.Join(root, "go.sum"))
+	if err != nil {
+		return deps, err
+	}
+	paths := make([]string, 0, len(sum))
+	for path := range sum {
+		if !seen[path] {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		entry := sum[path]
+		mod := module.Version{Path: path, Version: entry.version}
+		if r, ok := replacement(mod); ok {
+			if r.Version == "" {
+				continue
+			}
+			mod = r
+		}
+		deps = append(deps, Dependency{Ecosystem: EcosystemGo, Name: mod.Path, Version: mod.Version, File: "go.sum", Line: entry.line, Inferred: true})
+	}
+	return deps, nil
+}
+
+// goSumEntry is a module version hashed in go.sum and the line hashing it
+type goSumEntry struct {
+	version string
+	line    int
+}
+
+// goSumVersions returns the highest version of each module whose content
+// (not just its go.mod) is hashed in go.sum
+func goSumVersions(path string) (map[string]goSumEntry, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make(map[string]goSumEntry)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 || strings.HasSuffix(fields[1], "/go.mod") {
+			continue
+		}
+		mod, version := fields[0], fields[1]
+		if cur, ok := versions[mod]; !ok || compareVersions(version, cur.version) > 0 {
+			versions[mod] = goSumEntry{version: version, line: line}
+		}
+	}
+	return versions, scanner.Err()
+}
@@ -0,0 +1,352 @@
+package vulndb
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/mod/semver"
+
+	"github.com/Siddhant-K-code/sentinel-ai/internal/cache"
+)
+
+// indexFile lists the advisories per ecosystem and package
+const indexFile = "index.json"
+
+// entriesDir holds one OSV JSON file per advisory
+const entriesDir = "entries"
+
+// Entry is an OSV advisory (https://ossf.github.io/osv-schema/)
+type Entry struct {
+	ID               string           `json:"id"`
+	Aliases          []string         `json:"aliases,omitempty"`
+	Summary          string           `json:"summary,omitempty"`
+	Details          string           `json:"details,omitempty"`
+	Modified         time.Time        `json:"modified"`
+	Published        time.Time        `json:"published,omitempty"`
+	Withdrawn        *time.Time       `json:"withdrawn,omitempty"`
+	Affected         []Affected       `json:"affected"`
+	Severity         []Severity       `json:"severity,omitempty"`
+	References       []Reference      `json:"references,omitempty"`
+	DatabaseSpecific DatabaseSpecific `json:"database_specific,omitempty"`
+}
+
+// Affected is a package and the versions of it an advisory applies to
+type Affected struct {
+	Package  Package    `json:"package"`
+	Ranges   []Range    `json:"ranges,omitempty"`
+	Versions []string   `json:"versions,omitempty"`
+	Severity []Severity `json:"severity,omitempty"`
+	// EcosystemSpecific lists the affected Go packages and symbols
+	EcosystemSpecific EcosystemSpecific `json:"ecosystem_specific,omitempty"`
+}
+
+// EcosystemSpecific holds the fields the Go vulnerability database adds
+type EcosystemSpecific struct {
+	Imports []AffectedImport `json:"imports,omitempty"`
+}
+
+// AffectedImport is an affected package and, if known, its vulnerable symbols
+// ("Func" or "Type.Method"); no symbols means the whole package
+type AffectedImport struct {
+	Path    string   `json:"path"`
+	Symbols []string `json:"symbols,omitempty"`
+}
+
+// Package names a package within an ecosystem such as "Go" or "npm"
+type Package struct {
+	Ecosystem string `json:"ecosystem"`
+	Name      string `json:"name"`
+}
+
+// Range is a sequence of version events
+type Range struct {
+	Type   string  `json:"type"` // SEMVER, ECOSYSTEM or GIT
+	Events []Event `json:"events"`
+}
+
+// Event introduces or ends a range of affected versions
+type Event struct {
+	Introduced   string `json:"introduced,omitempty"`
+	Fixed        string `json:"fixed,omitempty"`
+	LastAffected string `json:"last_affected,omitempty"`
+	Limit        string `json:"limit,omitempty"`
+}
+
+// Severity is a scored severity, e.g. a CVSS vector
+type Severity struct {
+	Type  string `json:"type"`
+	Score string `json:"score"`
+}
+
+// Reference links to more information about an advisory
+type Reference struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+// DatabaseSpecific holds fields GitHub advisories add to OSV entries
+type DatabaseSpecific struct {
+	Severity string   `json:"severity,omitempty"`
+	CWEIDs   []string `json:"cwe_ids,omitempty"`
+}
+
+// Index is the lookup table stored next to the entries
+type Index struct {
+	Source   string                         `json:"source"`
+	Imported time.Time                      `json:"imported"`
+	Entries  int                            `json:"entries"`
+	Packages map[string]map[string][]string `json:"packages"` // ecosystem -> package -> IDs
+}
+
+// DB is a local OSV database created by Import
+type DB struct {
+	Dir   string
+	index Index
+}
+
+// Match is an advisory affecting a dependency version
+type Match struct {
+	Entry *Entry
+	// Fixed is the first fixed version above the matched one, if any
+	Fixed string
+	// Imports are the affected packages and symbols, when listed
+	Imports []AffectedImport
+}
+
+// DefaultDir returns the database directory under the user cache
+func DefaultDir() (string, error) {
+	base, err := cache.DefaultDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, "vulndb"), nil
+}
+
+// Open opens a database created by Import. An empty dir selects
+// DefaultDir.
+func Open(dir string) (*DB, error) {
+	if dir == "" {
+		var err error
+		if dir, err = DefaultDir(); err != nil {
+			return nil, err
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, indexFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("no vulnerability database in %s; run 'sentinel-ai vulndb import'", dir)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	db := &DB{Dir: dir}
+	if err := json.Unmarshal(data, &db.index); err != nil {
+		return nil, fmt.Errorf("read %s: %w", indexFile, err)
+	}
+	return db, nil
+}
+
+// Index returns the database metadata
+func (db *DB) Index() Index {
+	return db.index
+}
+
+// Lookup returns the advisories affecting version of a package
+func (db *DB) Lookup(ecosystem, name, version string) ([]Match, error) {
+	var matches []Match
+	for _, id := range db.index.Packages[ecosystem][name] {
+		entry, err := db.entry(id)
+		if err != nil {
+			return matches, err
+		}
+		if entry.Withdrawn != nil {
+			continue
+		}
+
+		for _, aff := range entry.Affected {
+			if aff.Package.Ecosystem != ecosystem || aff.Package.Name != name {
+				continue
+			}
+			if fixed, ok := aff.affects(version); ok {
+				matches = append(matches, Match{Entry: entry, Fixed: fixed, Imports: aff.EcosystemSpecific.Imports})
+				break
+			}
+		}
+	}
+	return matches, nil
+}
+
+// entry reads one advisory
+func (db *DB) entry(id string) (*Entry, error) {
+	data, err := os.ReadFile(filepath.Join(db.Dir, entriesDir, entryFile(id)))
+	if err != nil {
+		return nil, err
+	}
+	var e Entry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, fmt.Errorf("read %s: %w", id, err)
+	}
+	return &e, nil
+}
+
+// entryFile maps an advisory ID to a safe file name
+func entryFile(id string) string {
+	return strings.NewReplacer("/", "_", "\\", "_", "..", "_").Replace(id) + ".json"
+}
+
+// affects reports whether version is affected and the version fixing it
+func (a Affected) affects(version string) (string, bool) {
+	for _, v := range a.Versions {
+		if compareVersions(v, version) == 0 {
+			return a.fixedAfter(version), true
+		}
+	}
+
+	for _, r := range a.Ranges {
+		if r.Type != "SEMVER" && r.Type != "ECOSYSTEM" {
+			continue
+		}
+
+		events := append([]Event{}, r.Events...)
+		sort.SliceStable(events, func(i, j int) bool {
+			return compareVersions(events[i].version(), events[j].version()) < 0
+		})
+
+		affected := false
+		for _, ev := range events {
+			switch {
+			case ev.Introduced != "" && compareVersions(version, ev.Introduced) >= 0:
+				affected = true
+			case ev.Fixed != "" && compareVersions(version, ev.Fixed) >= 0:
+				affected = false
+			case ev.LastAffected != "" && compareVersions(version, ev.LastAffected) > 0:
+				affected = false
+			}
+		}
+		if affected {
+			return a.fixedAfter(version), true
+		}
+	}
+	return "", false
+}
+
+// fixedAfter returns the lowest fixed version above version
+func (a Affected) fixedAfter(version string) string {
+	var best string
+	for _, r := range a.Ranges {
+		for _, ev := range r.Events {
+			if ev.Fixed == "" || compareVersions(ev.Fixed, version) <= 0 {
+				continue
+			}
+			if best == "" || compareVersions(ev.Fixed, best) < 0 {
+				best = ev.Fixed
+			}
+		}
+	}
+	return best
+}
+
+// version returns the version an event refers to
+func (e Event) version() string {
+	switch {
+	case e.Introduced != "":
+		return e.Introduced
+	case e.Fixed != "":
+		return e.Fixed
+	case e.LastAffected != "":
+		return e.LastAffected
+	}
+	return e.Limit
+}
+
+// compareVersions orders semantic versions with or without a "v" prefix;
+// "0" sorts before every version, as OSV uses it for "since the start"
+func compareVersions(a, b string) int {
+	if a == b {
+		return 0
+	}
+	if a == "0" {
+		return -1
+	}
+	if b == "0" {
+		return 1
+	}
+	return semver.Compare(canonical(a), canonical(b))
+}
+
+// canonical adds the "v" prefix golang.org/x/mod/semver expects
+func canonical(v string) string {
+	if strings.HasPrefix(v, "v") {
+		return v
+	}
+	return "v" + v
+}
+
+// IDs returns the advisory ID followed by its CVE and GHSA aliases
+func (e *Entry) IDs() []string {
+	ids := []string{e.ID}
+	for _, alias := range e.Aliases {
+		if strings.HasPrefix(alias, "CVE-") || strings.HasPrefix(alias, "GHSA-") {
+			ids = append(ids, alias)
+		}
+	}
+	return ids
+}
+
+// SeverityLevel returns critical, high, medium or low from the GitHub
+// severity or the highest CVSS v3 score, or "" when the entry has neither
+func (e *Entry) SeverityLevel() string {
+	switch strings.ToUpper(e.DatabaseSpecific.Severity) {
+	case "CRITICAL":
+		return "critical"
+	case "HIGH":
+		return "high"
+	case "MODERATE", "MEDIUM":
+		return "medium"
+	case "LOW":
+		return "low"
+	}
+
+	return CVSSLevel(e.CVSSScore())
+}
+
+// CVSSScore returns the highest CVSS v3 base score of the entry and its
+// affected packages, or 0 when none is given
+func (e *Entry) CVSSScore() float64 {
+	best := 0.0
+	severities := append([]Severity{}, e.Severity...)
+	for _, aff := range e.Affected {
+		severities = append(severities, aff.Severity...)
+	}
+	for _, s := range severities {
+		if s.Type != "CVSS_V3" {
+			continue
+		}
+		if score, err := CVSS3Score(s.Score); err == nil && score > best {
+			best = score
+		}
+	}
+	return best
+}
+
+// CVSSLevel maps a CVSS score to its qualitative rating, or "" for none
+func CVSSLevel(score float64) string {
+	switch {
+	case score >= 9:
+		return "critical"
+	case score >= 7:
+		return "high"
+	case score >= 4:
+		return "medium"
+	case score > 0:
+		return "low"
+	}
+	return ""
+}
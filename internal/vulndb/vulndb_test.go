@@ -0,0 +1,253 @@
+package vulndb
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var advisories = map[string]string{
+	"GO-2023-0001.json": `{
+  "id": "GO-2023-0001",
+  "aliases": ["CVE-2023-1111", "GHSA-aaaa-bbbb-cccc"],
+  "summary": "Request smuggling in example.com/web",
+  "modified": "2023-05-01T00:00:00Z",
+  "affected": [{
+    "package": {"ecosystem": "Go", "name": "example.com/web"},
+    "ranges": [{"type": "SEMVER", "events": [
+      {"introduced": "0"}, {"fixed": "1.2.0"},
+      {"introduced": "1.4.0"}, {"fixed": "1.4.3"}
+    ]}]
+  }],
+  "database_specific": {"severity": "HIGH", "cwe_ids": ["CWE-444"]}
+}`,
+	"GO-2023-0002.json": `{
+  "id": "GO-2023-0002",
+  "modified": "2023-05-01T00:00:00Z",
+  "affected": [{
+    "package": {"ecosystem": "Go", "name": "example.com/web"},
+    "ranges": [{"type": "SEMVER", "events": [{"introduced": "1.0.0"}, {"last_affected": "1.1.0"}]}]
+  }],
+  "severity": [{"type": "CVSS_V3", "score": "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"}]
+}`,
+	"GO-2023-0003.json": `{
+  "id": "GO-2023-0003",
+  "modified": "2023-05-01T00:00:00Z",
+  "withdrawn": "2023-06-01T00:00:00Z",
+  "affected": [{
+    "package": {"ecosystem": "Go", "name": "example.com/web"},
+    "ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}]}]
+  }]
+}`,
+	"nested/PYSEC-2023-1.json": `{
+  "id": "PYSEC-2023-1",
+  "modified": "2023-05-01T00:00:00Z",
+  "affected": [{"package": {"ecosystem": "PyPI", "name": "web"}, "versions": ["1.0"]}]
+}`,
+	"index/db.json": `{"modified": "2023-05-01T00:00:00Z"}`,
+}
+
+func writeAdvisories(t *testing.T) string {
+	t.Helper()
+	src := t.TempDir()
+	for name, content := range advisories {
+		path := filepath.Join(src, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return src
+}
+
+func writeZip(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "all.zip")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(f)
+	for name, content := range advisories {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestImportAndLookup(t *testing.T) {
+	for name, src := range map[string]string{"dir": writeAdvisories(t), "zip": writeZip(t)} {
+		t.Run(name, func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), "vulndb")
+			stats, err := Import(src, dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stats.Entries != 4 || stats.Skipped != 1 {
+				t.Errorf("stats = %+v, want 4 entries and 1 skipped", stats)
+			}
+
+			// Importing again replaces the database
+			if _, err := Import(src, dir); err != nil {
+				t.Fatal(err)
+			}
+
+			db, err := Open(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			tests := []struct {
+				version string
+				want    map[string]string // ID -> fixed version
+			}{
+				{"v1.0.0", map[string]string{"GO-2023-0001": "1.2.0", "GO-2023-0002": ""}},
+				{"v1.1.0", map[string]string{"GO-2023-0001": "1.2.0", "GO-2023-0002": ""}},
+				{"v1.2.0", map[string]string{}},
+				{"v1.4.2", map[string]string{"GO-2023-0001": "1.4.3"}},
+				{"v1.4.3", map[string]string{}},
+			}
+			for _, tt := range tests {
+				matches, err := db.Lookup("Go", "example.com/web", tt.version)
+				if err != nil {
+					t.Fatal(err)
+				}
+				got := make(map[string]string)
+				for _, m := range matches {
+					got[m.Entry.ID] = m.Fixed
+				}
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("Lookup(%s) = %v, want %v", tt.version, got, tt.want)
+				}
+			}
+
+			matches, err := db.Lookup("PyPI", "web", "1.0")
+			if err != nil || len(matches) != 1 {
+				t.Errorf("PyPI lookup = %v, %v; want 1 match", matches, err)
+			}
+		})
+	}
+}
+
+func TestOpenMissing(t *testing.T) {
+	if _, err := Open(t.TempDir()); err == nil {
+		t.Error("Open of an empty directory succeeded")
+	}
+}
+
+func TestSeverityLevel(t *testing.T) {
+	tests := []struct {
+		entry Entry
+		want  string
+	}{
+		{Entry{DatabaseSpecific: DatabaseSpecific{Severity: "MODERATE"}}, "medium"},
+		{Entry{Severity: []Severity{{Type: "CVSS_V3", Score: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"}}}, "critical"},
+		{Entry{Severity: []Severity{{Type: "CVSS_V3", Score: "CVSS:3.1/AV:N/AC:H/PR:N/UI:R/S:U/C:L/I:N/A:N"}}}, "low"},
+		{Entry{}, ""},
+	}
+	for _, tt := range tests {
+		if got := tt.entry.SeverityLevel(); got != tt.want {
+			t.Errorf("SeverityLevel(%+v) = %q, want %q", tt.entry, got, tt.want)
+		}
+	}
+}
+
+func TestCVSS3Score(t *testing.T) {
+	tests := map[string]float64{
+		"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H": 9.8,
+		"CVSS:3.1/AV:N/AC:L/PR:N/UI:R/S:C/C:L/I:L/A:N": 6.1,
+		"CVSS:3.0/AV:L/AC:L/PR:L/UI:N/S:U/C:H/I:N/A:N": 5.5,
+		"CVSS:3.1/AV:N/AC:L/PR:H/UI:N/S:C/C:H/I:H/A:H": 9.1,
+		"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:N/I:N/A:N": 0,
+	}
+	for vector, want := range tests {
+		got, err := CVSS3Score(vector)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("CVSS3Score(%s) = %v, want %v", vector, got, want)
+		}
+	}
+
+	if _, err := CVSS3Score("CVSS:2.0/AV:N"); err == nil {
+		t.Error("accepted a CVSS v2 vector")
+	}
+}
+
+func TestDependenciesGo(t *testing.T) {
+	root := t.TempDir()
+	gomod := `module example.com/app
+
+go 1.21
+
+require (
+	example.com/web v1.0.0
+	example.com/forked v1.0.0
+	example.com/local v1.0.0
+)
+
+replace example.com/forked => example.com/fork v1.5.0
+
+replace example.com/local => ../local
+`
+	gosum := `example.com/web v1.0.0 h1:aaa=
+example.com/web v1.0.0/go.mod h1:bbb=
+example.com/old v1.2.0 h1:ccc=
+example.com/old v1.10.0 h1:ddd=
+example.com/old v1.11.0/go.mod h1:eee=
+`
+	if err := os.WriteFile(filepath.Join(root, "go.mod"), []byte(gomod), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "go.sum"), []byte(gosum), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	deps, err := Dependencies(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Since Go 1.17 go.mod lists the whole build, so go.sum is not read
+	want := []Dependency{
+		{Ecosystem: "Go", Name: "example.com/web", Version: "v1.0.0", File: "go.mod", Line: 6},
+		{Ecosystem: "Go", Name: "example.com/fork", Version: "v1.5.0", File: "go.mod", Line: 7},
+	}
+	if !reflect.DeepEqual(deps, want) {
+		t.Errorf("Dependencies = %+v, want %+v", deps, want)
+	}
+
+	// Before, modules only in go.sum are inferred at their highest version
+	gomod = strings.Replace(gomod, "go 1.21", "go 1.16", 1)
+	if err := os.WriteFile(filepath.Join(root, "go.mod"), []byte(gomod), 0644); err != nil {
+		t.Fatal(err)
+	}
+	deps, err = Dependencies(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = append(want, Dependency{Ecosystem: "Go", Name: "example.com/old", Version: "v1.10.0", File: "go.sum", Line: 4, Inferred: true})
+	if !reflect.DeepEqual(deps, want) {
+		t.Errorf("Dependencies = %+v, want %+v", deps, want)
+	}
+
+	if deps, err := Dependencies(t.TempDir()); err != nil || len(deps) != 0 {
+		t.Errorf("Dependencies without manifests = %v, %v", deps, err)
+	}
+}
@@ -10,7 +10,7 @@ echo "=== Sentinel-AI Setup ==="
 # Check if Go is installed
 if ! command -v go &> /dev/null; then
     echo "Installing Go..."
-    curl -L https://go.dev/dl/go1.21.5.linux-amd64.tar.gz | sudo tar -xzC /usr/local
+    curl -L https://go.dev/dl/go1.25.0.linux-amd64.tar.gz | sudo tar -xzC /usr/local
     export PATH=$PATH:/usr/local/go/bin
 else
     echo "Go is already installed: $(go version)"